@@ -48,8 +48,11 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 
 	cmd.Flags().String("out-file", "", "Save output to a file, helpful with format flag")
 	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable with --terraform-force-cli")
+	cmd.Flags().Bool("hcl-diagnostics", false, "Report values that are unresolved or unknown after evaluating Terraform directories, and the resources they affect")
+	cmd.Flags().Bool("include-attributes", false, "Include the Terraform attributes of each resource in the JSON output, so comment policies can check them")
 	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table", "html"})
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().StringSlice("group-by", nil, "Comma separated list of keys to group costs by: tag:<name>,resource_type,region,module")
 
 	// This is deprecated and will show a warning if used without --terraform-force-cli
 	_ = cmd.Flags().MarkHidden("terraform-use-state")
@@ -1,14 +1,10 @@
 package main
 
 import (
-	"context"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 
-	"github.com/open-policy-agent/opa/v1/ast"
-	"github.com/open-policy-agent/opa/v1/rego"
 	"github.com/spf13/cobra"
 
 	"github.com/infracost/infracost/internal/clierror"
@@ -20,8 +16,8 @@ import (
 func commentCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "comment",
-		Short: "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
-		Long:  "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
+		Short: "Post an Infracost comment to GitHub, GitLab, Azure Repos, Bitbucket or Gitea",
+		Long:  "Post an Infracost comment to GitHub, GitLab, Azure Repos, Bitbucket or Gitea",
 		Example: `  Update the Infracost comment on a GitHub pull request:
 
       infracost comment github --repo my-org/my-repo --pull-request 3 --path infracost.json --behavior update --github-token $GITHUB_TOKEN
@@ -39,10 +35,13 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 		},
 	}
 
-	cmds := []*cobra.Command{commentGitHubCmd(ctx), commentGitLabCmd(ctx), commentAzureReposCmd(ctx), commentBitbucketCmd(ctx)}
+	cmds := []*cobra.Command{commentGitHubCmd(ctx), commentGitLabCmd(ctx), commentAzureReposCmd(ctx), commentBitbucketCmd(ctx), commentGiteaCmd(ctx)}
 	for _, subCmd := range cmds {
 		subCmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+		subCmd.Flags().String("previous-path", "", "Path to Infracost JSON file of a previous run, policies can use it as input.previous")
+		subCmd.Flags().String("config-file", "", "Path to Infracost config file, resources are checked against its tag_policy and guardrails")
 		subCmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
+		subCmd.Flags().StringSlice("group-by", nil, "Group costs in the comment by tag:<name>, resource_type, region or module")
 		subCmd.Flags().Bool("show-changed", false, "Show only projects in the table that have code changes")
 		_ = subCmd.Flags().MarkHidden("show-changed")
 		subCmd.Flags().Bool("skip-no-diff", false, "Skip posting comment if there are no resource changes. Only applies to update, hide-and-new, and delete-and-new behaviors")
@@ -54,19 +53,46 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 	return cmd
 }
 
+// commentOutput is the markdown of a comment and the results it was built from.
+type commentOutput struct {
+	body           []byte
+	hasDiff        bool
+	combined       output.Root
+	policyChecks   output.PolicyCheck
+	guardrailCheck output.GuardrailCheck
+}
+
 func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string, mdOpts output.MarkdownOptions) ([]byte, bool, error) {
+	out, err := buildCommentOutput(cmd, ctx, paths, mdOpts)
+	if err != nil {
+		return nil, out.hasDiff, err
+	}
+
+	if out.policyChecks.HasFailed() {
+		return out.body, out.hasDiff, out.policyChecks.Failures
+	}
+	if len(out.guardrailCheck.BlockingFailures) > 0 {
+		return out.body, out.hasDiff, out.guardrailCheck.BlockingFailures
+	}
+
+	return out.body, out.hasDiff, nil
+}
+
+// buildCommentOutput builds the markdown of the comment from the Infracost
+// JSON files after running the policy, tag policy and guardrail checks.
+func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []string, mdOpts output.MarkdownOptions) (commentOutput, error) {
 	hasDiff := false
 
 	inputs, err := output.LoadPaths(paths)
 	if err != nil {
-		return nil, hasDiff, err
+		return commentOutput{hasDiff: hasDiff}, err
 	}
 
 	combined, err := output.Combine(inputs)
 	if errors.As(err, &clierror.WarningError{}) {
 		ui.PrintWarningf(cmd.ErrOrStderr(), "%s", err.Error())
 	} else if err != nil {
-		return nil, hasDiff, err
+		return commentOutput{hasDiff: hasDiff}, err
 	}
 
 	hasDiff = combined.HasDiff()
@@ -84,16 +110,12 @@ func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string
 		}
 	}
 
-	var policyChecks output.PolicyCheck
-	policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
-	if len(policyPaths) > 0 {
-		policyChecks, err = queryPolicy(policyPaths, combined)
-		if err != nil {
-			return nil, hasDiff, err
-		}
-
-		ctx.SetContextValue("passedPolicyCount", len(policyChecks.Passed))
-		ctx.SetContextValue("failedPolicyCount", len(policyChecks.Failures))
+	policyChecks, localGuardrailCheck, err := runLocalChecks(cmd, ctx, combined)
+	if err != nil {
+		return commentOutput{hasDiff: hasDiff}, err
+	}
+	if localGuardrailCheck.TotalChecked > 0 {
+		guardrailCheck = guardrailCheck.Add(localGuardrailCheck)
 	}
 
 	opts := output.Options{
@@ -105,20 +127,79 @@ func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string
 	}
 	opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
 	opts.ShowOnlyChanges, _ = cmd.Flags().GetBool("show-changed")
+	opts.GroupBy, _ = cmd.Flags().GetStringSlice("group-by")
+	if err := output.ValidateGroupBy(opts.GroupBy); err != nil {
+		return commentOutput{hasDiff: hasDiff}, err
+	}
 
 	b, err := output.ToMarkdown(combined, opts, mdOpts)
 	if err != nil {
-		return nil, hasDiff, err
+		return commentOutput{hasDiff: hasDiff}, err
+	}
+
+	return commentOutput{
+		body:           b,
+		hasDiff:        hasDiff,
+		combined:       combined,
+		policyChecks:   policyChecks,
+		guardrailCheck: guardrailCheck,
+	}, nil
+}
+
+// runLocalChecks runs the checks that don't need Infracost Cloud against the
+// combined output: the policies from --policy-path, and the tag policy and
+// guardrails from --config-file.
+func runLocalChecks(cmd *cobra.Command, ctx *config.RunContext, combined output.Root) (policyChecks output.PolicyCheck, guardrailCheck output.GuardrailCheck, err error) {
+	policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+	if len(policyPaths) > 0 {
+		var previous *output.Root
+		if previousPath, _ := cmd.Flags().GetString("previous-path"); previousPath != "" {
+			prev, err := output.Load(previousPath)
+			if err != nil {
+				return policyChecks, guardrailCheck, fmt.Errorf("Unable to load previous run: %w", err)
+			}
+			previous = &prev
+		}
+
+		input, err := policyInput(combined, commentPolicyDocuments(ctx, previous))
+		if err != nil {
+			return policyChecks, guardrailCheck, err
+		}
+
+		policyChecks, err = queryPolicy(policyPaths, input)
+		if err != nil {
+			return policyChecks, guardrailCheck, err
+		}
+
+		ctx.SetContextValue("passedPolicyCount", len(policyChecks.Passed))
+		ctx.SetContextValue("failedPolicyCount", len(policyChecks.Failures))
+		ctx.SetContextValue("warnedPolicyCount", len(policyChecks.Warnings))
 	}
 
-	if policyChecks.HasFailed() {
-		return b, hasDiff, policyChecks.Failures
+	if configFile, _ := cmd.Flags().GetString("config-file"); configFile != "" {
+		err = ctx.Config.LoadFromConfigFile(configFile)
+		if err != nil {
+			return policyChecks, guardrailCheck, err
+		}
 	}
-	if len(guardrailCheck.BlockingFailures) > 0 {
-		return b, hasDiff, guardrailCheck.BlockingFailures
+
+	if ctx.Config.TagPolicy != nil {
+		tagChecks := output.CheckTagPolicy(combined, ctx.Config.TagPolicy)
+		policyChecks.Enabled = true
+		policyChecks.Failures = append(policyChecks.Failures, tagChecks.Failures...)
+		policyChecks.Passed = append(policyChecks.Passed, tagChecks.Passed...)
+
+		ctx.SetContextValue("failedTagPolicyCount", len(tagChecks.Failures))
+	}
+
+	if len(ctx.Config.Guardrails) > 0 {
+		guardrailCheck = output.CheckGuardrails(combined, ctx.Config.Guardrails)
+
+		ctx.SetContextValue("localGuardrailCount", len(ctx.Config.Guardrails))
+		ctx.SetContextValue("failedLocalGuardrailCount", len(guardrailCheck.CommentableFailures))
 	}
 
-	return b, hasDiff, nil
+	return policyChecks, guardrailCheck, nil
 }
 
 type PRNumber int
@@ -145,73 +226,3 @@ func (p *PRNumber) String() string {
 func (p *PRNumber) Type() string {
 	return "int"
 }
-
-func queryPolicy(policyPaths []string, input output.Root) (output.PolicyCheck, error) {
-	checks := output.PolicyCheck{
-		Enabled: true,
-	}
-
-	inputValue, err := ast.InterfaceToValue(input)
-	if err != nil {
-		return checks, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
-	}
-
-	ctx := context.Background()
-	r := rego.New(
-		rego.Query("data.infracost.deny"),
-		rego.ParsedInput(inputValue),
-		rego.Load(policyPaths, func(abspath string, info os.FileInfo, depth int) bool {
-			return false
-		}),
-	)
-	pq, err := r.PrepareForEval(ctx)
-	if err != nil {
-		return checks, fmt.Errorf("Unable to query provided policies: %s", err.Error())
-	}
-
-	res, err := pq.Eval(ctx)
-	if err != nil {
-		return checks, err
-	}
-
-	if len(res) == 0 {
-		return checks, fmt.Errorf("The provided polices returned no valid data.infracost.deny rules. Please check that the policies are formatted correctly.")
-	}
-
-	for _, e := range res[0].Expressions {
-		switch v := e.Value.(type) {
-		case map[string]interface{}:
-			readPolicyOut(v, &checks)
-		case []interface{}:
-			for _, ii := range v {
-				if m, ok := ii.(map[string]interface{}); ok {
-					readPolicyOut(m, &checks)
-				}
-			}
-		}
-	}
-
-	return checks, nil
-}
-
-func readPolicyOut(v map[string]interface{}, checks *output.PolicyCheck) {
-	if _, ok := v["msg"]; !ok {
-		checks.Failures = append(checks.Failures, "Policy rule invalid as it did not contain {msg: string} property in output object. Please edit rule output object.")
-		return
-	}
-	msg := v["msg"].(string)
-
-	if _, ok := v["failed"]; !ok {
-		checks.Failures = append(checks.Failures, fmt.Sprintf("Policy rule: [%s] did not contain {failed: bool} output property. Please edit rule output object.", msg))
-		return
-	}
-
-	failed, _ := v["failed"].(bool)
-
-	if failed {
-		checks.Failures = append(checks.Failures, msg)
-		return
-	}
-
-	checks.Passed = append(checks.Passed, msg)
-}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/comment"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+var validCommentGiteaBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new"}
+
+func commentGiteaCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gitea",
+		Short: "Post an Infracost comment to Gitea or Forgejo",
+		Long:  "Post an Infracost comment to Gitea or Forgejo",
+		Example: `  Update comment on a pull request:
+
+      infracost comment gitea --gitea-server-url https://gitea.example.com --repo my-org/my-repo --pull-request 3 --path infracost.json --gitea-token $GITEA_TOKEN
+
+  Hide previous comments and post a new comment to a pull request:
+
+      infracost comment gitea --gitea-server-url https://gitea.example.com --repo my-org/my-repo --pull-request 3 --path infracost.json --behavior hide-and-new --gitea-token $GITEA_TOKEN`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx.SetContextValue("platform", "gitea")
+
+			serverURL, _ := cmd.Flags().GetString("gitea-server-url")
+			token, _ := cmd.Flags().GetString("gitea-token")
+			tag, _ := cmd.Flags().GetString("tag")
+			extra := comment.GiteaExtra{
+				ServerURL: serverURL,
+				Token:     token,
+				Tag:       tag,
+			}
+
+			prNumber, _ := cmd.Flags().GetInt("pull-request")
+			repo, _ := cmd.Flags().GetString("repo")
+
+			behavior, _ := cmd.Flags().GetString("behavior")
+			if behavior != "" && !contains(validCommentGiteaBehaviors, behavior) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--behavior only supports %s", strings.Join(validCommentGiteaBehaviors, ", "))
+			}
+			ctx.SetContextValue("behavior", behavior)
+
+			ctx.SetContextValue("targetType", "pull-request")
+
+			commentHandler, err := comment.NewGiteaPRHandler(ctx.Context(), repo, strconv.Itoa(prNumber), extra)
+			if err != nil {
+				return err
+			}
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			body, hasDiff, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
+				WillUpdate:          behavior == "update",
+				WillReplace:         behavior == "delete-and-new",
+				IncludeFeedbackLink: true,
+			})
+			var policyFailure output.PolicyCheckFailures
+			var guardrailFailure output.GuardrailFailures
+			if err != nil {
+				if v, ok := err.(output.PolicyCheckFailures); ok {
+					policyFailure = v
+				} else if v, ok := err.(output.GuardrailFailures); ok {
+					guardrailFailure = v
+				} else {
+					return err
+				}
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if !dryRun {
+				skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff")
+
+				posted, err := commentHandler.CommentWithBehavior(ctx.Context(), !hasDiff && skipNoDiff, behavior, string(body))
+				if err != nil {
+					return err
+				}
+
+				pricingClient := apiclient.NewPricingAPIClient(ctx)
+				err = pricingClient.AddEvent("infracost-comment", ctx.EventEnv())
+				if err != nil {
+					logging.Logger.WithError(err).Error("could not report infracost-comment event")
+				}
+
+				if posted {
+					cmd.Println("Comment posted to Gitea")
+				} else {
+					cmd.Println("Comment not posted to Gitea")
+				}
+			} else {
+				cmd.Println(string(body))
+				cmd.Println("Comment not posted to Gitea (--dry-run was specified)")
+			}
+
+			if policyFailure != nil {
+				return policyFailure
+			}
+			if guardrailFailure != nil {
+				return guardrailFailure
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
+  update (default)  Update latest comment
+  new               Create a new comment
+  hide-and-new      Collapse previous matching comments and create a new comment
+  delete-and-new    Delete previous matching comments and create a new comment`)
+	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validCommentGiteaBehaviors, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().String("gitea-server-url", "", "Gitea or Forgejo server URL")
+	_ = cmd.MarkFlagRequired("gitea-server-url")
+	cmd.Flags().String("gitea-token", "", "Gitea or Forgejo access token")
+	_ = cmd.MarkFlagRequired("gitea-token")
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	var prNumber PRNumber
+	cmd.Flags().Var(&prNumber, "pull-request", "Pull request number to post comment on")
+	_ = cmd.MarkFlagRequired("pull-request")
+	cmd.Flags().String("repo", "", "Repository in format owner/repo")
+	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().String("tag", "", "Customize hidden markdown tag used to detect comments posted by Infracost")
+	cmd.Flags().Bool("dry-run", false, "Generate comment without actually posting to Gitea")
+
+	return cmd
+}
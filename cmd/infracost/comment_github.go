@@ -15,7 +15,11 @@ import (
 	"github.com/infracost/infracost/internal/ui"
 )
 
-var validCommentGitHubBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new"}
+var validCommentGitHubBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new", "check-run"}
+
+// githubCheckRunBehavior posts the comment as the summary of a check run
+// instead of as a comment.
+const githubCheckRunBehavior = "check-run"
 
 func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
@@ -48,6 +52,22 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 			prNumber, _ := cmd.Flags().GetInt("pull-request")
 			repo, _ := cmd.Flags().GetString("repo")
 
+			behavior, _ := cmd.Flags().GetString("behavior")
+			if behavior != "" && !contains(validCommentGitHubBehaviors, behavior) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--behavior only supports %s", strings.Join(validCommentGitHubBehaviors, ", "))
+			}
+			ctx.SetContextValue("behavior", behavior)
+
+			if behavior == githubCheckRunBehavior {
+				if prNumber == 0 && commit == "" {
+					ui.PrintUsage(cmd)
+					return fmt.Errorf("either --commit or --pull-request is required")
+				}
+
+				return commentGitHubCheckRun(cmd, ctx, repo, prNumber, commit, extra)
+			}
+
 			var commentHandler *comment.CommentHandler
 			if prNumber != 0 {
 				ctx.SetContextValue("targetType", "pull-request")
@@ -68,13 +88,6 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 				return fmt.Errorf("either --commit or --pull-request is required")
 			}
 
-			behavior, _ := cmd.Flags().GetString("behavior")
-			if behavior != "" && !contains(validCommentGitHubBehaviors, behavior) {
-				ui.PrintUsage(cmd)
-				return fmt.Errorf("--behavior only supports %s", strings.Join(validCommentGitHubBehaviors, ", "))
-			}
-			ctx.SetContextValue("behavior", behavior)
-
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			body, hasDiff, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
@@ -137,7 +150,8 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
   update (default)  Update latest comment
   new               Create a new comment
   hide-and-new      Hide previous matching comments and create a new comment
-  delete-and-new    Delete previous matching comments and create a new comment`)
+  delete-and-new    Delete previous matching comments and create a new comment
+  check-run         Create a check run with the comment as its summary and annotations on changed resources`)
 	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validCommentGitHubBehaviors, cobra.ShellCompDirectiveDefault
 	})
@@ -157,3 +171,94 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 
 	return cmd
 }
+
+// commentGitHubCheckRun creates a check run with the comment as its summary.
+// The conclusion fails if the policy or guardrail checks block, and each
+// resource that changes the cost is annotated on the lines it's defined on.
+func commentGitHubCheckRun(cmd *cobra.Command, ctx *config.RunContext, repo string, prNumber int, commit string, extra comment.GitHubExtra) error {
+	paths, _ := cmd.Flags().GetStringArray("path")
+
+	out, err := buildCommentOutput(cmd, ctx, paths, output.MarkdownOptions{
+		IncludeFeedbackLink: true,
+		MaxMessageSize:      output.GitHubCheckRunMaxSummarySize,
+	})
+	if err != nil {
+		return err
+	}
+
+	checkRun := comment.GitHubCheckRun{
+		Name:       "Infracost",
+		Conclusion: githubCheckRunConclusion(out),
+		Title:      output.CostChangeTitle(out.combined),
+		Summary:    string(out.body),
+	}
+	if extra.Tag != "" {
+		checkRun.Name = fmt.Sprintf("Infracost (%s)", extra.Tag)
+	}
+
+	for _, a := range output.ToAnnotations(out.combined) {
+		checkRun.Annotations = append(checkRun.Annotations, comment.GitHubCheckRunAnnotation{
+			Path:      a.Path,
+			StartLine: a.StartLine,
+			EndLine:   a.EndLine,
+			Level:     a.Level,
+			Title:     a.Title,
+			Message:   a.Message,
+		})
+	}
+
+	ctx.SetContextValue("checkRunAnnotationCount", len(checkRun.Annotations))
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !dryRun {
+		handler, err := comment.NewGitHubCheckRunHandler(ctx.Context(), repo, prNumber, commit, extra)
+		if err != nil {
+			return err
+		}
+
+		_, err = handler.CreateCheckRun(ctx.Context(), checkRun)
+		if err != nil {
+			return err
+		}
+
+		pricingClient := apiclient.NewPricingAPIClient(ctx)
+		err = pricingClient.AddEvent("infracost-comment", ctx.EventEnv())
+		if err != nil {
+			logging.Logger.WithError(err).Error("could not report infracost-comment event")
+		}
+
+		cmd.Println("Check run posted to GitHub")
+	} else {
+		cmd.Println(string(out.body))
+		cmd.Printf("Check run conclusion: %s\n", checkRun.Conclusion)
+		for _, a := range checkRun.Annotations {
+			cmd.Printf("  %s:%d-%d %s: %s\n", a.Path, a.StartLine, a.EndLine, a.Level, a.Message)
+		}
+		cmd.Println("Check run not posted to GitHub (--dry-run was specified)")
+	}
+
+	if out.policyChecks.HasFailed() {
+		cmd.Printf("\n")
+		return out.policyChecks.Failures
+	}
+	if len(out.guardrailCheck.BlockingFailures) > 0 {
+		cmd.Printf("\n")
+		return out.guardrailCheck.BlockingFailures
+	}
+
+	return nil
+}
+
+// githubCheckRunConclusion returns failure if the policy or guardrail checks
+// block, neutral if they have warnings and success otherwise.
+func githubCheckRunConclusion(out commentOutput) string {
+	if out.policyChecks.HasFailed() || len(out.guardrailCheck.BlockingFailures) > 0 {
+		return "failure"
+	}
+
+	if len(out.policyChecks.Warnings) > 0 || len(out.guardrailCheck.CommentableFailures) > 0 {
+		return "neutral"
+	}
+
+	return "success"
+}
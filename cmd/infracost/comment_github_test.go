@@ -6,9 +6,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"path"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
 
@@ -43,6 +47,80 @@ func TestCommentGitHubShowChangedProjects(t *testing.T) {
 		nil)
 }
 
+func TestCommentGitHubTagPolicyFailure(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName,
+		[]string{"comment", "github", "--github-token", "abc", "--repo", "test/test", "--commit", "5", "--path", "./testdata/terraform_v0.14_breakdown.json", "--config-file", path.Join("./testdata", testName, "infracost.yml"), "--dry-run"},
+		nil)
+}
+
+func TestCommentGitHubLocalGuardrails(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName,
+		[]string{"comment", "github", "--github-token", "abc", "--repo", "test/test", "--commit", "5", "--path", "./testdata/terraform_v0.14_breakdown.json", "--config-file", path.Join("./testdata", testName, "infracost.yml"), "--dry-run"},
+		nil)
+}
+
+func TestCommentGitHubPolicies(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName,
+		[]string{"comment", "github", "--github-token", "abc", "--repo", "test/test", "--commit", "5", "--path", "./testdata/terraform_v0.14_breakdown.json", "--policy-path", path.Join("./testdata", testName, "policy.rego"), "--dry-run"},
+		nil)
+}
+
+func TestCommentGitHubCheckRunDryRun(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"comment", "github", "--github-token", "abc", "--repo", "test/test", "--commit", "5", "--path", "./testdata/comment_git_hub_check_run/infracost.json", "--behavior", "check-run", "--dry-run"},
+		nil)
+}
+
+func TestCommentGitHubCheckRun(t *testing.T) {
+	var checkRuns []map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/test/test/pulls/5":
+			fmt.Fprintln(w, `{"number": 5, "head": {"sha": "abc123"}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/test/test/check-runs":
+			var checkRun map[string]interface{}
+			err := json.NewDecoder(r.Body).Decode(&checkRun)
+			require.NoError(t, err)
+			checkRuns = append(checkRuns, checkRun)
+
+			fmt.Fprintln(w, `{"id": 1, "html_url": "https://github.com/test/test/runs/1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"comment", "github", "--github-token", "abc", "--repo", "test/test", "--pull-request", "5", "--path", "./testdata/comment_git_hub_check_run/infracost.json", "--behavior", "check-run", "--github-api-url", ts.URL},
+		nil)
+
+	require.NotEmpty(t, checkRuns)
+	checkRun := checkRuns[0]
+	assert.Equal(t, "Infracost", checkRun["name"])
+	assert.Equal(t, "abc123", checkRun["head_sha"])
+	assert.Equal(t, "completed", checkRun["status"])
+	assert.Equal(t, "success", checkRun["conclusion"])
+
+	out := checkRun["output"].(map[string]interface{})
+	assert.Equal(t, "Monthly cost will increase by $40.56 (+100%) ↑", out["title"])
+	assert.Contains(t, out["summary"], "Infracost estimate: **monthly cost will increase by $40.56")
+
+	annotations := out["annotations"].([]interface{})
+	require.Len(t, annotations, 7)
+	assert.Equal(t, map[string]interface{}{
+		"path":             "main.tf",
+		"start_line":       float64(11),
+		"end_line":         float64(19),
+		"annotation_level": "warning",
+		"title":            "aws_instance.instance_2",
+		"message":          "aws_instance.instance_2 changes the monthly cost by +$4.60 ($0.00 → $4.60)",
+	}, annotations[0])
+}
+
 var ghZeroCommentsResponse = `{ "data": { "repository": { "pullRequest": { "comments": { "nodes": [], "pageInfo": { "endCursor": "abc", "hasNextPage": false }}}}}}`
 var ghOneMatchingCommentResponse = `{ "data": { "repository": { "pullRequest": { "comments": { "nodes": [ 
             { "id": "123", "body": "infracomment body here, followed by tag: [//]: <> (infracost-comment)" }
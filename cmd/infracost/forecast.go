@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+const maxForecastMonths = 120
+
+type forecastCmd struct {
+	TerraformVarFiles  []string
+	TerraformVars      []string
+	TerraformWorkspace string
+
+	Path       string
+	ConfigFile string
+	UsageFile  string
+	Months     int
+	StartMonth string
+
+	cmd *cobra.Command
+}
+
+func (f forecastCmd) loadRunFlags(cfg *config.Config) error {
+	if f.ConfigFile == "" && f.Path == "" {
+		ui.PrintUsage(f.cmd)
+		return errors.New("Please provide either a --path or --config-file")
+	}
+
+	if f.ConfigFile != "" && (f.Path != "" || f.UsageFile != "" || len(f.TerraformVars) > 0 || len(f.TerraformVarFiles) > 0 || f.TerraformWorkspace != "") {
+		ui.PrintUsage(f.cmd)
+		return errors.New("--config-file flag cannot be used with the following flags: --path, --terraform-*, --usage-file")
+	}
+
+	if f.Months < 1 || f.Months > maxForecastMonths {
+		return fmt.Errorf("--months must be between 1 and %d", maxForecastMonths)
+	}
+
+	if f.StartMonth != "" {
+		if _, err := time.Parse("2006-01", f.StartMonth); err != nil {
+			return fmt.Errorf("--start-month must be in the format YYYY-MM, got %s", f.StartMonth)
+		}
+	}
+
+	if f.ConfigFile != "" {
+		err := cfg.LoadFromConfigFile(f.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		cfg.ConfigFilePath = f.ConfigFile
+		return nil
+	}
+
+	projectCfg := cfg.Projects[0]
+	cfg.RootPath = f.Path
+	projectCfg.Path = f.Path
+	projectCfg.TerraformVarFiles = f.TerraformVarFiles
+	projectCfg.TerraformVars = tfVarsToMap(f.TerraformVars)
+	projectCfg.TerraformWorkspace = f.TerraformWorkspace
+	projectCfg.UsageFile = f.UsageFile
+
+	return nil
+}
+
+func (f forecastCmd) run(runCtx *config.RunContext) error {
+	err := f.loadRunFlags(runCtx.Config)
+	if err != nil {
+		return err
+	}
+
+	forecast := output.Forecast{
+		Currency:   runCtx.Config.Currency,
+		StartMonth: f.StartMonth,
+	}
+
+	for _, projectCfg := range runCtx.Config.Projects {
+		projects, err := f.forecastProject(runCtx, projectCfg)
+		if err != nil {
+			return err
+		}
+
+		forecast.Projects = append(forecast.Projects, projects...)
+	}
+
+	var start time.Time
+	if f.StartMonth != "" {
+		start, _ = time.Parse("2006-01", f.StartMonth)
+	}
+
+	total := decimal.Zero
+	for month := 0; month < f.Months; month++ {
+		monthlyCost := decimal.Zero
+		for _, p := range forecast.Projects {
+			if month < len(p.Months) && p.Months[month].MonthlyCost != nil {
+				monthlyCost = monthlyCost.Add(*p.Months[month].MonthlyCost)
+			}
+		}
+		total = total.Add(monthlyCost)
+
+		m := output.ForecastMonth{
+			Month:       month + 1,
+			MonthlyCost: decimalPtr(monthlyCost),
+		}
+		if !start.IsZero() {
+			m.Period = start.AddDate(0, month, 0).Format("2006-01")
+		}
+
+		forecast.Months = append(forecast.Months, m)
+	}
+	forecast.TotalCost = decimalPtr(total)
+
+	if runCtx.Config.Format == "json" {
+		b, err := json.MarshalIndent(forecast, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		f.cmd.Println(string(b))
+		return nil
+	}
+
+	f.cmd.Print(string(output.ToForecastTable(forecast)))
+
+	return nil
+}
+
+// forecastProject calculates the costs of the projects at the project config
+// path for each month. The resources are loaded again for each month since
+// some resources read their usage when they are loaded, and the costs are
+// calculated from that month's usage so that tiered prices are applied to
+// the volumes for that month.
+func (f forecastCmd) forecastProject(runCtx *config.RunContext, projectCfg *config.Project) ([]output.ForecastProject, error) {
+	projectCtx := config.NewProjectContext(runCtx, projectCfg, log.Fields{})
+
+	provider, err := providers.Detect(projectCtx, false)
+	if v, ok := err.(*providers.ValidationError); ok {
+		if v.Warn() == nil {
+			return nil, err
+		}
+		ui.PrintWarning(runCtx.ErrWriter, *v.Warn())
+	} else if err != nil {
+		return nil, fmt.Errorf("Could not detect path type for %s: %w", projectCfg.Path, err)
+	}
+
+	usageFile := usage.NewBlankUsageFile()
+	if projectCfg.UsageFile != "" {
+		usageFile, err = usage.LoadUsageFile(projectCfg.UsageFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mergeWildcardUsages(usageFile)
+
+	if !usageFile.HasTimeSeries() {
+		ui.PrintWarningf(runCtx.ErrWriter, "No time series usage values found for %s, the costs will be the same every month\n", projectCfg.Path)
+	}
+
+	var forecastProjects []output.ForecastProject
+
+	for month := 0; month < f.Months; month++ {
+		// If the usage doesn't change then there's no need to calculate the costs again
+		if month > 0 && !usageFile.HasTimeSeries() {
+			for i := range forecastProjects {
+				m := forecastProjects[i].Months[0]
+				m.Month = month + 1
+				forecastProjects[i].Months = append(forecastProjects[i].Months, m)
+			}
+			continue
+		}
+
+		projects, err := f.forecastMonth(runCtx, provider, usageFile, month)
+		if err != nil {
+			return nil, err
+		}
+
+		forecastProjects = output.AddForecastMonth(forecastProjects, projects, month)
+	}
+
+	for i, p := range forecastProjects {
+		total := decimal.Zero
+		for _, m := range p.Months {
+			total = total.Add(*m.MonthlyCost)
+		}
+		forecastProjects[i].TotalCost = decimalPtr(total)
+	}
+
+	return forecastProjects, nil
+}
+
+// forecastMonth returns the costs of each project for a single month.
+func (f forecastCmd) forecastMonth(runCtx *config.RunContext, provider schema.Provider, usageFile *usage.UsageFile, month int) ([]output.ForecastProject, error) {
+	projects, err := provider.LoadResources(usageFile.ToUsageDataMapForMonth(month))
+	if err != nil {
+		return nil, err
+	}
+
+	schema.BuildResources(projects, nil)
+
+	forecastProjects := make([]output.ForecastProject, 0, len(projects))
+	for _, project := range projects {
+		if err := prices.PopulatePrices(runCtx, project); err != nil {
+			return nil, err
+		}
+
+		schema.CalculateCosts(project)
+
+		monthlyCost := decimal.Zero
+		resources := make([]output.ForecastResource, 0, len(project.Resources))
+		for _, r := range project.Resources {
+			if r.IsSkipped || r.MonthlyCost == nil {
+				continue
+			}
+
+			monthlyCost = monthlyCost.Add(*r.MonthlyCost)
+			resources = append(resources, output.ForecastResource{
+				Name:        r.Name,
+				MonthlyCost: r.MonthlyCost,
+			})
+		}
+
+		forecastProjects = append(forecastProjects, output.ForecastProject{
+			Name: project.Name,
+			Months: []output.ForecastProjectMonth{{
+				Month:       month + 1,
+				MonthlyCost: decimalPtr(monthlyCost),
+				Resources:   resources,
+			}},
+		})
+	}
+
+	return forecastProjects, nil
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func forecastCommand(ctx *config.RunContext) *cobra.Command {
+	var forecast forecastCmd
+
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Show a month-by-month cost forecast from time series usage",
+		Long: `Show a month-by-month cost forecast from time series usage.
+
+Usage values in the usage file can change over time by setting them to a time series, e.g.
+
+  resource_usage:
+    aws_lambda_function.api:
+      monthly_requests:
+        start: 1000000  # Requests in the first month
+        growth: 5%      # Compound growth each month
+        increment: 0    # Linear increase each month
+
+or to an explicit series, where the last value is used for any remaining months:
+
+      monthly_requests:
+        series: [1000000, 2000000, 4000000]
+
+The costs for each month are calculated from that month's usage, so tiered prices are
+applied to the volumes for that month. Other commands use the value for the first month.`,
+		Example: `  Forecast the costs of a Terraform directory for the next 12 months:
+
+      infracost forecast --path /code --usage-file infracost-usage.yml --months 12
+
+  Forecast the costs of all projects in a config file as JSON:
+
+      infracost forecast --config-file infracost.yml --months 24 --start-month 2025-01 --format json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx.Config.Format, _ = cmd.Flags().GetString("format")
+
+			return forecast.run(ctx)
+		},
+	}
+
+	forecast.cmd = cmd
+	cmd.Flags().StringSliceVar(&forecast.TerraformVarFiles, "terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
+	cmd.Flags().StringSliceVar(&forecast.TerraformVars, "terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().StringVar(&forecast.TerraformWorkspace, "terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+
+	cmd.Flags().StringVarP(&forecast.Path, "path", "p", "", "Path to the Terraform directory or JSON/plan file")
+	cmd.Flags().StringVar(&forecast.ConfigFile, "config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
+	cmd.Flags().StringVar(&forecast.UsageFile, "usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	cmd.Flags().IntVar(&forecast.Months, "months", 12, "Number of months to forecast")
+	cmd.Flags().StringVar(&forecast.StartMonth, "start-month", "", "Month of the first month in the forecast, in the format YYYY-MM, used to label the months")
+	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table"})
+
+	_ = cmd.MarkFlagFilename("path", "json", "tf")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestForecastHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"forecast", "--help"}, nil)
+}
+
+func TestForecastInvalidMonths(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{
+		"forecast",
+		"--path", "./testdata/example_plan.json",
+		"--months", "0",
+	}, nil)
+}
+
+// lambdaDurationPrices are the prices of the AWS Lambda duration tiers,
+// by the start usage amount of the tier.
+var lambdaDurationPrices = map[string]string{
+	"0":           "0.0000166667",
+	"6000000000":  "0.000015",
+	"15000000000": "0.0000133334",
+}
+
+func TestForecastTieredUsage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&queries)
+
+		results := make([]string, 0, len(queries))
+		for _, q := range queries {
+			price, ok := lambdaDurationPrices[gjson.GetBytes(q, "variables.priceFilter.startUsageAmount").String()]
+			if !ok {
+				price = "0.2"
+			}
+			results = append(results, fmt.Sprintf(`{"data": {"products": [{"prices": [{"priceHash": "hash", "USD": "%s"}]}]}}`, price))
+		}
+
+		fmt.Fprintf(w, "[%s]", strings.Join(results, ","))
+	}))
+	defer ts.Close()
+
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"forecast",
+			"--path", dir,
+			"--usage-file", path.Join(dir, "infracost-usage.yml"),
+			"--months", "4",
+			"--start-month", "2025-01",
+		},
+		nil,
+		func(c *config.RunContext) {
+			c.Config.PricingAPIEndpoint = ts.URL
+		},
+	)
+}
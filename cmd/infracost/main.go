@@ -178,9 +178,14 @@ func newRootCmd(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
 	rootCmd.AddCommand(scanCommand(ctx))
+	rootCmd.AddCommand(usageCmd(ctx))
+	rootCmd.AddCommand(modulesCmd(ctx))
+	rootCmd.AddCommand(forecastCommand(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
 	rootCmd.AddCommand(uploadCmd(ctx))
 	rootCmd.AddCommand(commentCmd(ctx))
+	rootCmd.AddCommand(notifyCmd(ctx))
+	rootCmd.AddCommand(policyCmd())
 	rootCmd.AddCommand(completionCmd())
 	rootCmd.AddCommand(figAutocompleteCmd())
 
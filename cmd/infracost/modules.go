@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func modulesCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "modules",
+		Short: "Work with Terraform modules",
+		Long:  "Work with Terraform modules",
+		Example: `  Add the modules of all projects in a config file to a module mirror:
+
+      infracost modules vendor --config-file infracost.yml --mirror /mirror`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(modulesVendorCommand(ctx))
+
+	return cmd
+}
+
+type modulesVendorCmd struct {
+	TerraformVarFiles  []string
+	TerraformVars      []string
+	TerraformWorkspace string
+
+	Path       string
+	ConfigFile string
+	Mirror     string
+
+	cmd *cobra.Command
+}
+
+func (v modulesVendorCmd) loadRunFlags(cfg *config.Config) error {
+	if v.ConfigFile == "" && v.Path == "" {
+		ui.PrintUsage(v.cmd)
+		return errors.New("Either --path or --config-file must be specified")
+	}
+
+	if v.ConfigFile != "" && (v.Path != "" || len(v.TerraformVars) > 0 || len(v.TerraformVarFiles) > 0 || v.TerraformWorkspace != "") {
+		ui.PrintUsage(v.cmd)
+		return errors.New("--config-file flag cannot be used with the following flags: --path, --terraform-*")
+	}
+
+	if v.ConfigFile != "" {
+		err := cfg.LoadFromConfigFile(v.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		cfg.ConfigFilePath = v.ConfigFile
+		return nil
+	}
+
+	projectCfg := cfg.Projects[0]
+	cfg.RootPath = v.Path
+	projectCfg.Path = v.Path
+	projectCfg.TerraformVarFiles = v.TerraformVarFiles
+	projectCfg.TerraformVars = tfVarsToMap(v.TerraformVars)
+	projectCfg.TerraformWorkspace = v.TerraformWorkspace
+
+	return nil
+}
+
+func (v modulesVendorCmd) run(runCtx *config.RunContext) error {
+	err := v.loadRunFlags(runCtx.Config)
+	if err != nil {
+		return err
+	}
+
+	// Projects in a config file can share a mirror, so only load each one once.
+	var mirrorPaths []string
+	mirrors := map[string]*modules.Mirror{}
+	counts := map[string]int{}
+
+	for _, projectCfg := range runCtx.Config.Projects {
+		mirrorPath := v.mirrorPath(runCtx.Config, projectCfg)
+		if mirrorPath == "" {
+			return fmt.Errorf("No module mirror for project %s, use --mirror or set module_mirror in the config file", projectCfg.Path)
+		}
+
+		mirror, ok := mirrors[mirrorPath]
+		if !ok {
+			mirror, err = modules.LoadMirror(mirrorPath)
+			if err != nil {
+				return err
+			}
+
+			mirrors[mirrorPath] = mirror
+			counts[mirrorPath] = len(mirror.Modules)
+			mirrorPaths = append(mirrorPaths, mirrorPath)
+		}
+
+		err = v.vendorProject(runCtx, projectCfg, mirror)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, mirrorPath := range mirrorPaths {
+		mirror := mirrors[mirrorPath]
+		err = mirror.Save()
+		if err != nil {
+			return err
+		}
+
+		v.cmd.Printf("%s %s\n", ui.SuccessString("✔"), ui.BoldStringf("Vendored %d new modules to %s", len(mirror.Modules)-counts[mirrorPath], mirrorPath))
+	}
+
+	return nil
+}
+
+// mirrorPath returns the mirror to vendor the modules of the project to: the
+// --mirror flag, or else the module_mirror of the project, which is relative
+// to the config file.
+func (v modulesVendorCmd) mirrorPath(cfg *config.Config, projectCfg *config.Project) string {
+	if v.Mirror != "" {
+		return filepath.Clean(v.Mirror)
+	}
+
+	if projectCfg.ModuleMirror == "" || filepath.IsAbs(projectCfg.ModuleMirror) {
+		return projectCfg.ModuleMirror
+	}
+
+	return filepath.Join(cfg.RepoPath(), projectCfg.ModuleMirror)
+}
+
+func (v modulesVendorCmd) vendorProject(runCtx *config.RunContext, projectCfg *config.Project, mirror *modules.Mirror) error {
+	// The modules are downloaded rather than loaded from the mirror that they're
+	// being vendored to.
+	cfg := *projectCfg
+	cfg.ModuleMirror = ""
+	projectCtx := config.NewProjectContext(runCtx, &cfg, log.Fields{})
+
+	provider, err := terraform.NewHCLProvider(projectCtx, &terraform.HCLProviderConfig{SuppressLogging: true})
+	if err != nil {
+		return fmt.Errorf("Could not load Terraform project %s: %w", projectCfg.Path, err)
+	}
+
+	manifests, err := provider.LoadModules()
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		err = mirror.Add(manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func modulesVendorCommand(ctx *config.RunContext) *cobra.Command {
+	var vendor modulesVendorCmd
+
+	cmd := &cobra.Command{
+		Use:   "vendor",
+		Short: "Add the registry and remote modules of projects to a module mirror",
+		Long: `Add the registry and remote modules of projects to a module mirror.
+
+Downloads the registry and remote modules referenced by the projects and copies them to the
+module mirror directory, along with an index.json file listing them. Projects with module_mirror
+set in the config file then load modules from the mirror instead of downloading them, e.g. on
+CI runners without internet access.`,
+		Example: `  Add the modules of a Terraform directory to a module mirror:
+
+      infracost modules vendor --path /code --mirror /mirror
+
+  Add the modules of all projects in a config file to their module_mirror:
+
+      infracost modules vendor --config-file infracost.yml`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return vendor.run(ctx)
+		},
+	}
+
+	vendor.cmd = cmd
+	cmd.Flags().StringSliceVar(&vendor.TerraformVarFiles, "terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
+	cmd.Flags().StringSliceVar(&vendor.TerraformVars, "terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().StringVar(&vendor.TerraformWorkspace, "terraform-workspace", "", "Terraform workspace to use")
+
+	cmd.Flags().StringVarP(&vendor.Path, "path", "p", "", "Path to the Terraform directory")
+	cmd.Flags().StringVar(&vendor.ConfigFile, "config-file", "", "Path to Infracost config file. Cannot be used with path or terraform* flags")
+	cmd.Flags().StringVar(&vendor.Mirror, "mirror", "", "Path to the module mirror directory. Defaults to the module_mirror of each project")
+
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagDirname("path")
+	_ = cmd.MarkFlagDirname("mirror")
+
+	return cmd
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/notify"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// notifyContentTypes are the Content-Type headers sent with each output format,
+// formats that aren't listed are sent as text/plain.
+var notifyContentTypes = map[string]string{
+	"json":                      "application/json",
+	"slack-message":             "application/json",
+	"teams-message":             "application/json",
+	"sarif":                     "application/json",
+	"focus":                     "text/csv",
+	"csv":                       "text/csv",
+	"html":                      "text/html",
+	"xlsx":                      "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"github-comment":            "text/markdown",
+	"gitlab-comment":            "text/markdown",
+	"azure-repos-comment":       "text/markdown",
+	"bitbucket-comment":         "text/markdown",
+	"bitbucket-comment-summary": "text/markdown",
+	"gitea-comment":             "text/markdown",
+}
+
+func notifyCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Send Infracost output to a webhook",
+		Long: `Send Infracost output to a webhook, e.g. a Microsoft Teams or Slack channel, or
+incident tooling.
+
+The values of --header are Go templates that are rendered with the combined
+Infracost JSON, and can read environment variables with the env function.`,
+		Example: `  Post an Adaptive Card to a Microsoft Teams channel:
+
+      infracost notify --path infracost.json --format teams-message --webhook-url $TEAMS_WEBHOOK_URL
+
+  Post the JSON to a webhook only when the monthly cost increases by more than $100:
+
+      infracost notify --path infracost.json --webhook-url https://example.com/hooks/infracost --min-diff 100 \
+          --header 'Authorization: Bearer {{ env "WEBHOOK_TOKEN" }}'
+
+  Post a Slack message only when a policy or guardrail fails:
+
+      infracost notify --path infracost.json --format slack-message --webhook-url $SLACK_WEBHOOK_URL \
+          --config-file infracost.yml --on-policy-failure`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			format = strings.ToLower(format)
+			ctx.SetContextValue("outputFormat", format)
+
+			if !contains(validOutputFormats, format) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
+			}
+
+			webhookURL, _ := cmd.Flags().GetString("webhook-url")
+			if webhookURL == "" {
+				webhookURL = os.Getenv("INFRACOST_WEBHOOK_URL")
+			}
+			if webhookURL == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--webhook-url or the INFRACOST_WEBHOOK_URL environment variable is required")
+			}
+
+			onPolicyFailure, _ := cmd.Flags().GetBool("on-policy-failure")
+			policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+			configFile, _ := cmd.Flags().GetString("config-file")
+			if onPolicyFailure && len(policyPaths) == 0 && configFile == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--on-policy-failure requires --policy-path or --config-file")
+			}
+
+			groupBy, _ := cmd.Flags().GetStringSlice("group-by")
+			if err := output.ValidateGroupBy(groupBy); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := output.LoadPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs)
+			if errors.As(err, &clierror.WarningError{}) {
+				ui.PrintWarningf(cmd.ErrOrStderr(), "%s", err.Error())
+			} else if err != nil {
+				return err
+			}
+			combined.IsCIRun = ctx.IsCIRun()
+			combined.Metadata.InfracostCommand = "notify"
+
+			policyChecks, guardrailCheck, err := runLocalChecks(cmd, ctx, combined)
+			if err != nil {
+				return err
+			}
+
+			if reason := notifySkipReason(cmd, combined, policyChecks, guardrailCheck); reason != "" {
+				cmd.Printf("Notification not sent as %s\n", reason)
+				return nil
+			}
+
+			opts := output.Options{
+				DashboardEndpoint: ctx.Config.DashboardEndpoint,
+				NoColor:           true,
+				Fields:            []string{"monthlyQuantity", "unit", "monthlyCost"},
+				CurrencyFormat:    ctx.Config.CurrencyFormat,
+				GroupBy:           groupBy,
+				PolicyChecks:      policyChecks,
+				GuardrailCheck:    guardrailCheck,
+			}
+			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
+
+			b, err := output.FormatOutput(format, combined, opts)
+			if err != nil {
+				return err
+			}
+
+			rawHeaders, _ := cmd.Flags().GetStringArray("header")
+			headers, err := notify.RenderHeaders(rawHeaders, combined)
+			if err != nil {
+				return err
+			}
+			if headers.Get("Content-Type") == "" {
+				contentType, ok := notifyContentTypes[format]
+				if !ok {
+					contentType = "text/plain"
+				}
+				headers.Set("Content-Type", contentType)
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if dryRun {
+				cmd.Println(string(b))
+				cmd.Println("Notification not sent (--dry-run was specified)")
+				return nil
+			}
+
+			maxRetries, _ := cmd.Flags().GetInt("max-retries")
+			retryWait, _ := cmd.Flags().GetDuration("retry-wait")
+
+			err = notify.NewWebhook(webhookURL, headers, maxRetries, retryWait).Send(ctx.Context(), b)
+			if err != nil {
+				return err
+			}
+
+			pricingClient := apiclient.NewPricingAPIClient(ctx)
+			err = pricingClient.AddEvent("infracost-notify", ctx.EventEnv())
+			if err != nil {
+				logging.Logger.WithError(err).Error("could not report infracost-notify event")
+			}
+
+			cmd.Println("Notification sent to webhook")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	cmd.Flags().String("format", "json", "Output format sent to the webhook: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, gitea-comment, slack-message, teams-message, sarif, csv, xlsx, focus")
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validOutputFormats, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().String("webhook-url", "", "URL of the webhook, defaults to the INFRACOST_WEBHOOK_URL environment variable")
+	cmd.Flags().StringArray("header", nil, "Header sent to the webhook in the format 'Name: value', the value is a Go template")
+	cmd.Flags().Int("max-retries", 3, "Number of times the request is retried if the webhook returns a 429 or 5xx status")
+	cmd.Flags().Duration("retry-wait", time.Second, "Time to wait before the first retry, doubled for each retry after that")
+	cmd.Flags().Float64("min-diff", 0, "Only send if the monthly cost increases by more than this amount")
+	cmd.Flags().Float64("min-diff-percentage", 0, "Only send if the monthly cost increases by more than this percentage")
+	cmd.Flags().Bool("on-policy-failure", false, "Only send if a policy, tag policy or guardrail fails")
+	cmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+	cmd.Flags().String("previous-path", "", "Path to Infracost JSON file of a previous run, policies can use it as input.previous")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file, resources are checked against its tag_policy and guardrails")
+	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the output")
+	cmd.Flags().StringSlice("group-by", nil, "Comma separated list of keys to group costs by: tag:<name>,resource_type,region,module")
+	cmd.Flags().Bool("dry-run", false, "Generate the output without sending it to the webhook")
+
+	return cmd
+}
+
+// notifySkipReason returns why the notification shouldn't be sent if any of
+// the conditions set by the flags aren't met, otherwise it returns an empty
+// string.
+func notifySkipReason(cmd *cobra.Command, combined output.Root, policyChecks output.PolicyCheck, guardrailCheck output.GuardrailCheck) string {
+	diff := decimal.Zero
+	if combined.DiffTotalMonthlyCost != nil {
+		diff = *combined.DiffTotalMonthlyCost
+	}
+
+	if cmd.Flags().Changed("min-diff") {
+		minDiff, _ := cmd.Flags().GetFloat64("min-diff")
+		threshold := decimal.NewFromFloat(minDiff)
+		if !diff.GreaterThan(threshold) {
+			return fmt.Sprintf("the monthly cost increase of %s is not above %s", output.FormatCost2DP(combined.Currency, &diff), output.FormatCost2DP(combined.Currency, &threshold))
+		}
+	}
+
+	if cmd.Flags().Changed("min-diff-percentage") {
+		minDiffPercentage, _ := cmd.Flags().GetFloat64("min-diff-percentage")
+		threshold := decimal.NewFromFloat(minDiffPercentage)
+
+		// An increase from no past cost is always above the threshold.
+		if combined.PastTotalMonthlyCost != nil && combined.PastTotalMonthlyCost.IsPositive() {
+			percentage := diff.Div(*combined.PastTotalMonthlyCost).Mul(decimal.NewFromInt(100))
+			if !percentage.GreaterThan(threshold) {
+				return fmt.Sprintf("the monthly cost increase of %s%% is not above %s%%", percentage.Round(1).String(), threshold.String())
+			}
+		} else if !diff.IsPositive() {
+			return fmt.Sprintf("the monthly cost does not increase by more than %s%%", threshold.String())
+		}
+	}
+
+	if onPolicyFailure, _ := cmd.Flags().GetBool("on-policy-failure"); onPolicyFailure {
+		if !policyChecks.HasFailed() && len(guardrailCheck.CommentableFailures) == 0 {
+			return "no policy, tag policy or guardrail failed"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,134 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+// webhookRequest is a request received by the local stand-in of a webhook.
+type webhookRequest struct {
+	headers http.Header
+	body    map[string]interface{}
+}
+
+// newWebhookServer returns a local stand-in for a webhook that responds with
+// the given statuses in turn, and then with 200.
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, *[]webhookRequest) {
+	t.Helper()
+
+	var requests []webhookRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		require.NoError(t, err)
+
+		requests = append(requests, webhookRequest{headers: r.Header, body: body})
+
+		if len(requests) <= len(statuses) {
+			w.WriteHeader(statuses[len(requests)-1])
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &requests
+}
+
+func TestNotifyHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--help"}, nil)
+}
+
+func TestNotifyTeamsMessage(t *testing.T) {
+	ts, requests := newWebhookServer(t)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--format", "teams-message", "--webhook-url", ts.URL, "--header", `Authorization: Bearer {{ env "NOTIFY_TEST_TOKEN" }}`, "--header", "X-Infracost-Currency: {{ .Currency }}"},
+		&GoldenFileOptions{Env: map[string]string{"NOTIFY_TEST_TOKEN": "secret"}})
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "application/json", req.headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", req.headers.Get("Authorization"))
+	assert.Equal(t, "USD", req.headers.Get("X-Infracost-Currency"))
+
+	assert.Equal(t, "message", req.body["type"])
+	attachment := req.body["attachments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]interface{})
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	heading := card["body"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "💰 Infracost estimate: **monthly cost will increase by $40.56 (+100%) 📈**", heading["text"])
+}
+
+func TestNotifyRetries(t *testing.T) {
+	ts, requests := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL, "--retry-wait", "1ms"},
+		nil)
+
+	require.Len(t, *requests, 3)
+	for _, req := range *requests {
+		assert.Equal(t, "0.9", req.body["version"])
+	}
+}
+
+func TestNotifyRetriesExhausted(t *testing.T) {
+	ts, requests := newWebhookServer(t, http.StatusBadGateway, http.StatusBadGateway)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL, "--max-retries", "1", "--retry-wait", "1ms"},
+		nil)
+
+	assert.Len(t, *requests, 2)
+}
+
+func TestNotifyMinDiffNotExceeded(t *testing.T) {
+	ts, requests := newWebhookServer(t)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL, "--min-diff", "100"},
+		nil)
+
+	assert.Empty(t, *requests)
+}
+
+func TestNotifyMinDiffPercentageExceeded(t *testing.T) {
+	ts, requests := newWebhookServer(t)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL, "--min-diff-percentage", "50"},
+		nil)
+
+	assert.Len(t, *requests, 1)
+}
+
+func TestNotifyOnPolicyFailure(t *testing.T) {
+	ts, requests := newWebhookServer(t)
+
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName,
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--format", "slack-message", "--webhook-url", ts.URL, "--config-file", path.Join("./testdata", testName, "infracost.yml"), "--on-policy-failure"},
+		nil)
+
+	assert.Len(t, *requests, 1)
+}
+
+func TestNotifyOnPolicyFailureWithoutChecks(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", "http://localhost", "--on-policy-failure"},
+		nil)
+}
+
+func TestNotifyWithoutWebhookURL(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json"},
+		nil)
+}
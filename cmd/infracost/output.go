@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -26,7 +27,13 @@ var (
 		"azure-repos-comment",
 		"bitbucket-comment",
 		"bitbucket-comment-summary",
+		"gitea-comment",
 		"slack-message",
+		"teams-message",
+		"sarif",
+		"csv",
+		"xlsx",
+		"focus",
 	}
 
 	validCompareToFormats = map[string]bool{
@@ -37,7 +44,10 @@ var (
 		"azure-repos-comment":       true,
 		"bitbucket-comment":         true,
 		"bitbucket-comment-summary": true,
+		"gitea-comment":             true,
 		"slack-message":             true,
+		"teams-message":             true,
+		"sarif":                     true,
 	}
 )
 
@@ -72,7 +82,15 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
   Create markdown report to post in a Bitbucket comment:
 
-      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes`,
+      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes
+
+  Create markdown report to post in a Gitea comment:
+
+      infracost output --format gitea-comment --path "out*.json" # glob needs quotes
+
+  Create an Adaptive Card to post in a Microsoft Teams channel:
+
+      infracost output --format teams-message --path "out*.json" # glob needs quotes`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
@@ -86,6 +104,17 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
 			}
 
+			if outFile, _ := cmd.Flags().GetString("out-file"); format == "xlsx" && outFile == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--format xlsx requires --out-file as the workbook is a binary file")
+			}
+
+			groupBy, _ := cmd.Flags().GetStringSlice("group-by")
+			if err := output.ValidateGroupBy(groupBy); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			inputs, err := output.LoadPaths(paths)
@@ -132,24 +161,39 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				NoColor:           ctx.Config.NoColor,
 				Fields:            fields,
 				CurrencyFormat:    ctx.Config.CurrencyFormat,
+				GroupBy:           groupBy,
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
 
+			sarifCostThreshold, _ := cmd.Flags().GetFloat64("sarif-cost-threshold")
+			opts.SARIFCostThreshold = decimalPtr(decimal.NewFromFloat(sarifCostThreshold))
+
 			validFieldsFormats := []string{"table", "html"}
 
 			if cmd.Flags().Changed("fields") && !contains(validFieldsFormats, format) {
 				ui.PrintWarning(cmd.ErrOrStderr(), "fields is only supported for table and html output formats")
 			}
 
+			var guardrailCheck output.GuardrailCheck
 			if ctx.IsCloudUploadEnabled() {
 				if ctx.Config.IsSelfHosted() {
 					ui.PrintWarning(cmd.ErrOrStderr(), "Infracost Cloud is part of Infracost's hosted services. Contact hello@infracost.io for help.")
 				} else {
-					combined.RunID, combined.ShareURL, _ = shareCombinedRun(ctx, combined, inputs)
+					combined.RunID, combined.ShareURL, guardrailCheck = shareCombinedRun(ctx, combined, inputs)
 				}
 			}
 
+			policyChecks, localGuardrailCheck, err := runLocalChecks(cmd, ctx, combined)
+			if err != nil {
+				return err
+			}
+			if localGuardrailCheck.TotalChecked > 0 {
+				guardrailCheck = guardrailCheck.Add(localGuardrailCheck)
+			}
+			opts.PolicyChecks = policyChecks
+			opts.GuardrailCheck = guardrailCheck
+
 			b, err := output.FormatOutput(format, combined, opts)
 			if err != nil {
 				return err
@@ -177,10 +221,14 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
 	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, gitea-comment, slack-message, teams-message, sarif, csv, xlsx, focus")
 	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
+	cmd.Flags().Float64("sarif-cost-threshold", output.DefaultSARIFCostThreshold.InexactFloat64(), "Monthly cost above which unchanged resources are reported in sarif output")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().StringSlice("group-by", nil, "Comma separated list of keys to group costs by: tag:<name>,resource_type,region,module.\nSupported by table, html, json and comment output formats")
+	cmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental).\nSupported by sarif and comment output formats")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file, resources are checked against its tag_policy and guardrails.\nSupported by sarif and comment output formats")
 
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
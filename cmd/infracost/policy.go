@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/tester"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// The rules of the infracost package that are evaluated. Failed deny rules
+// fail the run, failed warn rules are only reported and info rules are always
+// reported.
+const (
+	policyRuleDeny = "deny"
+	policyRuleWarn = "warn"
+	policyRuleInfo = "info"
+)
+
+var policyRules = []string{policyRuleDeny, policyRuleWarn, policyRuleInfo}
+
+// policyResource is a resource in the resources document of the policy input.
+// Attributes are only set if the Infracost JSON was generated with
+// --include-attributes.
+type policyResource struct {
+	Project     string                 `json:"project"`
+	Address     string                 `json:"address"`
+	Type        string                 `json:"type"`
+	Tags        map[string]string      `json:"tags"`
+	MonthlyCost *decimal.Decimal       `json:"monthlyCost"`
+	Attributes  map[string]interface{} `json:"attributes"`
+}
+
+// policyDocuments are the documents that are added to the Infracost JSON to
+// make the policy input.
+type policyDocuments struct {
+	VCS       output.Metadata  `json:"vcs"`
+	Files     []string         `json:"files"`
+	Resources []policyResource `json:"resources"`
+	Previous  *output.Root     `json:"previous,omitempty"`
+}
+
+func policyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Test Infracost policies",
+		Long:  "Test Infracost policies",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(policyTestCmd())
+
+	return cmd
+}
+
+func policyTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run the Rego unit tests of Infracost policies",
+		Long: `Run the Rego unit tests of Infracost policies.
+
+Rules that start with test_ are run as tests. Each fixture is an Infracost JSON
+file that tests can use as the policy input, e.g. a fixture called
+infracost-base.json is available as data.fixtures["infracost-base"].`,
+		Example: `  Run the tests of the policies in a directory:
+
+      infracost policy test --policy-path policies --fixture-path testdata/infracost-base.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+			fixturePaths, _ := cmd.Flags().GetStringArray("fixture-path")
+
+			return runPolicyTests(cmd, policyPaths, fixturePaths)
+		},
+	}
+
+	cmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy and test files, glob patterns need quotes")
+	cmd.Flags().StringArray("fixture-path", nil, "Path to Infracost JSON files that tests can use as the policy input")
+
+	_ = cmd.MarkFlagRequired("policy-path")
+	_ = cmd.MarkFlagFilename("fixture-path", "json")
+
+	return cmd
+}
+
+func runPolicyTests(cmd *cobra.Command, policyPaths []string, fixturePaths []string) error {
+	fixtures := make(map[string]interface{}, len(fixturePaths))
+	for _, p := range fixturePaths {
+		root, err := output.Load(p)
+		if err != nil {
+			return fmt.Errorf("Unable to load fixture %s: %w", p, err)
+		}
+
+		input, err := policyInput(root, policyDocuments{VCS: root.Metadata})
+		if err != nil {
+			return err
+		}
+
+		fixtures[strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))] = input
+	}
+
+	modules, store, err := tester.Load(policyPaths, nil)
+	if err != nil {
+		return fmt.Errorf("Unable to load provided policies: %s", err.Error())
+	}
+
+	ctx := context.Background()
+	err = storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.Write(ctx, txn, storage.AddOp, storage.Path{"fixtures"}, fixtures)
+	})
+	if err != nil {
+		return fmt.Errorf("Unable to add fixtures to the policy data: %s", err.Error())
+	}
+
+	ch, err := tester.NewRunner().SetStore(store).Run(ctx, modules)
+	if err != nil {
+		return fmt.Errorf("Unable to run policy tests: %s", err.Error())
+	}
+
+	var results []*tester.Result
+	for r := range ch {
+		results = append(results, r)
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("No policy tests found, tests are rules that start with test_")
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Package != results[j].Package {
+			return results[i].Package < results[j].Package
+		}
+
+		return results[i].Name < results[j].Name
+	})
+
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Skip:
+			cmd.Printf("%s %s.%s\n", ui.WarningString("SKIP"), r.Package, r.Name)
+		case r.Pass():
+			cmd.Printf("%s %s.%s\n", ui.SuccessString("PASS"), r.Package, r.Name)
+		default:
+			failed++
+			cmd.Printf("%s %s.%s\n", ui.ErrorString("FAIL"), r.Package, r.Name)
+			if r.Error != nil {
+				cmd.Printf("  %s\n", r.Error.Error())
+			}
+		}
+	}
+
+	cmd.Printf("\n%d of %d policy tests passed\n", len(results)-failed, len(results))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d policy tests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// commentPolicyDocuments returns the documents that are added to the policy
+// input of the comment command.
+func commentPolicyDocuments(ctx *config.RunContext, previous *output.Root) policyDocuments {
+	return policyDocuments{
+		VCS:      output.NewMetadata(ctx),
+		Files:    ctx.VCSMetadata.Commit.ChangedObjects,
+		Previous: previous,
+	}
+}
+
+// policyInput returns the Infracost JSON with the documents added to it as the
+// input of the policies. The JSON is kept at the top level so that existing
+// policies can still use input.projects.
+func policyInput(root output.Root, docs policyDocuments) (map[string]interface{}, error) {
+	for _, project := range root.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, res := range project.Breakdown.Resources {
+			attributes, _ := res.Metadata["attributes"].(map[string]interface{})
+			if attributes == nil {
+				attributes = map[string]interface{}{}
+			}
+
+			docs.Resources = append(docs.Resources, policyResource{
+				Project:     project.Name,
+				Address:     res.Name,
+				Type:        res.ResourceType(),
+				Tags:        res.Tags,
+				MonthlyCost: res.MonthlyCost,
+				Attributes:  attributes,
+			})
+		}
+	}
+
+	if docs.Files == nil {
+		docs.Files = []string{}
+	}
+	if docs.Resources == nil {
+		docs.Resources = []policyResource{}
+	}
+
+	input := map[string]interface{}{}
+	for _, v := range []interface{}{root, docs} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
+		}
+
+		if err := json.Unmarshal(b, &input); err != nil {
+			return nil, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
+		}
+	}
+
+	return input, nil
+}
+
+func queryPolicy(policyPaths []string, input map[string]interface{}) (output.PolicyCheck, error) {
+	checks := output.PolicyCheck{
+		Enabled: true,
+	}
+
+	inputValue, err := ast.InterfaceToValue(input)
+	if err != nil {
+		return checks, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
+	}
+
+	ctx := context.Background()
+	r := rego.New(
+		rego.Query("data.infracost"),
+		rego.ParsedInput(inputValue),
+		rego.Load(policyPaths, func(abspath string, info os.FileInfo, depth int) bool {
+			return false
+		}),
+	)
+	pq, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return checks, fmt.Errorf("Unable to query provided policies: %s", err.Error())
+	}
+
+	res, err := pq.Eval(ctx)
+	if err != nil {
+		return checks, err
+	}
+
+	found := false
+	for _, result := range res {
+		for _, e := range result.Expressions {
+			pkg, ok := e.Value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, rule := range policyRules {
+				value, ok := pkg[rule]
+				if !ok {
+					continue
+				}
+
+				found = true
+				switch v := value.(type) {
+				case map[string]interface{}:
+					readPolicyOut(rule, v, &checks)
+				case []interface{}:
+					for _, ii := range v {
+						if m, ok := ii.(map[string]interface{}); ok {
+							readPolicyOut(rule, m, &checks)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if !found {
+		return checks, fmt.Errorf("The provided policies returned no valid data.infracost.deny, warn or info rules. Please check that the policies are formatted correctly.")
+	}
+
+	return checks, nil
+}
+
+// readPolicyOut adds the {msg, failed} output object of a rule to the checks.
+// Info rules only need a msg as they are always reported.
+func readPolicyOut(rule string, v map[string]interface{}, checks *output.PolicyCheck) {
+	if _, ok := v["msg"]; !ok {
+		checks.Failures = append(checks.Failures, "Policy rule invalid as it did not contain {msg: string} property in output object. Please edit rule output object.")
+		return
+	}
+	msg := fmt.Sprintf("%v", v["msg"])
+
+	if rule == policyRuleInfo {
+		checks.Infos = append(checks.Infos, msg)
+		return
+	}
+
+	if _, ok := v["failed"]; !ok {
+		checks.Failures = append(checks.Failures, fmt.Sprintf("Policy rule: [%s] did not contain {failed: bool} output property. Please edit rule output object.", msg))
+		return
+	}
+
+	failed, _ := v["failed"].(bool)
+
+	switch {
+	case !failed:
+		checks.Passed = append(checks.Passed, msg)
+	case rule == policyRuleWarn:
+		checks.Warnings = append(checks.Warnings, msg)
+	default:
+		checks.Failures = append(checks.Failures, msg)
+	}
+}
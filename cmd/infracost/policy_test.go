@@ -0,0 +1,15 @@
+package main_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestPolicyTest(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName,
+		[]string{"policy", "test", "--policy-path", path.Join("./testdata", testName, "policies"), "--fixture-path", path.Join("./testdata", testName, "fixtures", "infracost.json")},
+		nil)
+}
@@ -37,6 +37,11 @@ import (
 type projectJob struct {
 	index      int
 	projectCfg *config.Project
+	// dependencies are the done channels of the projects whose outputs the
+	// project uses as remote state outputs.
+	dependencies []chan struct{}
+	// done is closed once the project has run.
+	done chan struct{}
 }
 
 type projectResult struct {
@@ -64,6 +69,7 @@ func addRunFlags(cmd *cobra.Command) {
 	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable with --terraform-force-cli")
 	cmd.Flags().String("terraform-init-flags", "", "Flags to pass to 'terraform init'. Applicable with --terraform-force-cli")
 	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().StringArray("cfn-parameters", nil, "Set CloudFormation parameters as Key=Value, or a JSON parameters file. Applicable when path is a CloudFormation template")
 
 	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories to exclude, glob patterns need quotes")
 	cmd.Flags().Bool("include-all-paths", false, "Set project auto-detection to use all subdirectories in given path")
@@ -170,6 +176,7 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		NoColor:           runCtx.Config.NoColor,
 		Fields:            runCtx.Config.Fields,
 		CurrencyFormat:    runCtx.Config.CurrencyFormat,
+		GroupBy:           runCtx.Config.GroupBy,
 	})
 	if err != nil {
 		return err
@@ -201,6 +208,24 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		cmd.Println(string(b))
 	}
 
+	if runCtx.Config.TagPolicy != nil {
+		tagChecks := output.CheckTagPolicy(r, runCtx.Config.TagPolicy)
+		if tagChecks.HasFailed() {
+			return tagChecks.Failures
+		}
+	}
+
+	if len(runCtx.Config.Guardrails) > 0 {
+		guardrailCheck := output.CheckGuardrails(r, runCtx.Config.Guardrails)
+		if len(guardrailCheck.BlockingFailures) > 0 {
+			return guardrailCheck.BlockingFailures
+		}
+
+		if len(guardrailCheck.CommentableFailures) > 0 {
+			ui.PrintWarning(cmd.ErrOrStderr(), guardrailCheck.CommentableFailures.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -311,31 +336,29 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 			}()
 
 			for job := range jobs {
-				ctx := config.NewProjectContext(r.runCtx, job.projectCfg, log.Fields{
-					"routine": i,
-				})
-				configProjects, err := r.runProjectConfig(ctx)
+				result, err := r.runJob(job, i)
 				if err != nil {
 					return err
 				}
 
-				projectResultChan <- projectResult{
-					index:      job.index,
-					ctx:        ctx,
-					projectOut: configProjects,
-				}
+				projectResultChan <- result
 			}
 
 			return nil
 		})
 	}
 
-	for i, p := range r.runCtx.Config.Projects {
-		jobs <- projectJob{index: i, projectCfg: p}
+	orderedJobs, err := projectJobs(r.runCtx.Config.Projects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range orderedJobs {
+		jobs <- job
 	}
 	close(jobs)
 
-	err := errGroup.Wait()
+	err = errGroup.Wait()
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +377,100 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 	return projectResults, nil
 }
 
+// runJob runs the project of the job once the projects it depends on have
+// run. The done channel of the job is closed even if the project returns an
+// error or panics, so that the jobs that depend on it don't wait forever.
+func (r *parallelRunner) runJob(job projectJob, routine int) (projectResult, error) {
+	defer close(job.done)
+
+	for _, dependency := range job.dependencies {
+		<-dependency
+	}
+
+	ctx := config.NewProjectContext(r.runCtx, job.projectCfg, log.Fields{
+		"routine": routine,
+	})
+	configProjects, err := r.runProjectConfig(ctx)
+	if err != nil {
+		return projectResult{}, err
+	}
+
+	return projectResult{
+		index:      job.index,
+		ctx:        ctx,
+		projectOut: configProjects,
+	}, nil
+}
+
+// projectJobs returns the jobs for the projects, ordered so that a project runs
+// after the projects whose outputs it uses as remote state outputs. Projects
+// keep their config order otherwise. Jobs are queued in this order, so the
+// projects a job waits on have always been picked up by a worker first.
+func projectJobs(projects []*config.Project) ([]projectJob, error) {
+	jobs := make([]projectJob, len(projects))
+	byName := make(map[string][]int)
+	for i, p := range projects {
+		jobs[i] = projectJob{index: i, projectCfg: p, done: make(chan struct{})}
+		if p.Name != "" {
+			byName[p.Name] = append(byName[p.Name], i)
+		}
+	}
+
+	dependsOn := make([][]int, len(projects))
+	for i, p := range projects {
+		for _, state := range p.RemoteStates {
+			for _, j := range byName[state.Project] {
+				if j == i {
+					continue
+				}
+
+				dependsOn[i] = append(dependsOn[i], j)
+				jobs[i].dependencies = append(jobs[i].dependencies, jobs[j].done)
+			}
+		}
+	}
+
+	ordered := make([]projectJob, 0, len(jobs))
+	queued := make([]bool, len(jobs))
+	for len(ordered) < len(jobs) {
+		progress := false
+		for i := range jobs {
+			if queued[i] {
+				continue
+			}
+
+			ready := true
+			for _, j := range dependsOn[i] {
+				if !queued[j] {
+					ready = false
+					break
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			ordered = append(ordered, jobs[i])
+			queued[i] = true
+			progress = true
+		}
+
+		if !progress {
+			var cycle []string
+			for i, p := range projects {
+				if !queued[i] {
+					cycle = append(cycle, p.Name)
+				}
+			}
+
+			return nil, fmt.Errorf("projects %s use each other's outputs as remote states, which is a cycle", strings.Join(cycle, ", "))
+		}
+	}
+
+	return ordered, nil
+}
+
 func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectOutput, error) {
 	mux := r.pathMuxs[ctx.ProjectConfig.Path]
 	if mux != nil {
@@ -393,7 +510,7 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 	}
 
 	m := fmt.Sprintf("Detected %s at %s", provider.DisplayType(), ui.DisplayPath(ctx.ProjectConfig.Path))
-	if provider.Type() == "terraform_dir" {
+	if provider.Type() == "terraform_dir" || provider.Type() == "terragrunt_dir" {
 		m = fmt.Sprintf("Evaluating %s at %s", provider.DisplayType(), ui.DisplayPath(ctx.ProjectConfig.Path))
 	}
 
@@ -443,29 +560,7 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 		ctx.SetContextValue("hasUsageFile", true)
 	}
 
-	// Merge wildcard usages into individual usage
-	wildCardUsage := make(map[string]*usage.ResourceUsage)
-	for _, us := range usageFile.ResourceUsages {
-		if strings.HasSuffix(us.Name, "[*]") {
-			lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
-			prefixName := us.Name[:lastIndexOfOpenBracket]
-			wildCardUsage[prefixName] = us
-		}
-	}
-
-	for _, us := range usageFile.ResourceUsages {
-		if strings.HasSuffix(us.Name, "[*]") {
-			continue
-		}
-
-		if !strings.HasSuffix(us.Name, "]") {
-			continue
-		}
-		lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
-		prefixName := us.Name[:lastIndexOfOpenBracket]
-
-		us.MergeResourceUsage(wildCardUsage[prefixName])
-	}
+	mergeWildcardUsages(usageFile)
 
 	usageData = usageFile.ToUsageDataMap()
 	out := &projectOutput{}
@@ -552,6 +647,33 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 	return out, nil
 }
 
+// mergeWildcardUsages merges wildcard usages into the individual usages of
+// the resources they match.
+func mergeWildcardUsages(usageFile *usage.UsageFile) {
+	wildCardUsage := make(map[string]*usage.ResourceUsage)
+	for _, us := range usageFile.ResourceUsages {
+		if strings.HasSuffix(us.Name, "[*]") {
+			lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
+			prefixName := us.Name[:lastIndexOfOpenBracket]
+			wildCardUsage[prefixName] = us
+		}
+	}
+
+	for _, us := range usageFile.ResourceUsages {
+		if strings.HasSuffix(us.Name, "[*]") {
+			continue
+		}
+
+		if !strings.HasSuffix(us.Name, "]") {
+			continue
+		}
+		lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
+		prefixName := us.Name[:lastIndexOfOpenBracket]
+
+		us.MergeResourceUsage(wildCardUsage[prefixName])
+	}
+}
+
 func (r *parallelRunner) uploadCloudResourceIDs(projects []*schema.Project) error {
 	if r.runCtx.Config.UsageAPIEndpoint == "" || !r.hasCloudResourceIDToUpload(projects) {
 		return nil
@@ -824,11 +946,12 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		cmd.Flags().Changed("terraform-var-file") ||
 		cmd.Flags().Changed("terraform-var") ||
 		cmd.Flags().Changed("terraform-init-flags") ||
-		cmd.Flags().Changed("terraform-workspace"))
+		cmd.Flags().Changed("terraform-workspace") ||
+		cmd.Flags().Changed("cfn-parameters"))
 
 	if hasConfigFile && hasProjectFlags {
 		m := "--config-file flag cannot be used with the following flags: "
-		m += "--path, --project-name, --terraform-*, --usage-file"
+		m += "--path, --project-name, --terraform-*, --cfn-parameters, --usage-file"
 		ui.PrintUsage(cmd)
 		return errors.New(m)
 	}
@@ -855,6 +978,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		if cmd.Flags().Changed("terraform-workspace") {
 			projectCfg.TerraformWorkspace, _ = cmd.Flags().GetString("terraform-workspace")
 		}
+
+		cfnParams, _ := cmd.Flags().GetStringArray("cfn-parameters")
+		projectCfg.CloudFormationParameters, projectCfg.CloudFormationParameterFiles = cfnParametersFromFlags(cfnParams)
 	}
 
 	if hasConfigFile {
@@ -882,8 +1008,18 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	cfg.NoCache, _ = cmd.Flags().GetBool("no-cache")
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+	cfg.HCLDiagnostics, _ = cmd.Flags().GetBool("hcl-diagnostics")
+	cfg.IncludeAttributes, _ = cmd.Flags().GetBool("include-attributes")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
 
+	if cmd.Flags().Changed("group-by") {
+		cfg.GroupBy, _ = cmd.Flags().GetStringSlice("group-by")
+		if err := output.ValidateGroupBy(cfg.GroupBy); err != nil {
+			ui.PrintUsage(cmd)
+			return err
+		}
+	}
+
 	includeAllFields := "all"
 	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
 	validFieldsFormats := []string{"table", "html"}
@@ -930,6 +1066,28 @@ func tfVarsToMap(vars []string) map[string]string {
 	return m
 }
 
+// cfnParametersFromFlags splits the --cfn-parameters flag values into the
+// Key=Value parameters and the paths to JSON parameter files.
+func cfnParametersFromFlags(values []string) (map[string]string, []string) {
+	var params map[string]string
+	var files []string
+
+	for _, v := range values {
+		pieces := strings.SplitN(v, "=", 2)
+		if len(pieces) != 2 {
+			files = append(files, strings.TrimPrefix(v, "file://"))
+			continue
+		}
+
+		if params == nil {
+			params = map[string]string{}
+		}
+		params[pieces[0]] = pieces[1]
+	}
+
+	return params, files
+}
+
 func checkRunConfig(warningWriter io.Writer, cfg *config.Config) error {
 	if cfg.Format == "json" && cfg.ShowSkipped {
 		ui.PrintWarning(warningWriter, "show-skipped is not needed with JSON output format as that always includes them.\n")
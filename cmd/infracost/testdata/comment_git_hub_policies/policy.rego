@@ -0,0 +1,28 @@
+package infracost
+
+deny contains out if {
+	maxDiff := 1000
+	diff := to_number(input.diffTotalMonthlyCost)
+
+	out := {
+		"msg": sprintf("Total monthly cost diff must be less than $%d (actual diff is $%.2f)", [maxDiff, diff]),
+		"failed": diff >= maxDiff,
+	}
+}
+
+warn contains out if {
+	r := input.resources[_]
+	r.type == "aws_db_instance"
+	cost := to_number(r.monthlyCost)
+
+	out := {
+		"msg": sprintf("%s costs $%.2f per month, consider a smaller instance", [r.address, cost]),
+		"failed": cost > 10,
+	}
+}
+
+info contains out if {
+	count(input.resources) > 0
+
+	out := {"msg": sprintf("Checked %d resources", [count(input.resources)])}
+}
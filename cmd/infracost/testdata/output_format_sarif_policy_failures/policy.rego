@@ -0,0 +1,11 @@
+package infracost
+
+deny contains out if {
+	maxDiff := 10
+	diff := to_number(input.diffTotalMonthlyCost)
+
+	out := {
+		"msg": sprintf("Total monthly cost diff must be less than $%d (actual diff is $%.2f)", [maxDiff, diff]),
+		"failed": diff >= maxDiff,
+	}
+}
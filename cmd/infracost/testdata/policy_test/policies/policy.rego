@@ -0,0 +1,21 @@
+package infracost
+
+deny contains out if {
+	r := input.resources[_]
+	r.type == "aws_instance"
+
+	out := {
+		"msg": sprintf("%s must not use a 4xlarge instance type", [r.address]),
+		"failed": endswith(r.attributes.instance_type, ".4xlarge"),
+	}
+}
+
+warn contains out if {
+	r := input.resources[_]
+	r.type == "aws_db_instance"
+
+	out := {
+		"msg": sprintf("%s should be multi-AZ", [r.address]),
+		"failed": r.attributes.multi_az == false,
+	}
+}
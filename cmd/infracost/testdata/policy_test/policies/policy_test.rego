@@ -0,0 +1,18 @@
+package infracost
+
+test_deny_4xlarge_instance if {
+	deny with input as data.fixtures.infracost == {{
+		"msg": "aws_instance.instance_1 must not use a 4xlarge instance type",
+		"failed": true,
+	}}
+}
+
+test_warn_single_az_database if {
+	some out in warn with input as data.fixtures.infracost
+	out.failed
+}
+
+test_deny_small_instance if {
+	some out in deny with input as {"resources": [{"address": "aws_instance.a", "type": "aws_instance", "attributes": {"instance_type": "t3.micro"}}]}
+	out.failed
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func usageCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Work with Infracost usage files",
+		Long:  "Work with Infracost usage files",
+		Example: `  Validate a usage file against a Terraform directory:
+
+      infracost usage validate --path /code --usage-file infracost-usage.yml`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(usageValidateCommand(ctx))
+
+	return cmd
+}
+
+type usageValidateCmd struct {
+	TerraformVarFiles  []string
+	TerraformVars      []string
+	TerraformWorkspace string
+
+	Path       string
+	ConfigFile string
+	UsageFile  string
+	Strict     bool
+
+	cmd *cobra.Command
+}
+
+func (u usageValidateCmd) loadRunFlags(cfg *config.Config) error {
+	if u.ConfigFile == "" && (u.Path == "" || u.UsageFile == "") {
+		ui.PrintUsage(u.cmd)
+		return errors.New("Both --path and --usage-file must be specified, or use --config-file")
+	}
+
+	if u.ConfigFile != "" && (u.Path != "" || u.UsageFile != "" || len(u.TerraformVars) > 0 || len(u.TerraformVarFiles) > 0 || u.TerraformWorkspace != "") {
+		ui.PrintUsage(u.cmd)
+		return errors.New("--config-file flag cannot be used with the following flags: --path, --terraform-*, --usage-file")
+	}
+
+	if u.ConfigFile != "" {
+		err := cfg.LoadFromConfigFile(u.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		cfg.ConfigFilePath = u.ConfigFile
+		return nil
+	}
+
+	projectCfg := cfg.Projects[0]
+	cfg.RootPath = u.Path
+	projectCfg.Path = u.Path
+	projectCfg.TerraformVarFiles = u.TerraformVarFiles
+	projectCfg.TerraformVars = tfVarsToMap(u.TerraformVars)
+	projectCfg.TerraformWorkspace = u.TerraformWorkspace
+	projectCfg.UsageFile = u.UsageFile
+
+	return nil
+}
+
+func (u usageValidateCmd) run(runCtx *config.RunContext) error {
+	err := u.loadRunFlags(runCtx.Config)
+	if err != nil {
+		return err
+	}
+
+	// Group the resources by usage file since projects in a config file can share one.
+	var usageFilePaths []string
+	usageFiles := map[string]*usage.UsageFile{}
+	resources := map[string][]*schema.Resource{}
+
+	for _, projectCfg := range runCtx.Config.Projects {
+		if projectCfg.UsageFile == "" {
+			continue
+		}
+
+		usageFile, ok := usageFiles[projectCfg.UsageFile]
+		if !ok {
+			usageFile, err = usage.LoadUsageFile(projectCfg.UsageFile)
+			if err != nil {
+				return fmt.Errorf("%s: %w", projectCfg.UsageFile, err)
+			}
+
+			usageFiles[projectCfg.UsageFile] = usageFile
+			usageFilePaths = append(usageFilePaths, projectCfg.UsageFile)
+		}
+
+		projectResources, err := u.loadResources(runCtx, projectCfg, usageFile)
+		if err != nil {
+			return err
+		}
+
+		resources[projectCfg.UsageFile] = append(resources[projectCfg.UsageFile], projectResources...)
+	}
+
+	if len(usageFilePaths) == 0 {
+		return errors.New("No usage files found to validate")
+	}
+
+	results := make([]usage.ValidationResult, 0, len(usageFilePaths))
+	hasErrors := false
+
+	for _, path := range usageFilePaths {
+		result, err := usageFiles[path].Validate(path, resources[path])
+		if err != nil {
+			return err
+		}
+
+		hasErrors = hasErrors || result.HasErrors(u.Strict)
+		results = append(results, result)
+	}
+
+	if runCtx.Config.Format == "json" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		u.cmd.Println(string(b))
+	} else {
+		u.cmd.Print(formatValidationResults(results))
+	}
+
+	if hasErrors {
+		return errors.New("Usage file validation failed")
+	}
+
+	return nil
+}
+
+func (u usageValidateCmd) loadResources(runCtx *config.RunContext, projectCfg *config.Project, usageFile *usage.UsageFile) ([]*schema.Resource, error) {
+	projectCtx := config.NewProjectContext(runCtx, projectCfg, log.Fields{})
+
+	provider, err := providers.Detect(projectCtx, false)
+	if v, ok := err.(*providers.ValidationError); ok {
+		if v.Warn() == nil {
+			return nil, err
+		}
+		ui.PrintWarning(runCtx.ErrWriter, *v.Warn())
+	} else if err != nil {
+		return nil, fmt.Errorf("Could not detect path type for %s: %w", projectCfg.Path, err)
+	}
+
+	projects, err := provider.LoadResources(usageFile.ToUsageDataMap())
+	if err != nil {
+		return nil, err
+	}
+
+	schema.BuildResources(projects, nil)
+
+	var resources []*schema.Resource
+	for _, project := range projects {
+		resources = append(resources, project.Resources...)
+	}
+
+	return resources, nil
+}
+
+func formatValidationResults(results []usage.ValidationResult) string {
+	var b strings.Builder
+
+	for _, result := range results {
+		if len(result.Issues) == 0 {
+			b.WriteString(fmt.Sprintf("%s %s\n", ui.SuccessString("✔"), ui.BoldStringf("%s is valid", result.Filename)))
+			continue
+		}
+
+		for _, issue := range result.Issues {
+			severity := ui.WarningString(string(issue.Severity))
+			if issue.Severity == usage.SeverityError {
+				severity = ui.ErrorString(string(issue.Severity))
+			}
+
+			b.WriteString(fmt.Sprintf("%s:%d:%d: %s %s %s\n",
+				result.Filename,
+				issue.Line,
+				issue.Column,
+				severity,
+				issue.Message,
+				ui.FaintStringf("(%s)", issue.Code),
+			))
+		}
+	}
+
+	return b.String()
+}
+
+func usageValidateCommand(ctx *config.RunContext) *cobra.Command {
+	var validate usageValidateCmd
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a usage file against the resources in a project",
+		Long: `Validate a usage file against the resources in a project.
+
+Checks that usage values have the types expected by each resource, are not negative or
+implausible, and that every resource address, wildcard and resource type in the usage file
+still matches a resource in the project.`,
+		Example: `  Validate a usage file against a Terraform directory:
+
+      infracost usage validate --path /code --usage-file infracost-usage.yml
+
+  Validate the usage files of all projects in a config file, failing on warnings too:
+
+      infracost usage validate --config-file infracost.yml --strict --format json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx.Config.Format, _ = cmd.Flags().GetString("format")
+
+			return validate.run(ctx)
+		},
+	}
+
+	validate.cmd = cmd
+	cmd.Flags().StringSliceVar(&validate.TerraformVarFiles, "terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
+	cmd.Flags().StringSliceVar(&validate.TerraformVars, "terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().StringVar(&validate.TerraformWorkspace, "terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+
+	cmd.Flags().StringVarP(&validate.Path, "path", "p", "", "Path to the Terraform directory or JSON/plan file")
+	cmd.Flags().StringVar(&validate.ConfigFile, "config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
+	cmd.Flags().StringVar(&validate.UsageFile, "usage-file", "", "Path to Infracost usage file to validate")
+	cmd.Flags().BoolVar(&validate.Strict, "strict", false, "Exit with a non-zero code on warnings as well as errors")
+	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table"})
+
+	_ = cmd.MarkFlagFilename("path", "json", "tf")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+
+	return cmd
+}
@@ -0,0 +1,32 @@
+package main_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestUsageValidateHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"usage", "validate", "--help"}, nil)
+}
+
+func TestUsageValidateTerraformPlanJSON(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName, []string{
+		"usage", "validate",
+		"--path", "./testdata/example_plan.json",
+		"--usage-file", filepath.Join("./testdata", testName, "infracost-usage.yml"),
+	}, nil)
+}
+
+func TestUsageValidateTerraformPlanJSONFormatJSON(t *testing.T) {
+	opts := DefaultOptions()
+	opts.IsJSON = true
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{
+		"usage", "validate",
+		"--path", "./testdata/example_plan.json",
+		"--usage-file", "./testdata/usage_validate_terraform_plan_json/infracost-usage.yml",
+		"--format", "json",
+	}, opts)
+}
@@ -0,0 +1,280 @@
+package comment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// giteaOutdatedTag marks comments that have been hidden. Gitea doesn't have a
+// feature for hiding comments so they're hidden by editing them to collapse
+// the body.
+var giteaOutdatedTag = "infracost-outdated"
+
+// giteaComment represents a comment on a Gitea pull request. It implements
+// the Comment interface.
+type giteaComment struct {
+	id        int64
+	body      string
+	createdAt string
+	url       string
+}
+
+// Body returns the body of the comment
+func (c *giteaComment) Body() string {
+	return c.body
+}
+
+// Ref returns the reference to the comment. For Gitea this is an API URL of
+// the comment.
+func (c *giteaComment) Ref() string {
+	return c.url
+}
+
+// Less compares the comment to another comment and returns true if this
+// comment should be sorted before the other comment.
+func (c *giteaComment) Less(other Comment) bool {
+	j := other.(*giteaComment)
+
+	if c.createdAt != j.createdAt {
+		return c.createdAt < j.createdAt
+	}
+
+	return c.id < j.id
+}
+
+// IsHidden returns true if the comment has been edited to hide it.
+func (c *giteaComment) IsHidden() bool {
+	return strings.HasPrefix(c.body, markdownTag(giteaOutdatedTag))
+}
+
+// GiteaExtra contains any extra inputs that can be passed to the Gitea
+// comment handlers.
+type GiteaExtra struct {
+	// ServerURL is the URL of the Gitea or Forgejo server.
+	ServerURL string
+	// Token is the Gitea access token.
+	Token string
+	// Tag is used to identify the Infracost comment.
+	Tag string
+}
+
+// giteaAPIComment represents API response structure of Gitea comment.
+type giteaAPIComment struct {
+	ID        int64  `json:"id"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// newGiteaAPIClient creates a HTTP client.
+func newGiteaAPIClient(ctx context.Context, token string) (*http.Client, error) {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{
+			AccessToken: token,
+			TokenType:   "token",
+		},
+	)
+	httpClient := oauth2.NewClient(ctx, ts)
+
+	return httpClient, nil
+}
+
+// giteaPRHandler is a PlatformHandler for Gitea pull requests. It
+// implements the PlatformHandler interface and contains the functions
+// for finding, creating, updating, deleting and hiding comments on Gitea pull
+// requests. Forgejo has the same API so it's supported by this handler too.
+type giteaPRHandler struct {
+	httpClient *http.Client
+	apiURL     string
+	prNumber   int
+}
+
+// NewGiteaPRHandler creates a new PlatformHandler for Gitea pull requests.
+func NewGiteaPRHandler(ctx context.Context, repo string, targetRef string, extra GiteaExtra) (*CommentHandler, error) {
+	prNumber, err := strconv.Atoi(targetRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing targetRef as pull request number")
+	}
+
+	if extra.ServerURL == "" {
+		return nil, errors.New("Gitea server URL is required")
+	}
+
+	httpClient, err := newGiteaAPIClient(ctx, extra.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &giteaPRHandler{
+		httpClient: httpClient,
+		apiURL:     fmt.Sprintf("%s/api/v1/repos/%s/", strings.TrimSuffix(extra.ServerURL, "/"), repo),
+		prNumber:   prNumber,
+	}
+
+	return NewCommentHandler(ctx, h, extra.Tag), nil
+}
+
+// CallFindMatchingComments calls the Gitea API to find the pull request
+// comments that match the given tag, which has been embedded at the beginning
+// of the comment.
+func (h *giteaPRHandler) CallFindMatchingComments(ctx context.Context, tag string) ([]Comment, error) {
+	url := fmt.Sprintf("%sissues/%d/comments", h.apiURL, h.prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error getting comments")
+	}
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error getting comments")
+	}
+
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return []Comment{}, errors.Errorf("Error getting comments: %s", res.Status)
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error reading response body")
+	}
+
+	var resData []giteaAPIComment
+
+	err = json.Unmarshal(resBody, &resData)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error unmarshaling response body")
+	}
+
+	matchingComments := []Comment{}
+
+	for _, c := range resData {
+		if !strings.Contains(c.Body, markdownTag(tag)) {
+			continue
+		}
+
+		matchingComments = append(matchingComments, h.newComment(c))
+	}
+
+	return matchingComments, nil
+}
+
+// CallCreateComment calls the Gitea API to create a new comment on the pull request.
+func (h *giteaPRHandler) CallCreateComment(ctx context.Context, body string) (Comment, error) {
+	url := fmt.Sprintf("%sissues/%d/comments", h.apiURL, h.prNumber)
+
+	resBody, err := h.sendComment(ctx, "POST", url, body, http.StatusCreated)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating comment")
+	}
+
+	resData := giteaAPIComment{}
+
+	err = json.Unmarshal(resBody, &resData)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error unmarshaling response body")
+	}
+
+	return h.newComment(resData), nil
+}
+
+// CallUpdateComment calls the Gitea API to update the body of a comment on the pull request.
+func (h *giteaPRHandler) CallUpdateComment(ctx context.Context, comment Comment, body string) error {
+	_, err := h.sendComment(ctx, "PATCH", comment.Ref(), body, http.StatusOK)
+	if err != nil {
+		return errors.Wrap(err, "Error updating comment")
+	}
+
+	return nil
+}
+
+// CallDeleteComment calls the Gitea API to delete the pull request comment.
+func (h *giteaPRHandler) CallDeleteComment(ctx context.Context, comment Comment) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", comment.Ref(), nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating request")
+	}
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error deleting comment")
+	}
+
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		return errors.Errorf("Error deleting comment: %s", res.Status)
+	}
+
+	return nil
+}
+
+// CallHideComment calls the Gitea API to edit the pull request comment so that
+// its body is collapsed and marked as outdated. The body is kept so the
+// comment still matches the tag.
+func (h *giteaPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
+	body := fmt.Sprintf("%s\n<details>\n<summary>This comment is outdated</summary>\n\n%s\n</details>", markdownTag(giteaOutdatedTag), comment.Body())
+
+	return h.CallUpdateComment(ctx, comment, body)
+}
+
+// AddMarkdownTag prepends a tag as a markdown comment to the given string.
+func (h *giteaPRHandler) AddMarkdownTag(s string, tag string) string {
+	return addMarkdownTag(s, tag)
+}
+
+// sendComment sends the body of a comment to the Gitea API and returns the
+// response body.
+func (h *giteaPRHandler) sendComment(ctx context.Context, method string, url string, body string, wantStatus int) ([]byte, error) {
+	reqData, err := json.Marshal(map[string]string{
+		"body": body,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshaling comment body")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqData))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
+	if res.StatusCode != wantStatus {
+		return nil, errors.New(res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func (h *giteaPRHandler) newComment(c giteaAPIComment) *giteaComment {
+	return &giteaComment{
+		id:        c.ID,
+		body:      c.Body,
+		createdAt: c.CreatedAt,
+		url:       fmt.Sprintf("%sissues/comments/%d", h.apiURL, c.ID),
+	}
+}
@@ -0,0 +1,163 @@
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGiteaServer stores the comments of a single pull request in memory.
+type fakeGiteaServer struct {
+	mu       sync.Mutex
+	comments []giteaAPIComment
+	nextID   int64
+}
+
+func (s *fakeGiteaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "token gitea-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var reqData struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&reqData)
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/v1/repos/my-org/my-repo/issues/3/comments":
+		_ = json.NewEncoder(w).Encode(s.comments)
+	case r.Method == "POST" && r.URL.Path == "/api/v1/repos/my-org/my-repo/issues/3/comments":
+		s.nextID++
+		c := giteaAPIComment{
+			ID:        s.nextID,
+			Body:      reqData.Body,
+			CreatedAt: fmt.Sprintf("2024-01-01T00:00:%02dZ", s.nextID),
+		}
+		s.comments = append(s.comments, c)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(c)
+	case strings.HasPrefix(r.URL.Path, "/api/v1/repos/my-org/my-repo/issues/comments/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/repos/my-org/my-repo/issues/comments/")
+		for i, c := range s.comments {
+			if fmt.Sprint(c.ID) != id {
+				continue
+			}
+
+			if r.Method == "DELETE" {
+				s.comments = append(s.comments[:i], s.comments[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			s.comments[i].Body = reqData.Body
+			_ = json.NewEncoder(w).Encode(s.comments[i])
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeGiteaServer) bodies() []string {
+	var bodies []string
+	for _, c := range s.comments {
+		bodies = append(bodies, c.Body)
+	}
+
+	return bodies
+}
+
+func TestGiteaPRHandlerBehaviors(t *testing.T) {
+	tag := markdownTag(defaultTag)
+	outdated := func(body string) string {
+		return fmt.Sprintf("%s\n<details>\n<summary>This comment is outdated</summary>\n\n%s\n%s\n</details>", markdownTag(giteaOutdatedTag), tag, body)
+	}
+
+	tests := []struct {
+		behavior string
+		want     []string
+	}{
+		{
+			behavior: "update",
+			want:     []string{tag + "\nfirst", tag + "\nthird"},
+		},
+		{
+			behavior: "new",
+			want:     []string{tag + "\nfirst", tag + "\nsecond", tag + "\nthird"},
+		},
+		{
+			behavior: "hide-and-new",
+			want:     []string{outdated("first"), outdated("second"), tag + "\nthird"},
+		},
+		{
+			behavior: "delete-and-new",
+			want:     []string{"unrelated", tag + "\nthird"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			s := &fakeGiteaServer{}
+			ts := httptest.NewServer(s)
+			defer ts.Close()
+
+			ctx := context.Background()
+			h, err := NewGiteaPRHandler(ctx, "my-org/my-repo", "3", GiteaExtra{
+				ServerURL: ts.URL + "/",
+				Token:     "gitea-token",
+			})
+			require.NoError(t, err)
+
+			for _, body := range []string{"first", "second"} {
+				_, err = h.CommentWithBehavior(ctx, false, "new", body)
+				require.NoError(t, err)
+			}
+			if tt.behavior == "delete-and-new" {
+				s.comments = append([]giteaAPIComment{{ID: 100, Body: "unrelated", CreatedAt: "2023-01-01T00:00:00Z"}}, s.comments...)
+			}
+
+			posted, err := h.CommentWithBehavior(ctx, false, tt.behavior, "third")
+			require.NoError(t, err)
+			assert.True(t, posted)
+			assert.Equal(t, tt.want, s.bodies())
+		})
+	}
+}
+
+func TestGiteaPRHandlerHideAndNewSkipsHiddenComments(t *testing.T) {
+	s := &fakeGiteaServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ctx := context.Background()
+	h, err := NewGiteaPRHandler(ctx, "my-org/my-repo", "3", GiteaExtra{
+		ServerURL: ts.URL,
+		Token:     "gitea-token",
+		Tag:       "my-tag",
+	})
+	require.NoError(t, err)
+
+	for _, body := range []string{"first", "second", "third"} {
+		_, err = h.CommentWithBehavior(ctx, false, "hide-and-new", body)
+		require.NoError(t, err)
+	}
+
+	bodies := s.bodies()
+	require.Len(t, bodies, 3)
+	assert.Equal(t, 1, strings.Count(bodies[0], markdownTag(giteaOutdatedTag)))
+	assert.Equal(t, 1, strings.Count(bodies[1], markdownTag(giteaOutdatedTag)))
+	assert.Equal(t, markdownTag("my-tag")+"\nthird", bodies[2])
+}
@@ -0,0 +1,155 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// maxGitHubCheckRunAnnotations is the number of annotations that GitHub
+// accepts in each request that creates or updates a check run.
+const maxGitHubCheckRunAnnotations = 50
+
+// GitHubCheckRun is a completed check run that reports the cost estimate on a
+// commit.
+type GitHubCheckRun struct {
+	// Name of the check, this is shown in the list of checks of the commit.
+	Name string
+	// Conclusion is one of success, neutral or failure.
+	Conclusion string
+	Title      string
+	// Summary is the markdown shown on the page of the check run.
+	Summary     string
+	Annotations []GitHubCheckRunAnnotation
+}
+
+// GitHubCheckRunAnnotation is shown on the lines of a file in the diff of the
+// pull request.
+type GitHubCheckRunAnnotation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	// Level is one of notice, warning or failure.
+	Level   string
+	Title   string
+	Message string
+}
+
+// GitHubCheckRunHandler creates check runs on the head commit of a GitHub pull
+// request or on a commit.
+type GitHubCheckRunHandler struct {
+	v3client *github.Client
+	owner    string
+	repo     string
+	prNumber int
+	commit   string
+}
+
+// NewGitHubCheckRunHandler creates a new GitHubCheckRunHandler. The check run
+// is created on the head commit of the pull request if prNumber is set,
+// otherwise it's created on the commit.
+func NewGitHubCheckRunHandler(ctx context.Context, project string, prNumber int, commit string, extra GitHubExtra) (*GitHubCheckRunHandler, error) {
+	owner, repo, err := splitGitHubProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	v3client, _, err := newGitHubAPIClients(ctx, extra.Token, extra.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubCheckRunHandler{
+		v3client: v3client,
+		owner:    owner,
+		repo:     repo,
+		prNumber: prNumber,
+		commit:   commit,
+	}, nil
+}
+
+// CreateCheckRun creates the check run and returns the URL of its HTML page.
+// GitHub only accepts 50 annotations in each request, so any more are added by
+// updating the check run.
+func (h *GitHubCheckRunHandler) CreateCheckRun(ctx context.Context, checkRun GitHubCheckRun) (string, error) {
+	sha, err := h.headSHA(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	batches := batchGitHubCheckRunAnnotations(checkRun.Annotations)
+
+	created, _, err := h.v3client.Checks.CreateCheckRun(ctx, h.owner, h.repo, github.CreateCheckRunOptions{
+		Name:        checkRun.Name,
+		HeadSHA:     sha,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(checkRun.Conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output:      checkRunOutput(checkRun, batches[0]),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating check run")
+	}
+
+	for _, batch := range batches[1:] {
+		_, _, err = h.v3client.Checks.UpdateCheckRun(ctx, h.owner, h.repo, created.GetID(), github.UpdateCheckRunOptions{
+			Name:   checkRun.Name,
+			Output: checkRunOutput(checkRun, batch),
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "Error adding annotations to check run")
+		}
+	}
+
+	return created.GetHTMLURL(), nil
+}
+
+// headSHA returns the SHA of the commit that the check run is created on.
+func (h *GitHubCheckRunHandler) headSHA(ctx context.Context) (string, error) {
+	if h.prNumber == 0 {
+		return h.commit, nil
+	}
+
+	pr, _, err := h.v3client.PullRequests.Get(ctx, h.owner, h.repo, h.prNumber)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting pull request")
+	}
+
+	return pr.GetHead().GetSHA(), nil
+}
+
+// batchGitHubCheckRunAnnotations splits the annotations into batches that can
+// be sent in each request. There is always at least one batch so the check run
+// is created even if there are no annotations.
+func batchGitHubCheckRunAnnotations(annotations []GitHubCheckRunAnnotation) [][]*github.CheckRunAnnotation {
+	batches := [][]*github.CheckRunAnnotation{nil}
+
+	for _, a := range annotations {
+		last := len(batches) - 1
+		if len(batches[last]) == maxGitHubCheckRunAnnotations {
+			batches = append(batches, nil)
+			last++
+		}
+
+		batches[last] = append(batches[last], &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(a.EndLine),
+			AnnotationLevel: github.String(a.Level),
+			Title:           github.String(a.Title),
+			Message:         github.String(a.Message),
+		})
+	}
+
+	return batches
+}
+
+func checkRunOutput(checkRun GitHubCheckRun, annotations []*github.CheckRunAnnotation) *github.CheckRunOutput {
+	return &github.CheckRunOutput{
+		Title:       github.String(checkRun.Title),
+		Summary:     github.String(checkRun.Summary),
+		Annotations: annotations,
+	}
+}
@@ -0,0 +1,35 @@
+package comment
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_batchGitHubCheckRunAnnotations(t *testing.T) {
+	tests := []struct {
+		annotations int
+		want        []int
+	}{
+		{annotations: 0, want: []int{0}},
+		{annotations: 50, want: []int{50}},
+		{annotations: 120, want: []int{50, 50, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d annotations", tt.annotations), func(t *testing.T) {
+			annotations := make([]GitHubCheckRunAnnotation, tt.annotations)
+			for i := range annotations {
+				annotations[i] = GitHubCheckRunAnnotation{Path: "main.tf", StartLine: i + 1, EndLine: i + 1, Level: "notice"}
+			}
+
+			batches := batchGitHubCheckRunAnnotations(annotations)
+
+			got := make([]int, len(batches))
+			for i, batch := range batches {
+				got[i] = len(batch)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
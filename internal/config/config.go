@@ -54,9 +54,59 @@ type Project struct {
 	TerragruntFlags string `envconfig:"TERRAGRUNT_FLAGS"`
 	// UsageFile is the full path to usage file that specifies values for usage-based resources
 	UsageFile string `yaml:"usage_file,omitempty" ignored:"true"`
+	// DataMocks is the path to a file of attribute values for Terraform data sources, relative to the project path.
+	DataMocks string `yaml:"data_mocks,omitempty" ignored:"true"`
+	// RemoteStates are the sources of the outputs for the terraform_remote_state data sources of the project.
+	RemoteStates []RemoteState `yaml:"remote_states,omitempty" ignored:"true"`
+	// ModuleMirror is the path to a module mirror directory that registry and remote modules are loaded from
+	// instead of being downloaded, relative to the config file or root path. See modules.Mirror.
+	ModuleMirror string `yaml:"module_mirror,omitempty" envconfig:"MODULE_MIRROR"`
+	// Matrix evaluates a Terraform directory once for each combination of its workspaces and var file
+	// sets, with a project for each combination. See ProjectMatrix.
+	Matrix *ProjectMatrix `yaml:"matrix,omitempty" ignored:"true"`
 	// TerraformUseState sets if the users wants to use the terraform state for infracost ops.
-	TerraformUseState bool              `yaml:"terraform_use_state,omitempty" ignored:"true"`
-	Env               map[string]string `yaml:"env,omitempty" ignored:"true"`
+	TerraformUseState bool `yaml:"terraform_use_state,omitempty" ignored:"true"`
+	// CloudFormationParameters sets the values of parameters in a CloudFormation template.
+	CloudFormationParameters map[string]string `yaml:"cloudformation_parameters,omitempty" ignored:"true"`
+	// CloudFormationParameterFiles are JSON files of parameter values for a CloudFormation template.
+	CloudFormationParameterFiles []string          `yaml:"cloudformation_parameter_files,omitempty" ignored:"true"`
+	Env                          map[string]string `yaml:"env,omitempty" ignored:"true"`
+}
+
+// RemoteState maps terraform_remote_state data sources to a local state JSON file or to
+// another project in the same run that provides their outputs. Data sources are matched
+// by Address, by Backend and Config, or by both.
+type RemoteState struct {
+	// Address of the data sources, e.g. data.terraform_remote_state.network.
+	Address string `yaml:"address,omitempty"`
+	// Backend is the backend type of the data sources, e.g. s3.
+	Backend string `yaml:"backend,omitempty"`
+	// Config are backend config values that the data sources must have, e.g. key: network/terraform.tfstate.
+	Config map[string]string `yaml:"config,omitempty"`
+	// StateFile is the path to a Terraform state or state JSON file, relative to the project path.
+	StateFile string `yaml:"state_file,omitempty"`
+	// Project is the name of another project in the config file whose outputs are used.
+	Project string `yaml:"project,omitempty"`
+}
+
+// ProjectMatrix lists the workspaces and var file sets that a Terraform directory is evaluated with.
+// Each combination of a workspace and a var file set is evaluated as a project named after them.
+type ProjectMatrix struct {
+	// Workspaces to evaluate the directory with. Defaults to the terraform_workspace of the project.
+	Workspaces []string `yaml:"workspaces,omitempty"`
+	// VarFiles are named sets of var files to evaluate the directory with, in addition to the
+	// terraform_var_files of the project. Paths are relative to the project path.
+	VarFiles []MatrixVarFiles `yaml:"var_files,omitempty"`
+	// AutodetectVarFiles adds a var file set for each *.tfvars or *.tfvars.json file in the project
+	// path, named after the file. terraform.tfvars and *.auto.tfvars files are always loaded so are
+	// not added.
+	AutodetectVarFiles bool `yaml:"autodetect_var_files,omitempty"`
+}
+
+// MatrixVarFiles is a named set of var files in a ProjectMatrix.
+type MatrixVarFiles struct {
+	Name  string   `yaml:"name"`
+	Files []string `yaml:"files"`
 }
 
 type Config struct {
@@ -100,14 +150,19 @@ type Config struct {
 	// Org settings
 	EnableCloudForOrganization bool
 
-	Projects        []*Project `yaml:"projects" ignored:"true"`
-	Format          string     `yaml:"format,omitempty" ignored:"true"`
-	ShowAllProjects bool       `yaml:"show_all_projects,omitempty" ignored:"true"`
-	ShowSkipped     bool       `yaml:"show_skipped,omitempty" ignored:"true"`
-	SyncUsageFile   bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
-	Fields          []string   `yaml:"fields,omitempty" ignored:"true"`
-	CompareTo       string
-	GitDiffTarget   *string
+	Projects          []*Project   `yaml:"projects" ignored:"true"`
+	Format            string       `yaml:"format,omitempty" ignored:"true"`
+	ShowAllProjects   bool         `yaml:"show_all_projects,omitempty" ignored:"true"`
+	ShowSkipped       bool         `yaml:"show_skipped,omitempty" ignored:"true"`
+	HCLDiagnostics    bool         `yaml:"hcl_diagnostics,omitempty" ignored:"true"`
+	IncludeAttributes bool         `yaml:"include_attributes,omitempty" ignored:"true"`
+	SyncUsageFile     bool         `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	Fields            []string     `yaml:"fields,omitempty" ignored:"true"`
+	GroupBy           []string     `yaml:"group_by,omitempty" ignored:"true"`
+	TagPolicy         *TagPolicy   `yaml:"tag_policy,omitempty" ignored:"true"`
+	Guardrails        []*Guardrail `yaml:"guardrails,omitempty" ignored:"true"`
+	CompareTo         string
+	GitDiffTarget     *string
 
 	// Base configuration settings
 	// RootPath defines the raw value of the `--path` flag provided by the user
@@ -171,6 +226,8 @@ func (c *Config) LoadFromConfigFile(path string) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	c.TagPolicy = cfgFile.TagPolicy
+	c.Guardrails = cfgFile.Guardrails
 
 	// Reload the environment to overwrite any of the config file configs
 	err = c.LoadFromEnv()
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -109,8 +111,10 @@ func (y *YamlError) Error() string {
 }
 
 type fileSpec struct {
-	Version  string     `yaml:"version"`
-	Projects []*Project `yaml:"projects" ignored:"true"`
+	Version    string       `yaml:"version"`
+	Projects   []*Project   `yaml:"projects" ignored:"true"`
+	TagPolicy  *TagPolicy   `yaml:"tag_policy,omitempty" ignored:"true"`
+	Guardrails []*Guardrail `yaml:"guardrails,omitempty" ignored:"true"`
 }
 
 // UnmarshalYAML implements the yaml.v2.Unmarshaller interface. Marshalls the
@@ -201,11 +205,242 @@ func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return &YamlError{raw: ErrorInvalidConfigFile}
 	}
 
+	if remoteStateErr := validateRemoteStates(c.Projects); remoteStateErr.isValid() {
+		return remoteStateErr
+	}
+
+	if matrixErr := validateMatrices(c.Projects); matrixErr.isValid() {
+		return matrixErr
+	}
+
+	if tagPolicyErr := validateTagPolicy(c.TagPolicy); tagPolicyErr.isValid() {
+		return tagPolicyErr
+	}
+
+	if guardrailsErr := validateGuardrails(c.Guardrails); guardrailsErr.isValid() {
+		return guardrailsErr
+	}
+
 	f.Version = c.Version
 	f.Projects = c.Projects
+	f.TagPolicy = c.TagPolicy
+	f.Guardrails = c.Guardrails
 	return nil
 }
 
+// validateRemoteStates checks that the remote states of each project match data sources
+// and have a single source of outputs, which must be a project in the file.
+func validateRemoteStates(projects []*Project) *YamlError {
+	validationError := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	names := make(map[string]struct{}, len(projects))
+	for _, p := range projects {
+		if p.Name != "" {
+			names[p.Name] = struct{}{}
+		}
+	}
+
+	for _, p := range projects {
+		projectError := &YamlError{
+			base: fmt.Sprintf("project config defined for path: [%s] is invalid", p.Path),
+		}
+
+		for i, state := range p.RemoteStates {
+			if state.Address == "" && state.Backend == "" {
+				projectError.add(fmt.Errorf("remote_states at index %d must have an address or backend", i))
+			}
+
+			if (state.StateFile == "") == (state.Project == "") {
+				projectError.add(fmt.Errorf("remote_states at index %d must have either a state_file or project", i))
+				continue
+			}
+
+			if state.Project == "" {
+				continue
+			}
+
+			if _, ok := names[state.Project]; !ok {
+				projectError.add(fmt.Errorf("remote_states at index %d references unknown project %s", i, state.Project))
+			} else if state.Project == p.Name {
+				projectError.add(fmt.Errorf("remote_states at index %d references its own project", i))
+			}
+		}
+
+		if projectError.isValid() {
+			validationError.add(projectError)
+		}
+	}
+
+	return validationError
+}
+
+// validateMatrices checks that the matrix of each project has at least one
+// dimension and that its workspaces and var file sets are unique.
+func validateMatrices(projects []*Project) *YamlError {
+	validationError := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	for _, p := range projects {
+		m := p.Matrix
+		if m == nil {
+			continue
+		}
+
+		projectError := &YamlError{
+			base: fmt.Sprintf("project config defined for path: [%s] is invalid", p.Path),
+		}
+
+		if len(m.Workspaces) == 0 && len(m.VarFiles) == 0 && !m.AutodetectVarFiles {
+			projectError.add(fmt.Errorf("matrix must have workspaces, var_files or autodetect_var_files"))
+		}
+
+		if p.TerraformForceCLI {
+			projectError.add(fmt.Errorf("matrix cannot be used with terraform_force_cli"))
+		}
+
+		workspaces := make(map[string]struct{}, len(m.Workspaces))
+		for i, workspace := range m.Workspaces {
+			if strings.TrimSpace(workspace) == "" {
+				projectError.add(fmt.Errorf("matrix workspace at index %d must not be empty", i))
+				continue
+			}
+
+			if _, ok := workspaces[workspace]; ok {
+				projectError.add(fmt.Errorf("matrix workspace %s is duplicated", workspace))
+			}
+			workspaces[workspace] = struct{}{}
+		}
+
+		names := make(map[string]struct{}, len(m.VarFiles))
+		for i, varFiles := range m.VarFiles {
+			if varFiles.Name == "" {
+				projectError.add(fmt.Errorf("matrix var_files at index %d must have a name", i))
+			} else if _, ok := names[varFiles.Name]; ok {
+				projectError.add(fmt.Errorf("matrix var_files name %s is duplicated", varFiles.Name))
+			}
+			names[varFiles.Name] = struct{}{}
+
+			if len(varFiles.Files) == 0 {
+				projectError.add(fmt.Errorf("matrix var_files at index %d must have files", i))
+			}
+		}
+
+		if projectError.isValid() {
+			validationError.add(projectError)
+		}
+	}
+
+	return validationError
+}
+
+// validateTagPolicy checks that each required tag has a key, that its allowed
+// values are a valid regex and that its resource types are valid patterns.
+func validateTagPolicy(policy *TagPolicy) *YamlError {
+	validationError := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	if policy == nil {
+		return validationError
+	}
+
+	policyError := &YamlError{
+		base: "tag_policy is invalid",
+	}
+
+	for i, tag := range policy.RequiredTags {
+		if tag == nil || strings.TrimSpace(tag.Key) == "" {
+			policyError.add(fmt.Errorf("required_tags at index %d must have a key", i))
+			continue
+		}
+
+		if tag.AllowedValues != "" {
+			re, err := regexp.Compile(tag.AllowedValues)
+			if err != nil {
+				policyError.add(fmt.Errorf("required tag %s has invalid allowed_values regex: %s", tag.Key, err))
+			}
+			tag.allowedValues = re
+		}
+
+		for _, pattern := range tag.ResourceTypes {
+			if _, err := path.Match(pattern, ""); err != nil {
+				policyError.add(fmt.Errorf("required tag %s has invalid resource type pattern %s", tag.Key, pattern))
+			}
+		}
+	}
+
+	if policyError.isValid() {
+		validationError.add(policyError)
+	}
+
+	return validationError
+}
+
+// validateGuardrails checks that each guardrail has a name, a threshold, a
+// valid action and at most one of project, tag and resource_type.
+func validateGuardrails(guardrails []*Guardrail) *YamlError {
+	validationError := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	for i, g := range guardrails {
+		if g == nil {
+			validationError.add(fmt.Errorf("guardrail at index %d is empty", i))
+			continue
+		}
+
+		guardrailError := &YamlError{
+			base: fmt.Sprintf("guardrail at index %d is invalid", i),
+		}
+		if g.Name != "" {
+			guardrailError.base = fmt.Sprintf("guardrail %s is invalid", g.Name)
+		} else {
+			guardrailError.add(errors.New("guardrail must have a name"))
+		}
+
+		if g.TotalMonthlyCost == nil && g.DiffMonthlyCost == nil && g.DiffPercentage == nil {
+			guardrailError.add(errors.New("guardrail must have total_monthly_cost, diff_monthly_cost or diff_percentage"))
+		}
+
+		scopes := 0
+		for _, scope := range []string{g.Project, g.Tag, g.ResourceType} {
+			if scope != "" {
+				scopes++
+			}
+		}
+		if scopes > 1 {
+			guardrailError.add(errors.New("guardrail can only have one of project, tag and resource_type"))
+		}
+
+		for _, pattern := range []string{g.Project, g.ResourceType} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				guardrailError.add(fmt.Errorf("%s is not a valid pattern", pattern))
+			}
+		}
+
+		if key, _, _ := strings.Cut(g.Tag, "="); g.Tag != "" && strings.TrimSpace(key) == "" {
+			guardrailError.add(fmt.Errorf("tag %s must be a key or key=value", g.Tag))
+		}
+
+		switch g.Action {
+		case "":
+			g.Action = GuardrailActionWarn
+		case GuardrailActionWarn, GuardrailActionBlock:
+		default:
+			guardrailError.add(fmt.Errorf("action %s is not valid, valid actions are %s and %s", g.Action, GuardrailActionWarn, GuardrailActionBlock))
+		}
+
+		if guardrailError.isValid() {
+			validationError.add(guardrailError)
+		}
+	}
+
+	return validationError
+}
+
 func loadConfigFile(path string) (fileSpec, error) {
 	var cfgFile fileSpec
 
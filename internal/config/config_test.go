@@ -86,6 +86,130 @@ projects:
 				},
 			},
 		},
+		{
+			name: "should parse remote states",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/network
+    name: network
+  - path: path/to/app
+    remote_states:
+      - address: data.terraform_remote_state.network
+        project: network
+      - backend: s3
+        config:
+          key: shared/terraform.tfstate
+        state_file: shared.tfstate.json
+`),
+			expected: []*Project{
+				{
+					Path: "path/to/network",
+					Name: "network",
+				},
+				{
+					Path: "path/to/app",
+					RemoteStates: []RemoteState{
+						{Address: "data.terraform_remote_state.network", Project: "network"},
+						{Backend: "s3", Config: map[string]string{"key": "shared/terraform.tfstate"}, StateFile: "shared.tfstate.json"},
+					},
+				},
+			},
+		},
+		{
+			name: "should error invalid remote states",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+    name: app
+    remote_states:
+      - state_file: network.tfstate
+      - address: data.terraform_remote_state.network
+        project: network
+        state_file: network.tfstate
+      - address: data.terraform_remote_state.network
+        project: missing
+      - address: data.terraform_remote_state.app
+        project: app
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base: "project config defined for path: [path/to/app] is invalid",
+						errors: []error{
+							errors.New("remote_states at index 0 must have an address or backend"),
+							errors.New("remote_states at index 1 must have either a state_file or project"),
+							errors.New("remote_states at index 2 references unknown project missing"),
+							errors.New("remote_states at index 3 references its own project"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should parse matrix",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+    matrix:
+      workspaces: [dev, prod]
+      var_files:
+        - name: us
+          files: [env/us.tfvars]
+      autodetect_var_files: true
+`),
+			expected: []*Project{
+				{
+					Path: "path/to/app",
+					Matrix: &ProjectMatrix{
+						Workspaces:         []string{"dev", "prod"},
+						VarFiles:           []MatrixVarFiles{{Name: "us", Files: []string{"env/us.tfvars"}}},
+						AutodetectVarFiles: true,
+					},
+				},
+			},
+		},
+		{
+			name: "should error invalid matrix",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+    terraform_force_cli: true
+    matrix:
+      workspaces: [dev, dev, ""]
+      var_files:
+        - name: us
+        - name: us
+          files: [env/us.tfvars]
+  - path: path/to/network
+    matrix: {}
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base: "project config defined for path: [path/to/app] is invalid",
+						errors: []error{
+							errors.New("matrix cannot be used with terraform_force_cli"),
+							errors.New("matrix workspace dev is duplicated"),
+							errors.New("matrix workspace at index 2 must not be empty"),
+							errors.New("matrix var_files at index 0 must have files"),
+							errors.New("matrix var_files name us is duplicated"),
+						},
+					},
+					&YamlError{
+						base: "project config defined for path: [path/to/network] is invalid",
+						errors: []error{
+							errors.New("matrix must have workspaces, var_files or autodetect_var_files"),
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "should error invalid version given",
 			contents: []byte(`version: 81923.1
@@ -129,3 +253,122 @@ projects:
 		})
 	}
 }
+
+func TestConfigLoadTagPolicyFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+
+tag_policy:
+  required_tags:
+    - key: cost-center
+      allowed_values: ^CC-[0-9]+$
+      resource_types: [aws_*]
+    - key: owner
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	c := Config{}
+	err = c.LoadFromConfigFile(path)
+	require.NoError(t, err)
+	require.NotNil(t, c.TagPolicy)
+	require.Len(t, c.TagPolicy.RequiredTags, 2)
+
+	costCenter := c.TagPolicy.RequiredTags[0]
+	require.True(t, costCenter.AppliesTo("aws_instance"))
+	require.False(t, costCenter.AppliesTo("google_compute_instance"))
+	require.True(t, costCenter.Allows("CC-123"))
+	require.False(t, costCenter.Allows("finance"))
+
+	owner := c.TagPolicy.RequiredTags[1]
+	require.True(t, owner.AppliesTo("google_compute_instance"))
+	require.True(t, owner.Allows(""))
+}
+
+func TestConfigLoadInvalidTagPolicyFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+
+tag_policy:
+  required_tags:
+    - allowed_values: ^team-
+    - key: cost-center
+      allowed_values: "[0-9"
+      resource_types: ["aws_[instance"]
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	c := Config{}
+	err = c.LoadFromConfigFile(path)
+	require.Error(t, err)
+	require.Equal(t, `config file is invalid, see https://infracost.io/config-file for valid options:
+	tag_policy is invalid:
+		required_tags at index 0 must have a key
+		required tag cost-center has invalid allowed_values regex: error parsing regexp: missing closing ]: `+"`[0-9`"+`
+		required tag cost-center has invalid resource type pattern aws_[instance`, err.Error())
+}
+
+func TestConfigLoadGuardrailsFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+
+guardrails:
+  - name: Total budget
+    total_monthly_cost: 1000
+    action: block
+  - name: Team increase
+    tag: team
+    diff_percentage: 20
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	c := Config{}
+	err = c.LoadFromConfigFile(path)
+	require.NoError(t, err)
+
+	require.Len(t, c.Guardrails, 2)
+	require.Equal(t, "Total budget", c.Guardrails[0].Name)
+	require.Equal(t, 1000.0, *c.Guardrails[0].TotalMonthlyCost)
+	require.True(t, c.Guardrails[0].Blocks())
+	require.Equal(t, "team", c.Guardrails[1].Tag)
+	require.Equal(t, 20.0, *c.Guardrails[1].DiffPercentage)
+	require.Equal(t, GuardrailActionWarn, c.Guardrails[1].Action)
+	require.False(t, c.Guardrails[1].Blocks())
+}
+
+func TestConfigLoadInvalidGuardrailsFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+
+projects:
+  - path: path/to/app
+
+guardrails:
+  - total_monthly_cost: 100
+  - name: Databases
+    project: infra/*
+    resource_type: "aws_[db"
+    action: fail
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	c := Config{}
+	err = c.LoadFromConfigFile(path)
+	require.Error(t, err)
+	require.Equal(t, `config file is invalid, see https://infracost.io/config-file for valid options:
+	guardrail at index 0 is invalid:
+		guardrail must have a name
+	guardrail Databases is invalid:
+		guardrail must have total_monthly_cost, diff_monthly_cost or diff_percentage
+		guardrail can only have one of project, tag and resource_type
+		aws_[db is not a valid pattern
+		action fail is not valid, valid actions are warn and block`, err.Error())
+}
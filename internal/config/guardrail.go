@@ -0,0 +1,42 @@
+package config
+
+const (
+	// GuardrailActionWarn reports the failures of a guardrail without failing the run.
+	GuardrailActionWarn = "warn"
+	// GuardrailActionBlock reports the failures of a guardrail and fails the run
+	// with a non-zero exit code.
+	GuardrailActionBlock = "block"
+)
+
+// Guardrail is a cost threshold that is checked locally against the estimate.
+// It is set with the guardrails section of the config file. A guardrail checks
+// the total costs of all projects unless it is limited to projects, a tag or
+// resource types, in which case each of them is checked separately.
+type Guardrail struct {
+	// Name of the guardrail used in failure messages.
+	Name string `yaml:"name"`
+	// Project limits the guardrail to the projects with names that match the
+	// pattern, e.g. infra/prod or infra/*.
+	Project string `yaml:"project,omitempty"`
+	// Tag limits the guardrail to the resources with the tag, given as key or
+	// key=value. The resources with each value of the tag are checked separately.
+	Tag string `yaml:"tag,omitempty"`
+	// ResourceType limits the guardrail to resource types that match the
+	// pattern, e.g. aws_db_instance or aws_rds_*.
+	ResourceType string `yaml:"resource_type,omitempty"`
+	// TotalMonthlyCost fails the guardrail if the monthly cost is above it.
+	TotalMonthlyCost *float64 `yaml:"total_monthly_cost,omitempty"`
+	// DiffMonthlyCost fails the guardrail if the monthly cost increases by more
+	// than it.
+	DiffMonthlyCost *float64 `yaml:"diff_monthly_cost,omitempty"`
+	// DiffPercentage fails the guardrail if the monthly cost increases by more
+	// than this percentage of the past monthly cost.
+	DiffPercentage *float64 `yaml:"diff_percentage,omitempty"`
+	// Action is either warn or block, defaults to warn.
+	Action string `yaml:"action,omitempty"`
+}
+
+// Blocks returns true if the failures of the guardrail should fail the run.
+func (g *Guardrail) Blocks() bool {
+	return g.Action == GuardrailActionBlock
+}
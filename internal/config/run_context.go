@@ -34,6 +34,9 @@ type RunContext struct {
 	StartTime   int64
 
 	isCommentCmd bool
+	// projectOutputs holds the JSON encoded root module outputs of the projects
+	// that have been evaluated in the run, keyed by project name.
+	projectOutputs map[string][]byte
 
 	OutWriter io.Writer
 	ErrWriter io.Writer
@@ -151,6 +154,29 @@ func (r *RunContext) ContextValues() map[string]interface{} {
 	return r.contextVals
 }
 
+// SetProjectOutputs stores the JSON encoded outputs of the named project, so that
+// projects evaluated later in the run can use them, e.g. for terraform_remote_state
+// data sources.
+func (r *RunContext) SetProjectOutputs(name string, outputs []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.projectOutputs == nil {
+		r.projectOutputs = make(map[string][]byte)
+	}
+	r.projectOutputs[name] = outputs
+}
+
+// ProjectOutputs returns the JSON encoded outputs of the named project. It returns
+// false if the project hasn't been evaluated yet or has no outputs.
+func (r *RunContext) ProjectOutputs(name string) ([]byte, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	outputs, ok := r.projectOutputs[name]
+	return outputs, ok
+}
+
 func (r *RunContext) GetResourceWarnings() map[string]map[string]int {
 	if warnings := r.contextVals["resourceWarnings"]; warnings != nil {
 		return warnings.(map[string]map[string]int)
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"path"
+	"regexp"
+)
+
+// TagPolicy lists the tags that billable resources must have. It is set with
+// the tag_policy section of the config file.
+type TagPolicy struct {
+	RequiredTags []*RequiredTag `yaml:"required_tags"`
+}
+
+// RequiredTag is a tag key that resources must have.
+type RequiredTag struct {
+	// Key of the tag.
+	Key string `yaml:"key"`
+	// AllowedValues is a regex that the value of the tag must match. Any value is
+	// allowed if it's empty.
+	AllowedValues string `yaml:"allowed_values,omitempty"`
+	// ResourceTypes limits the resource types that the tag is required for, e.g.
+	// aws_instance or aws_*. The tag is required for all resource types if it's empty.
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+
+	allowedValues *regexp.Regexp
+}
+
+// AppliesTo returns true if the tag is required for the resource type.
+func (r *RequiredTag) AppliesTo(resourceType string) bool {
+	if len(r.ResourceTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range r.ResourceTypes {
+		if ok, _ := path.Match(pattern, resourceType); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows returns true if the value matches the allowed values of the tag.
+func (r *RequiredTag) Allows(value string) bool {
+	if r.AllowedValues == "" {
+		return true
+	}
+
+	if r.allowedValues == nil {
+		r.allowedValues = regexp.MustCompile(r.AllowedValues)
+	}
+
+	return r.allowedValues.MatchString(value)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/gocty"
+
+	"github.com/infracost/infracost/internal/hcl/funcs"
 )
 
 var (
@@ -182,6 +184,15 @@ func (attr *Attribute) value(retry int) (ctyVal cty.Value) {
 				}
 			}
 
+			// provider defined functions, e.g. provider::aws::arn_parse, are implemented by the provider plugins
+			// so we can't evaluate them. We register a function that returns an unknown value in their place so
+			// that the rest of the expression can still be evaluated.
+			if extra, ok := hcl.DiagnosticExtra[hclsyntax.FunctionCallUnknownDiagExtra](d); ok && strings.HasPrefix(extra.CalledFunctionNamespace(), "provider::") {
+				if setProviderFuncOnCtx(ctx, extra.CalledFunctionNamespace()+extra.CalledFunctionName()) {
+					shouldRetry = true
+				}
+			}
+
 			// now that we've built a mocked attribute on the global context let's try and retrieve the value once again.
 			if shouldRetry {
 				return attr.value(retry + 1)
@@ -193,9 +204,35 @@ func (attr *Attribute) value(retry int) (ctyVal cty.Value) {
 		}
 	}
 
+	// values can be marked by functions such as sensitive. Marks aren't
+	// needed for estimating costs and marked values can't be converted to
+	// JSON, so we strip them here before the value is used anywhere else.
+	ctyVal, _ = ctyVal.UnmarkDeep()
+
 	return ctyVal
 }
 
+// setProviderFuncOnCtx adds a function that returns an unknown value for the
+// provider defined function name to the first evaluation context that has a
+// function table. It returns false if there is no function table to add to or
+// the function already exists.
+func setProviderFuncOnCtx(ctx *hcl.EvalContext, name string) bool {
+	for c := ctx; c != nil; c = c.Parent() {
+		if c.Functions == nil {
+			continue
+		}
+
+		if _, ok := c.Functions[name]; ok {
+			return false
+		}
+
+		c.Functions[name] = funcs.UnknownProviderFunc
+		return true
+	}
+
+	return false
+}
+
 // traverseVarAndSetCtx uses the hcl traversal to build a mocked attribute on the evaluation context.
 // hcl Traversals from missing are normally provided in the following manner:
 //
@@ -395,6 +432,35 @@ func (attr *Attribute) createDotReferenceFromTraversal(traversals ...hcl.Travers
 	return newReference(refParts)
 }
 
+// AddressString returns the resource or module address that the Attribute
+// expression refers to, e.g. module.app.aws_instance.web[0]. This is used for
+// the from and to attributes of moved, import and removed blocks, which are
+// addresses rather than values.
+func (attr *Attribute) AddressString() string {
+	if attr == nil || attr.HCLAttr == nil {
+		return ""
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(attr.HCLAttr.Expr)
+	if diags.HasErrors() {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, p := range traversal {
+		switch part := p.(type) {
+		case hcl.TraverseRoot:
+			sb.WriteString(part.Name)
+		case hcl.TraverseAttr:
+			sb.WriteString("." + part.Name)
+		case hcl.TraverseIndex:
+			sb.WriteString("[" + attr.getIndexValue(part) + "]")
+		}
+	}
+
+	return sb.String()
+}
+
 func (attr *Attribute) getIndexValue(part hcl.TraverseIndex) string {
 	switch part.Key.Type() {
 	case cty.String:
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -186,3 +187,58 @@ locals {
 
 	assert.NotContains(t, string(b), "invalid memory address")
 }
+
+func TestAttributeValueWithFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want cty.Value
+	}{
+		{
+			name: "unknown provider function returns unknown value",
+			expr: `provider::aws::arn_parse("arn:aws:iam::444455556666:role/example")`,
+			want: cty.DynamicVal,
+		},
+		{
+			name: "unknown provider functions in a larger expression",
+			expr: `"${provider::aws::arn_parse("a")}-${provider::time::rfc3339_parse("b")}"`,
+			want: cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+		{
+			name: "provider function result not needed",
+			expr: `true ? "known" : provider::aws::arn_parse("a")`,
+			want: cty.StringVal("known"),
+		},
+		{
+			name: "sensitive marks are removed",
+			expr: `sensitive({ a = "b" })`,
+			want: cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tt.expr), "test.tf", hcl.InitialPos)
+			require.False(t, diags.HasErrors(), diags.Error())
+
+			attr := &Attribute{
+				Ctx: &Context{
+					ctx: &hcl.EvalContext{
+						Variables: map[string]cty.Value{},
+						Functions: expFunctions(t.TempDir(), newDiscardLogger()),
+					},
+					logger: newDiscardLogger(),
+				},
+				HCLAttr: &hcl.Attribute{
+					Name: "test",
+					Expr: expr,
+				},
+				Logger: newDiscardLogger(),
+			}
+
+			got := attr.Value()
+			assert.False(t, got.ContainsMarked())
+			assert.Truef(t, tt.want.RawEquals(got), "got %#v, want %#v", got, tt.want)
+		})
+	}
+}
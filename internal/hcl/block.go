@@ -49,6 +49,19 @@ var (
 				Type:       "data",
 				LabelNames: []string{"type", "name"},
 			},
+			{
+				Type: "moved",
+			},
+			{
+				Type: "import",
+			},
+			{
+				Type: "removed",
+			},
+			{
+				Type:       "check",
+				LabelNames: []string{"name"},
+			},
 		},
 	}
 	justProviderBlocks = &hcl.BodySchema{
@@ -803,6 +816,13 @@ func (b *Block) Values() cty.Value {
 		values[attribute.Name()] = attribute.Value()
 	}
 
+	// The output of the built-in terraform_data resource is always its input
+	if b.Type() == "resource" && b.TypeLabel() == "terraform_data" {
+		if input, ok := values["input"]; ok {
+			values["output"] = input
+		}
+	}
+
 	return cty.ObjectVal(values)
 }
 
@@ -867,6 +887,17 @@ func (b *Block) Labels() []string {
 	return b.hclBlock.Labels
 }
 
+// Range returns the source range of the whole block, from the block header to
+// its closing brace. Blocks that aren't from native HCL syntax only have the
+// range of the header.
+func (b *Block) Range() hcl.Range {
+	if body, ok := b.hclBlock.Body.(*hclsyntax.Body); ok {
+		return hcl.RangeBetween(b.hclBlock.DefRange, body.SrcRange)
+	}
+
+	return b.hclBlock.DefRange
+}
+
 func (b *Block) Context() *Context {
 	return b.context
 }
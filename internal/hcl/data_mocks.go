@@ -0,0 +1,270 @@
+package hcl
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	yaml "github.com/zclconf/go-cty-yaml"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DataMocks holds values for data source attributes that can't be known without
+// calling the provider, e.g. the os of an image found by a data "ibm_is_image"
+// block. DataMocks is keyed by either a data source type, which applies to all data
+// sources of that type, or a data source address. Addresses can be relative to the
+// module the data source is in, e.g. data.aws_ami.ubuntu, or absolute, e.g.
+// module.web.data.aws_ami.ubuntu. An address without an index applies to all
+// instances of the data source.
+//
+// A data mocks file is a YAML or JSON file of these keys to attribute values:
+//
+//	ibm_is_image:
+//	  os: ubuntu-22-04-amd64
+//	module.web.data.aws_ami.ubuntu:
+//	  id: ami-0123456789abcdef0
+type DataMocks map[string]cty.Value
+
+// LoadDataMocks reads the DataMocks from the file at path.
+func LoadDataMocks(path string) (DataMocks, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading data mocks file %s: %w", path, err)
+	}
+
+	mocks, err := parseDataMocks(b)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing data mocks file %s: %w", path, err)
+	}
+
+	return mocks, nil
+}
+
+func parseDataMocks(b []byte) (DataMocks, error) {
+	ty, err := yaml.ImpliedType(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if ty == cty.DynamicPseudoType {
+		return DataMocks{}, nil
+	}
+
+	if !ty.IsObjectType() {
+		return nil, fmt.Errorf("expected a map of data sources to attribute values")
+	}
+
+	val, err := yaml.Unmarshal(b, ty)
+	if err != nil {
+		return nil, err
+	}
+
+	mocks := make(DataMocks)
+	for k, v := range val.AsValueMap() {
+		if v.IsNull() {
+			continue
+		}
+
+		if !v.Type().IsObjectType() {
+			return nil, fmt.Errorf("expected a map of attribute values for %s", k)
+		}
+
+		mocks[k] = v
+	}
+
+	return mocks, nil
+}
+
+// dataSourceKeys returns the keys that apply to the data Block, from the least
+// to the most specific: the data source type, its address relative to the module
+// without and with the index and its absolute address without and with the index.
+func dataSourceKeys(b *Block) []string {
+	local := b.LocalName()
+	full := b.FullName()
+
+	keys := []string{b.TypeLabel(), stripCount(local), local}
+	if full != local {
+		keys = append(keys, stripCount(full), full)
+	}
+
+	return keys
+}
+
+// Values returns the mocked attribute values for the data Block, with values of
+// more specific keys overriding less specific ones. It returns false if there
+// are no mocks for the Block.
+func (d DataMocks) Values(b *Block) (map[string]cty.Value, bool) {
+	if len(d) == 0 || b.Type() != "data" {
+		return nil, false
+	}
+
+	var found bool
+	values := make(map[string]cty.Value)
+	for _, k := range dataSourceKeys(b) {
+		v, ok := d[k]
+		if !ok {
+			continue
+		}
+
+		found = true
+		for attr, av := range v.AsValueMap() {
+			values[attr] = av
+		}
+	}
+
+	return values, found
+}
+
+// dataReferences returns the addresses of the data sources that the attribute
+// references directly or through a local value of the module. Addresses are
+// relative to the module and include the index if the reference has one.
+func dataReferences(attr *Attribute, locals map[string]*Attribute, visited map[string]struct{}) []string {
+	if attr == nil || attr.HCLAttr == nil {
+		return nil
+	}
+
+	var addrs []string
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		root := traversal.RootName()
+		switch root {
+		case "data":
+			if addr := dataAddress(traversal); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		case "local":
+			if len(traversal) < 2 {
+				continue
+			}
+
+			name, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+
+			if _, ok := visited[name.Name]; ok {
+				continue
+			}
+			visited[name.Name] = struct{}{}
+
+			addrs = append(addrs, dataReferences(locals[name.Name], locals, visited)...)
+		}
+	}
+
+	return addrs
+}
+
+func dataAddress(traversal hcl.Traversal) string {
+	if len(traversal) < 3 {
+		return ""
+	}
+
+	typ, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return ""
+	}
+
+	name, ok := traversal[2].(hcl.TraverseAttr)
+	if !ok {
+		return ""
+	}
+
+	addr := strings.Join([]string{"data", typ.Name, name.Name}, ".")
+	if len(traversal) < 4 {
+		return addr
+	}
+
+	index, ok := traversal[3].(hcl.TraverseIndex)
+	if !ok || !index.Key.IsKnown() || index.Key.IsNull() {
+		return addr
+	}
+
+	switch index.Key.Type() {
+	case cty.Number:
+		return fmt.Sprintf("%s[%s]", addr, index.Key.AsBigFloat().Text('f', -1))
+	case cty.String:
+		return fmt.Sprintf("%s[%q]", addr, index.Key.AsString())
+	}
+
+	return addr
+}
+
+// unmockedDataSources returns the data sources that don't have DataMocks values
+// or RemoteStates outputs and are referenced by the attributes of the resource,
+// keyed by the resource address. Data sources can be referenced directly or
+// through local values.
+func (m *Module) unmockedDataSources(mocks DataMocks, remoteStates RemoteStates) map[string][]string {
+	result := make(map[string][]string)
+
+	locals := make(map[string]*Attribute)
+	dataBlocks := make(map[string]*Block)
+	for _, b := range m.Blocks {
+		switch b.Type() {
+		case "locals":
+			for _, attr := range b.GetAttributes() {
+				locals[attr.Name()] = attr
+			}
+		case "data":
+			dataBlocks[b.LocalName()] = b
+			if addr := stripCount(b.LocalName()); dataBlocks[addr] == nil {
+				dataBlocks[addr] = b
+			}
+		}
+	}
+
+	for _, b := range m.Blocks {
+		if b.Type() != "resource" {
+			continue
+		}
+
+		seen := make(map[string]struct{})
+		var unmocked []string
+		for _, attr := range allAttributes(b) {
+			for _, addr := range dataReferences(attr, locals, map[string]struct{}{}) {
+				if _, ok := seen[addr]; ok {
+					continue
+				}
+				seen[addr] = struct{}{}
+
+				dataBlock, ok := dataBlocks[addr]
+				if !ok {
+					continue
+				}
+
+				if _, ok := mocks.Values(dataBlock); ok {
+					continue
+				}
+
+				if _, ok := remoteStates.Outputs(dataBlock); ok {
+					continue
+				}
+
+				unmocked = append(unmocked, m.absoluteAddress(addr))
+			}
+		}
+
+		if len(unmocked) > 0 {
+			sort.Strings(unmocked)
+			result[b.FullName()] = unmocked
+		}
+	}
+
+	for _, child := range m.Modules {
+		for k, v := range child.unmockedDataSources(mocks, remoteStates) {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// allAttributes returns the attributes of the Block and all its child Blocks.
+func allAttributes(b *Block) []*Attribute {
+	attrs := b.GetAttributes()
+	for _, child := range b.Children() {
+		attrs = append(attrs, allAttributes(child)...)
+	}
+
+	return attrs
+}
@@ -0,0 +1,523 @@
+package hcl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// DiagnosticKind is the reason a value is still unknown after evaluation.
+type DiagnosticKind string
+
+const (
+	// DiagnosticMissingVar is a variable without a default or an input value.
+	DiagnosticMissingVar DiagnosticKind = "missing_var"
+	// DiagnosticUnresolvedReference is an attribute that references a value that doesn't exist.
+	DiagnosticUnresolvedReference DiagnosticKind = "unresolved_reference"
+	// DiagnosticUnknownValue is an attribute that references values that are still unknown.
+	DiagnosticUnknownValue DiagnosticKind = "unknown_value"
+	// DiagnosticFailedFunctionCall is an attribute with a function call that returned an error.
+	DiagnosticFailedFunctionCall DiagnosticKind = "failed_function_call"
+	// DiagnosticUnexpandedCount is a count that couldn't be evaluated, so a single instance is assumed.
+	DiagnosticUnexpandedCount DiagnosticKind = "unexpanded_count"
+	// DiagnosticUnexpandedForEach is a for_each that couldn't be evaluated, so the block has no instances.
+	DiagnosticUnexpandedForEach DiagnosticKind = "unexpanded_for_each"
+	// DiagnosticModuleLoad is a module call whose source couldn't be loaded, so the module has no blocks.
+	DiagnosticModuleLoad DiagnosticKind = "module_load_failed"
+)
+
+// Diagnostic describes a value that is unresolved or unknown after the
+// Evaluator has run, which can cause resources to be priced incorrectly.
+type Diagnostic struct {
+	Kind DiagnosticKind
+	// Address is the absolute address of the block or attribute with the
+	// problem, e.g. module.web.aws_instance.app.instance_type or var.region.
+	Address string
+	Message string
+	Range   hcl.Range
+	// Resources are the addresses of the resources affected by the problem.
+	Resources []string
+
+	// ref is the reference that resources use to depend on the problem, e.g.
+	// var.region or local.size. It is empty if resources are affected directly.
+	ref string
+	// block is the address of the block that is affected directly, relative to the module.
+	block string
+}
+
+// AllDiagnostics returns the Diagnostics of the Module and its child Modules,
+// sorted by address.
+func (m *Module) AllDiagnostics() []Diagnostic {
+	diags := append([]Diagnostic{}, m.Diagnostics...)
+	for _, child := range m.Modules {
+		diags = append(diags, child.AllDiagnostics()...)
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Address != diags[j].Address {
+			return diags[i].Address < diags[j].Address
+		}
+
+		return diags[i].Kind < diags[j].Kind
+	})
+
+	return diags
+}
+
+// addDiagnostic records the Diagnostic for the Module being evaluated, unless
+// the same problem has already been recorded. Blocks can be expanded more than
+// once, e.g. module blocks, so the same problem can be found again.
+func (e *Evaluator) addDiagnostic(diag Diagnostic) {
+	if !e.collectDiagnostics {
+		return
+	}
+
+	for _, existing := range e.diagnostics {
+		if existing.Kind == diag.Kind && existing.Address == diag.Address {
+			return
+		}
+	}
+
+	e.diagnostics = append(e.diagnostics, diag)
+}
+
+// addExpansionDiagnostic records that the count or for_each attribute of the
+// Block couldn't be evaluated.
+func (e *Evaluator) addExpansionDiagnostic(kind DiagnosticKind, b *Block, attr *Attribute, message string) {
+	e.addDiagnostic(Diagnostic{
+		Kind:    kind,
+		Address: b.FullName() + "." + attr.Name(),
+		Message: message,
+		Range:   attr.HCLAttr.Range,
+		block:   b.LocalName(),
+	})
+}
+
+// addModuleLoadDiagnostic records that the module Block couldn't be loaded.
+func (e *Evaluator) addModuleLoadDiagnostic(b *Block, err error) {
+	diag := Diagnostic{
+		Kind:    DiagnosticModuleLoad,
+		Address: b.FullName(),
+		Message: fmt.Sprintf("module could not be loaded, so its resources are not included: %s", err),
+		Range:   b.hclBlock.DefRange,
+	}
+
+	if attr := b.GetAttribute("source"); attr != nil {
+		diag.Range = attr.HCLAttr.Range
+	}
+
+	e.addDiagnostic(diag)
+}
+
+// moduleDiagnostics returns the Diagnostics of the evaluated Module, along with
+// the ones found while expanding and loading its blocks, with the resources
+// that each one affects. It must be called after the child Modules have been
+// collected, so resources in them can be found.
+func (e *Evaluator) moduleDiagnostics(root *Module) []Diagnostic {
+	if !e.collectDiagnostics {
+		return nil
+	}
+
+	for _, b := range root.Blocks.OfType("variable") {
+		if _, err := e.evaluateVariable(b); err != errorNoVarValue {
+			continue
+		}
+
+		e.addDiagnostic(Diagnostic{
+			Kind:    DiagnosticMissingVar,
+			Address: root.absoluteAddress("var." + b.Label()),
+			Message: "no input value was provided for the variable and it has no default",
+			Range:   b.hclBlock.DefRange,
+			ref:     "var." + b.Label(),
+		})
+	}
+
+	decls := moduleDeclarations(root)
+	for _, b := range root.Blocks {
+		switch b.Type() {
+		case "resource", "data", "module", "locals", "output":
+		default:
+			continue
+		}
+
+		for _, attr := range allAttributes(b) {
+			if diag, ok := attributeDiagnostic(root, b, attr, decls); ok {
+				e.addDiagnostic(diag)
+			}
+		}
+	}
+
+	locals := make(map[string]*Attribute)
+	for _, b := range root.Blocks.OfType("locals") {
+		for _, attr := range b.GetAttributes() {
+			locals[attr.Name()] = attr
+		}
+	}
+
+	diags := make([]Diagnostic, 0, len(e.diagnostics))
+	for _, diag := range e.diagnostics {
+		// Module blocks are expanded before the modules are loaded as well, when
+		// a for_each that references module outputs can still be unknown.
+		if diag.Kind == DiagnosticUnexpandedForEach && hasInstances(root, diag.block) {
+			continue
+		}
+
+		diag.Resources = affectedResources(root, diag, locals)
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+// declarations are the references that can be made to the blocks of a Module,
+// e.g. var.region, local.zones, aws_instance.web, data.aws_ami.ubuntu and
+// module.vpc, along with the outputs of the loaded child modules, e.g.
+// module.vpc.vpc_id.
+type declarations struct {
+	refs          map[string]struct{}
+	loadedModules map[string]struct{}
+}
+
+func moduleDeclarations(m *Module) declarations {
+	decls := declarations{
+		refs:          make(map[string]struct{}),
+		loadedModules: make(map[string]struct{}),
+	}
+
+	// RawBlocks are used so that blocks that couldn't be expanded are still
+	// declared.
+	for _, b := range m.RawBlocks {
+		switch b.Type() {
+		case "variable":
+			decls.refs["var."+b.Label()] = struct{}{}
+		case "locals":
+			for _, attr := range b.GetAttributes() {
+				decls.refs["local."+attr.Name()] = struct{}{}
+			}
+		case "resource", "data", "module":
+			decls.refs[stripCount(b.LocalName())] = struct{}{}
+		}
+	}
+
+	prefix := ""
+	if m.Name != "" {
+		prefix = stripCount(m.Name) + "."
+	}
+
+	for _, child := range m.Modules {
+		name := strings.TrimPrefix(stripCount(child.Name), prefix)
+		decls.loadedModules[name] = struct{}{}
+
+		for _, b := range child.RawBlocks.OfType("output") {
+			decls.refs[name+"."+b.Label()] = struct{}{}
+		}
+	}
+
+	return decls
+}
+
+// undeclared returns the reference of the traversal if it references a block
+// that isn't declared in the Module, or an output that the child module
+// doesn't have. Traversals with other roots, e.g. each or count, and
+// iterators of dynamic blocks and for expressions are always declared.
+func (d declarations) undeclared(traversal hcl.Traversal, ctx *hcl.EvalContext) (string, bool) {
+	root := traversal.RootName()
+	ref := traversalReference(traversal)
+
+	switch root {
+	case "each", "count", "path", "terraform", "self":
+		return "", false
+	case "var", "local", "data":
+		if ref == root {
+			return "", false
+		}
+
+		_, ok := d.refs[ref]
+		return ref, !ok
+	case "module":
+		var names []string
+		for _, t := range traversal[1:] {
+			if attr, ok := t.(hcl.TraverseAttr); ok {
+				names = append(names, attr.Name)
+			}
+
+			if len(names) == 2 {
+				break
+			}
+		}
+
+		if len(names) == 0 {
+			return "", false
+		}
+
+		module := "module." + names[0]
+		if _, ok := d.refs[module]; !ok {
+			return module, true
+		}
+
+		if _, ok := d.loadedModules[module]; !ok || len(names) < 2 {
+			return "", false
+		}
+
+		output := module + "." + names[1]
+		_, ok := d.refs[output]
+		return output, !ok
+	}
+
+	if _, ok := d.refs[ref]; ok {
+		return "", false
+	}
+
+	for declared := range d.refs {
+		if strings.HasPrefix(declared, root+".") {
+			return ref, true
+		}
+	}
+
+	// the root can be the iterator of a dynamic block or for expression.
+	_, diags := hcl.Traversal{traversal[0]}.TraverseAbs(ctx)
+	return ref, diags.HasErrors()
+}
+
+// attributeDiagnostic returns a Diagnostic if the Attribute of the Block
+// references blocks that aren't declared, has a function call that fails or
+// evaluates to an unknown value. Values are mocked when an attribute can't be
+// evaluated, e.g. the id of a resource, so references are checked against the
+// declared blocks rather than the evaluation context.
+func attributeDiagnostic(m *Module, b *Block, attr *Attribute, decls declarations) (diag Diagnostic, found bool) {
+	if attr.HCLAttr == nil || attr.Ctx == nil {
+		return diag, false
+	}
+
+	// count and for_each are reported when the block is expanded.
+	if attr.Name() == "count" || attr.Name() == "for_each" {
+		return diag, false
+	}
+
+	diag = Diagnostic{
+		Address: m.absoluteAddress(b.LocalName() + "." + attr.Name()),
+		Range:   attr.HCLAttr.Range,
+		block:   b.LocalName(),
+	}
+
+	switch b.Type() {
+	case "locals":
+		diag.Address = m.absoluteAddress("local." + attr.Name())
+		diag.ref = "local." + attr.Name()
+		diag.block = ""
+	case "data":
+		diag.ref = stripCount(b.LocalName())
+		diag.block = ""
+	case "output":
+		diag.block = ""
+	}
+
+	defer func() {
+		// evaluating some expressions can panic in the underlying hcl/go-cty
+		// libraries, see Attribute.Value.
+		if err := recover(); err != nil {
+			found = false
+		}
+	}()
+
+	ctx := attr.Ctx.Inner()
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		if ref, ok := decls.undeclared(traversal, ctx); ok {
+			diag.Kind = DiagnosticUnresolvedReference
+			diag.Message = fmt.Sprintf("reference to %s, which is not declared", ref)
+			diag.Range = traversal.SourceRange()
+			return diag, true
+		}
+	}
+
+	val, hclDiags := attr.HCLAttr.Expr.Value(ctx)
+	for _, d := range hclDiags {
+		if d.Severity != hcl.DiagError || !strings.Contains(d.Summary, "function") {
+			continue
+		}
+
+		diag.Kind = DiagnosticFailedFunctionCall
+		diag.Message = strings.TrimSpace(fmt.Sprintf("%s: %s", d.Summary, d.Detail))
+		if d.Subject != nil {
+			diag.Range = *d.Subject
+		}
+
+		return diag, true
+	}
+
+	if hclDiags.HasErrors() || val.IsWhollyKnown() {
+		return diag, false
+	}
+
+	var unknown []string
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		v, travDiags := traversal.TraverseAbs(ctx)
+		if travDiags.HasErrors() || v.IsWhollyKnown() {
+			continue
+		}
+
+		unknown = append(unknown, traversalReference(traversal))
+	}
+
+	diag.Kind = DiagnosticUnknownValue
+	diag.Message = "value is still unknown after evaluation"
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		diag.Message = fmt.Sprintf("value depends on unknown values: %s", strings.Join(unique(unknown), ", "))
+	}
+
+	return diag, true
+}
+
+// traversalReference returns the reference that the traversal starts with,
+// e.g. var.region for var.region.name, or data.aws_ami.ubuntu for
+// data.aws_ami.ubuntu.id.
+func traversalReference(traversal hcl.Traversal) string {
+	parts := []string{traversal.RootName()}
+
+	n := 1
+	if parts[0] == "data" {
+		n = 2
+	}
+
+	for _, t := range traversal[1:] {
+		if len(parts) > n {
+			break
+		}
+
+		attr, ok := t.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+
+		parts = append(parts, attr.Name)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// affectedResources returns the addresses of the resources in the Module, or in
+// its child Modules, that are affected by the Diagnostic. These are the
+// resources of the block with the problem and the resources that reference it,
+// either directly or through local values.
+func affectedResources(m *Module, diag Diagnostic, locals map[string]*Attribute) []string {
+	var addrs []string
+
+	if diag.Kind == DiagnosticModuleLoad {
+		return nil
+	}
+
+	// A problem with a module block affects all the resources in the module.
+	if strings.HasPrefix(diag.block, "module.") {
+		prefix := m.absoluteAddress(diag.block)
+		for _, addr := range m.resourceAddresses() {
+			if strings.HasPrefix(addr, prefix+".") || strings.HasPrefix(addr, prefix+"[") {
+				addrs = append(addrs, addr)
+			}
+		}
+
+		return unique(addrs)
+	}
+
+	for _, b := range m.Blocks.OfType("resource") {
+		if diag.block != "" && stripCount(b.LocalName()) == stripCount(diag.block) {
+			addrs = append(addrs, b.FullName())
+			continue
+		}
+
+		if diag.ref == "" {
+			continue
+		}
+
+		if referencesAny(b, diag.ref, locals) {
+			addrs = append(addrs, b.FullName())
+		}
+	}
+
+	sort.Strings(addrs)
+	return unique(addrs)
+}
+
+// referencesAny returns if any attribute of the Block references ref, either
+// directly or through local values.
+func referencesAny(b *Block, ref string, locals map[string]*Attribute) bool {
+	for _, attr := range allAttributes(b) {
+		if references(attr, ref, locals, map[string]struct{}{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func references(attr *Attribute, ref string, locals map[string]*Attribute, visited map[string]struct{}) bool {
+	if attr == nil || attr.HCLAttr == nil {
+		return false
+	}
+
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		r := traversalReference(traversal)
+		if r == ref {
+			return true
+		}
+
+		if traversal.RootName() != "local" {
+			continue
+		}
+
+		name := strings.TrimPrefix(r, "local.")
+		if _, ok := visited[name]; ok {
+			continue
+		}
+		visited[name] = struct{}{}
+
+		if references(locals[name], ref, locals, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasInstances returns if the Module has expanded instances of the block.
+func hasInstances(m *Module, block string) bool {
+	for _, b := range m.Blocks {
+		if b.Key() != nil && stripCount(b.LocalName()) == stripCount(block) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resourceAddresses returns the addresses of the resources in the Module and
+// its child Modules.
+func (m *Module) resourceAddresses() []string {
+	var addrs []string
+	for _, b := range m.Blocks.OfType("resource") {
+		addrs = append(addrs, b.FullName())
+	}
+
+	for _, child := range m.Modules {
+		addrs = append(addrs, child.resourceAddresses()...)
+	}
+
+	return addrs
+}
+
+func unique(elems []string) []string {
+	seen := make(map[string]struct{}, len(elems))
+	result := elems[:0]
+	for _, elem := range elems {
+		if _, ok := seen[elem]; ok {
+			continue
+		}
+
+		seen[elem] = struct{}{}
+		result = append(result, elem)
+	}
+
+	return result
+}
@@ -71,8 +71,18 @@ type Evaluator struct {
 	workspace string
 	// blockBuilder handles generating blocks in the evaluation step.
 	blockBuilder BlockBuilder
-	newSpinner   ui.SpinnerFunc
-	logger       *logrus.Entry
+	// dataMocks are user provided attribute values for data blocks. These are
+	// added to the data block values in the evaluation context.
+	dataMocks DataMocks
+	// remoteStates are the outputs to use for terraform_remote_state data
+	// blocks. These are added to the data block values in the evaluation context.
+	remoteStates RemoteStates
+	// collectDiagnostics sets if the Evaluator records Diagnostics for values
+	// that are still unresolved or unknown after evaluation.
+	collectDiagnostics bool
+	diagnostics        []Diagnostic
+	newSpinner         ui.SpinnerFunc
+	logger             *logrus.Entry
 }
 
 // NewEvaluator returns an Evaluator with Context initialised with top level variables.
@@ -86,6 +96,9 @@ func NewEvaluator(
 	visitedModules map[string]map[string]cty.Value,
 	workspace string,
 	blockBuilder BlockBuilder,
+	dataMocks DataMocks,
+	remoteStates RemoteStates,
+	collectDiagnostics bool,
 	spinFunc ui.SpinnerFunc,
 	logger *logrus.Entry,
 ) *Evaluator {
@@ -129,16 +142,19 @@ func NewEvaluator(
 	})
 
 	return &Evaluator{
-		module:         module,
-		ctx:            ctx,
-		inputVars:      inputVars,
-		moduleMetadata: moduleMetadata,
-		visitedModules: visitedModules,
-		workspace:      workspace,
-		workingDir:     workingDir,
-		blockBuilder:   blockBuilder,
-		newSpinner:     spinFunc,
-		logger:         l,
+		module:             module,
+		ctx:                ctx,
+		inputVars:          inputVars,
+		moduleMetadata:     moduleMetadata,
+		visitedModules:     visitedModules,
+		workspace:          workspace,
+		workingDir:         workingDir,
+		blockBuilder:       blockBuilder,
+		dataMocks:          dataMocks,
+		remoteStates:       remoteStates,
+		collectDiagnostics: collectDiagnostics,
+		newSpinner:         spinFunc,
+		logger:             l,
 	}
 }
 
@@ -199,6 +215,8 @@ func (e *Evaluator) collectModules() *Module {
 		}
 	}
 
+	root.Diagnostics = e.moduleDiagnostics(&root)
+
 	return &root
 }
 
@@ -286,6 +304,9 @@ func (e *Evaluator) evaluateModules() {
 			map[string]map[string]cty.Value{},
 			e.workspace,
 			e.blockBuilder,
+			e.dataMocks,
+			e.remoteStates,
+			e.collectDiagnostics,
 			nil,
 			e.logger,
 		)
@@ -407,7 +428,9 @@ func (e *Evaluator) expandBlockForEaches(blocks Blocks) Blocks {
 		e.logger.Debugf("expanding block %s because a for_each attribute was found", block.LocalName())
 
 		value := forEachAttr.Value()
-		if !value.IsNull() && value.IsKnown() && forEachAttr.IsIterable() {
+		if value.IsNull() || !value.IsKnown() || !forEachAttr.IsIterable() {
+			e.addExpansionDiagnostic(DiagnosticUnexpandedForEach, block, forEachAttr, "for_each could not be evaluated to a known map or set, so the block has no instances")
+		} else {
 			srcValue := e.getSourceValue(block)
 
 			typeLabel := block.TypeLabel()
@@ -469,6 +492,8 @@ func (e *Evaluator) expandBlockCounts(blocks Blocks) Blocks {
 			if v <= math.MaxInt32 {
 				count = int(v)
 			}
+		} else {
+			e.addExpansionDiagnostic(DiagnosticUnexpandedCount, block, countAttr, "count could not be evaluated to a known number, so a single instance is assumed")
 		}
 
 		e.logger.Debugf("expanding block %s because a count attribute of value %d was found", block.LocalName(), count)
@@ -660,21 +685,51 @@ func (e *Evaluator) evaluateResource(b *Block, values map[string]cty.Value) cty.
 
 	if k := b.Key(); k != nil {
 		e.logger.Debugf("expanding block %s to be available for for_each key %s", b.FullName(), *k)
-		valueMap[stripCount(labels[1])] = e.expandedEachBlockToValue(b, valueMap)
+		valueMap[stripCount(labels[1])] = e.expandedEachBlockToValue(b, e.blockValues(b), valueMap)
 		return cty.ObjectVal(valueMap)
 	}
 
 	if k := b.Index(); k != nil {
 		e.logger.Debugf("expanding block %s to be available for index key %d", b.FullName(), *k)
-		valueMap[stripCount(labels[1])] = expandCountBlockToValue(b, valueMap)
+		valueMap[stripCount(labels[1])] = expandCountBlockToValue(b, e.blockValues(b), valueMap)
 		return cty.ObjectVal(valueMap)
 	}
 
-	valueMap[b.Labels()[1]] = b.Values()
+	valueMap[b.Labels()[1]] = e.blockValues(b)
 	return cty.ObjectVal(valueMap)
 }
 
-func expandCountBlockToValue(b *Block, existingValues map[string]cty.Value) cty.Value {
+// blockValues returns the values of the Block for the evaluation context. For
+// data blocks these include any values from the data mocks, which override the
+// values of the block attributes.
+func (e *Evaluator) blockValues(b *Block) cty.Value {
+	outputs, hasOutputs := e.remoteStates.Outputs(b)
+	mocks, hasMocks := e.dataMocks.Values(b)
+	if !hasOutputs && !hasMocks {
+		return b.Values()
+	}
+
+	values := b.Values().AsValueMap()
+	if values == nil {
+		values = make(map[string]cty.Value, len(mocks)+1)
+	}
+
+	if hasOutputs {
+		e.logger.Debugf("adding remote state outputs for %s to the evaluation context", b.FullName())
+		values["outputs"] = outputs
+	}
+
+	if hasMocks {
+		e.logger.Debugf("adding data mocks for %s to the evaluation context", b.FullName())
+		for k, v := range mocks {
+			values[k] = v
+		}
+	}
+
+	return cty.ObjectVal(values)
+}
+
+func expandCountBlockToValue(b *Block, blockValues cty.Value, existingValues map[string]cty.Value) cty.Value {
 	k := b.Index()
 	if k == nil {
 		return cty.NilVal
@@ -693,11 +748,11 @@ func expandCountBlockToValue(b *Block, existingValues map[string]cty.Value) cty.
 		}
 	}
 
-	elements = append(elements, b.Values())
+	elements = append(elements, blockValues)
 	return cty.TupleVal(elements)
 }
 
-func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string]cty.Value) cty.Value {
+func (e *Evaluator) expandedEachBlockToValue(b *Block, blockValues cty.Value, existingValues map[string]cty.Value) cty.Value {
 	k := b.Key()
 	if k == nil {
 		return cty.NilVal
@@ -713,7 +768,7 @@ func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string
 				"block": b.Label(),
 			}).Debugf("skipping unexpected cty value type '%s' for existing for_each context value", eachMap.GoString())
 
-			ob[*k] = b.Values()
+			ob[*k] = blockValues
 			return cty.ObjectVal(ob)
 		}
 
@@ -722,7 +777,7 @@ func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string
 		}
 	}
 
-	ob[*k] = b.Values()
+	ob[*k] = blockValues
 	return cty.ObjectVal(ob)
 }
 
@@ -808,6 +863,7 @@ func (e *Evaluator) loadModules(lastContext hcl.EvalContext) []*ModuleCall {
 		moduleCall, err := e.loadModule(moduleBlock)
 		if err != nil {
 			e.logger.WithError(err).Warnf("failed to load module %s ignoring", moduleBlock.LocalName())
+			e.addModuleLoadDiagnostic(moduleBlock, err)
 			continue
 		}
 
@@ -820,9 +876,12 @@ func (e *Evaluator) loadModules(lastContext hcl.EvalContext) []*ModuleCall {
 // expFunctions returns the set of functions that should be used to when evaluating
 // expressions in the receiving scope.
 func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Function {
-	return map[string]function.Function{
+	fns := map[string]function.Function{
 		"abs":              stdlib.AbsoluteFunc,
 		"abspath":          funcs.AbsPathFunc,
+		"alltrue":          funcs.AllTrueFunc,
+		"anytrue":          funcs.AnyTrueFunc,
+		"base64gunzip":     funcs.Base64GunzipFunc,
 		"basename":         funcs.BasenameFunc,
 		"base64decode":     funcs.Base64DecodeFunc,
 		"base64encode":     funcs.Base64EncodeFunc,
@@ -833,6 +892,7 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"can":              tryfunc.CanFunc,
 		"ceil":             stdlib.CeilFunc,
 		"chomp":            stdlib.ChompFunc,
+		"cidrcontains":     funcs.CidrContainsFunc,
 		"cidrhost":         funcs.CidrHostFunc,
 		"cidrnetmask":      funcs.CidrNetmaskFunc,
 		"cidrsubnet":       funcs.CidrSubnetFunc,
@@ -847,6 +907,8 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"distinct":         stdlib.DistinctFunc,
 		"element":          stdlib.ElementFunc,
 		"chunklist":        stdlib.ChunklistFunc,
+		"endswith":         funcs.EndsWithFunc,
+		"ephemeralasnull":  funcs.EphemeralAsNullFunc,
 		"file":             funcs.MakeFileFunc(baseDir, false),
 		"fileexists":       funcs.MakeFileExistsFunc(baseDir),
 		"fileset":          funcs.MakeFileSetFunc(baseDir),
@@ -864,6 +926,7 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"formatlist":       stdlib.FormatListFunc,
 		"indent":           stdlib.IndentFunc,
 		"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
+		"issensitive":      funcs.IsSensitiveFunc,
 		"join":             stdlib.JoinFunc,
 		"jsondecode":       stdlib.JSONDecodeFunc,
 		"jsonencode":       stdlib.JSONEncodeFunc,
@@ -879,10 +942,13 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"md5":              funcs.Md5Func,
 		"merge":            stdlib.MergeFunc,
 		"min":              stdlib.MinFunc,
+		"nonsensitive":     funcs.NonsensitiveFunc,
+		"one":              funcs.OneFunc,
 		"parseint":         stdlib.ParseIntFunc,
 		"pathexpand":       funcs.PathExpandFunc,
 		"infracostlog":     funcs.LogArgs(logger),
 		"infracostprint":   funcs.PrintArgs,
+		"plantimestamp":    funcs.PlanTimestampFunc,
 		"pow":              stdlib.PowFunc,
 		"range":            stdlib.RangeFunc,
 		"regex":            stdlib.RegexFunc,
@@ -890,6 +956,7 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"replace":          funcs.ReplaceFunc,
 		"reverse":          stdlib.ReverseListFunc,
 		"rsadecrypt":       funcs.RsaDecryptFunc,
+		"sensitive":        funcs.SensitiveFunc,
 		"setintersection":  stdlib.SetIntersectionFunc,
 		"setproduct":       stdlib.SetProductFunc,
 		"setsubtract":      stdlib.SetSubtractFunc,
@@ -901,8 +968,14 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"slice":            stdlib.SliceFunc,
 		"sort":             stdlib.SortFunc,
 		"split":            stdlib.SplitFunc,
+		"startswith":       funcs.StartsWithFunc,
+		"strcontains":      funcs.StrContainsFunc,
 		"strrev":           stdlib.ReverseFunc,
 		"substr":           stdlib.SubstrFunc,
+		"sum":              funcs.SumFunc,
+		"textdecodebase64": funcs.TextDecodeBase64Func,
+		"textencodebase64": funcs.TextEncodeBase64Func,
+		"timecmp":          funcs.TimeCmpFunc,
 		"timestamp":        funcs.TimestampFunc,
 		"timeadd":          stdlib.TimeAddFunc,
 		"title":            stdlib.TitleFunc,
@@ -919,6 +992,7 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"trimsuffix":       stdlib.TrimSuffixFunc,
 		"try":              tryfunc.TryFunc,
 		"upper":            stdlib.UpperFunc,
+		"urldecode":        funcs.URLDecodeFunc,
 		"urlencode":        funcs.URLEncodeFunc,
 		"uuid":             funcs.UUIDFunc,
 		"uuidv5":           funcs.UUIDV5Func,
@@ -926,6 +1000,30 @@ func expFunctions(baseDir string, logger *logrus.Entry) map[string]function.Func
 		"yamldecode":       yaml.YAMLDecodeFunc,
 		"yamlencode":       yaml.YAMLEncodeFunc,
 		"zipmap":           stdlib.ZipmapFunc,
+
+		// functions built in to the terraform provider, these are always
+		// available without a required_providers entry.
+		"provider::terraform::encode_tfvars": funcs.EncodeTfvarsFunc,
+		"provider::terraform::decode_tfvars": funcs.DecodeTfvarsFunc,
+		"provider::terraform::encode_expr":   funcs.EncodeExprFunc,
+	}
+
+	// the template functions can call any other function, so they need to
+	// reference the full function table.
+	fns["templatefile"] = funcs.MakeTemplateFileFunc(baseDir, func() map[string]function.Function {
+		return fns
+	})
+	fns["templatestring"] = funcs.MakeTemplateStringFunc(func() map[string]function.Function {
+		return fns
+	})
+
+	// Terraform allows every built-in function to also be called with the
+	// core:: namespace, e.g. core::max(1, 2).
+	for name, fn := range fns {
+		if !strings.Contains(name, "::") {
+			fns["core::"+name] = fn
+		}
 	}
 
+	return fns
 }
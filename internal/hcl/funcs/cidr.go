@@ -186,6 +186,52 @@ var CidrSubnetsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrContainsFunc constructs a function that checks whether a given IP
+// address or address prefix is contained within another address prefix.
+var CidrContainsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "containing_prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "contained_ip_or_prefix",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, containing, err := net.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+
+		contained := args[1].AsString()
+		var startIP, endIP net.IP
+		if ip := net.ParseIP(contained); ip != nil {
+			startIP, endIP = ip, ip
+		} else {
+			_, network, err := net.ParseCIDR(contained)
+			if err != nil {
+				return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid IP address or CIDR expression: %s", contained)
+			}
+			startIP, endIP = cidr.AddressRange(network)
+		}
+
+		if (containing.IP.To4() == nil) != (startIP.To4() == nil) {
+			return cty.UnknownVal(cty.Bool), fmt.Errorf("address family mismatch: %s vs. %s", args[0].AsString(), contained)
+		}
+
+		return cty.BoolVal(containing.Contains(startIP) && containing.Contains(endIP)), nil
+	},
+})
+
+// CidrContains checks whether a given IP address or address prefix is
+// contained within another address prefix.
+func CidrContains(containingPrefix, containedIPOrPrefix cty.Value) (cty.Value, error) {
+	return CidrContainsFunc.Call([]cty.Value{containingPrefix, containedIPOrPrefix})
+}
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
@@ -338,3 +338,80 @@ func TestCidrSubnets(t *testing.T) {
 		})
 	}
 }
+
+func TestCidrContains(t *testing.T) {
+	tests := []struct {
+		Prefix    cty.Value
+		Contained cty.Value
+		Want      cty.Value
+		Err       bool
+	}{
+		{
+			cty.StringVal("192.168.2.0/20"),
+			cty.StringVal("192.168.2.1"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.2.0/20"),
+			cty.StringVal("192.126.2.1"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("fd00:fd12:3456:7890::/56"),
+			cty.StringVal("fd00:fd12:3456:7890:00a2::"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.2.0/20"),
+			cty.StringVal("192.168.2.0/22"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.2.0/22"),
+			cty.StringVal("192.168.2.0/20"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("192.168.2.0/20"),
+			cty.StringVal("fd00:fd12:3456:7890:00a2::"),
+			cty.UnknownVal(cty.Bool),
+			true,
+		},
+		{
+			cty.StringVal("not-a-cidr"),
+			cty.StringVal("192.168.2.1"),
+			cty.UnknownVal(cty.Bool),
+			true,
+		},
+		{
+			cty.StringVal("192.168.2.0/20"),
+			cty.StringVal("not-an-ip"),
+			cty.UnknownVal(cty.Bool),
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrcontains(%#v, %#v)", test.Prefix, test.Contained), func(t *testing.T) {
+			got, err := CidrContains(test.Prefix, test.Contained)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/url"
 	"unicode/utf8"
 
@@ -183,6 +184,57 @@ var URLEncodeFunc = function.New(&function.Spec{
 	},
 })
 
+// Base64GunzipFunc constructs a function that decodes a Base64 string and
+// then decompresses the result with gzip.
+var Base64GunzipFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		s := args[0].AsString()
+		sDec, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to decode base64 data: %w", err)
+		}
+
+		gz, err := gzip.NewReader(bytes.NewReader(sDec))
+		if err != nil {
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to gunzip bytestream: %w", err)
+		}
+		defer gz.Close()
+
+		gunzip, err := io.ReadAll(gz)
+		if err != nil {
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to read gunzip raw data: %w", err)
+		}
+
+		return cty.StringVal(string(gunzip)), nil
+	},
+})
+
+// URLDecodeFunc constructs a function that decodes a URL encoded string.
+var URLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		query, err := url.QueryUnescape(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to decode URL '%s': %w", args[0].AsString(), err)
+		}
+
+		return cty.StringVal(query), nil
+	},
+})
+
 // Base64Decode decodes a string containing a base64 sequence.
 //
 // Terraform uses the "standard" Base64 alphabet as defined in RFC 4648 section 4.
@@ -230,6 +282,16 @@ func URLEncode(str cty.Value) (cty.Value, error) {
 	return URLEncodeFunc.Call([]cty.Value{str})
 }
 
+// Base64Gunzip decodes a Base64 string and decompresses the result with gzip.
+func Base64Gunzip(str cty.Value) (cty.Value, error) {
+	return Base64GunzipFunc.Call([]cty.Value{str})
+}
+
+// URLDecode decodes a URL encoded string.
+func URLDecode(str cty.Value) (cty.Value, error) {
+	return URLDecodeFunc.Call([]cty.Value{str})
+}
+
 // TextEncodeBase64 applies Base64 encoding to a string that was encoded before with a target encoding.
 //
 // Terraform uses the "standard" Base64 alphabet as defined in RFC 4648 section 4.
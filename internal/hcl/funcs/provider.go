@@ -0,0 +1,144 @@
+package funcs
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// EncodeTfvarsFunc constructs a function that encodes an object as a string
+// in the syntax of a .tfvars file. This is the provider::terraform::encode_tfvars
+// function built in to the terraform provider.
+var EncodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		ty := v.Type()
+		if v.IsNull() {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "cannot encode a null value in tfvars syntax")
+		}
+		if !v.IsWhollyKnown() {
+			return cty.UnknownVal(cty.String), nil
+		}
+		if !ty.IsObjectType() && !ty.IsMapType() {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid value type for tfvars encoding: must be an object or a map")
+		}
+
+		f := hclwrite.NewEmptyFile()
+		body := f.Body()
+		for it := v.ElementIterator(); it.Next(); {
+			k, av := it.Element()
+			name := k.AsString()
+			if !hclsyntax.ValidIdentifier(name) {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid variable name %q: must be a valid identifier", name)
+			}
+			body.SetAttributeValue(name, av)
+		}
+
+		return cty.StringVal(string(f.Bytes())), nil
+	},
+})
+
+// DecodeTfvarsFunc constructs a function that parses a string containing the
+// contents of a .tfvars file and returns an object of the values it defines.
+// This is the provider::terraform::decode_tfvars function.
+var DecodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		src := []byte(args[0].AsString())
+		f, diags := hclsyntax.ParseConfig(src, "<decode_tfvars argument>", hcl.InitialPos)
+		if diags.HasErrors() {
+			return cty.DynamicVal, function.NewArgErrorf(0, "invalid tfvars syntax: %s", diags.Error())
+		}
+
+		attrs, diags := f.Body.JustAttributes()
+		if diags.HasErrors() {
+			return cty.DynamicVal, function.NewArgErrorf(0, "invalid tfvars content: %s", diags.Error())
+		}
+
+		vals := make(map[string]cty.Value, len(attrs))
+		for name, attr := range attrs {
+			v, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return cty.DynamicVal, function.NewArgErrorf(0, "invalid expression for variable %q: %s", name, diags.Error())
+			}
+			vals[name] = v
+		}
+
+		return cty.ObjectVal(vals), nil
+	},
+})
+
+// EncodeExprFunc constructs a function that encodes a value as a string
+// containing the equivalent Terraform language expression. This is the
+// provider::terraform::encode_expr function.
+var EncodeExprFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		if !v.IsWhollyKnown() {
+			return cty.UnknownVal(cty.String), nil
+		}
+
+		return cty.StringVal(string(hclwrite.TokensForValue(v).Bytes())), nil
+	},
+})
+
+// UnknownProviderFunc is a stand in for provider defined functions, e.g.
+// provider::aws::arn_parse. Provider functions are implemented by the
+// provider plugins themselves, so their result can't be known when parsing
+// HCL and this returns an unknown value of any type.
+var UnknownProviderFunc = function.New(&function.Spec{
+	Params: []function.Parameter{},
+	VarParam: &function.Parameter{
+		Name:             "args",
+		Type:             cty.DynamicPseudoType,
+		AllowUnknown:     true,
+		AllowNull:        true,
+		AllowMarked:      true,
+		AllowDynamicType: true,
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.DynamicVal, nil
+	},
+})
+
+// EncodeTfvars encodes an object as a string in .tfvars syntax.
+func EncodeTfvars(v cty.Value) (cty.Value, error) {
+	return EncodeTfvarsFunc.Call([]cty.Value{v})
+}
+
+// DecodeTfvars parses a string in .tfvars syntax into an object.
+func DecodeTfvars(src cty.Value) (cty.Value, error) {
+	return DecodeTfvarsFunc.Call([]cty.Value{src})
+}
+
+// EncodeExpr encodes a value as a Terraform language expression.
+func EncodeExpr(v cty.Value) (cty.Value, error) {
+	return EncodeExprFunc.Call([]cty.Value{v})
+}
@@ -0,0 +1,159 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEncodeTfvars(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+		Err   bool
+	}{
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"string": cty.StringVal("hello"),
+				"number": cty.NumberIntVal(5),
+				"list":   cty.TupleVal([]cty.Value{cty.True, cty.False}),
+			}),
+			cty.StringVal("list   = [true, false]\nnumber = 5\nstring = \"hello\"\n"),
+			false,
+		},
+		{
+			cty.EmptyObjectVal,
+			cty.StringVal(""),
+			false,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"not valid": cty.StringVal("hello"),
+			}),
+			cty.UnknownVal(cty.String),
+			true,
+		},
+		{
+			cty.StringVal("hello"),
+			cty.UnknownVal(cty.String),
+			true,
+		},
+		{
+			cty.NullVal(cty.EmptyObject),
+			cty.UnknownVal(cty.String),
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("encode_tfvars(%#v)", test.Input), func(t *testing.T) {
+			got, err := EncodeTfvars(test.Input)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestDecodeTfvars(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+		Err   bool
+	}{
+		{
+			cty.StringVal("string = \"hello\"\nnumber = 5\n"),
+			cty.ObjectVal(map[string]cty.Value{
+				"string": cty.StringVal("hello"),
+				"number": cty.NumberIntVal(5),
+			}),
+			false,
+		},
+		{
+			cty.StringVal(""),
+			cty.EmptyObjectVal,
+			false,
+		},
+		{
+			cty.StringVal("string = var.foo"),
+			cty.DynamicVal,
+			true,
+		},
+		{
+			cty.StringVal("invalid {"),
+			cty.DynamicVal,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("decode_tfvars(%#v)", test.Input), func(t *testing.T) {
+			got, err := DecodeTfvars(test.Input)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestEncodeExpr(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+	}{
+		{
+			cty.StringVal("hello"),
+			cty.StringVal(`"hello"`),
+		},
+		{
+			cty.NumberIntVal(5),
+			cty.StringVal(`5`),
+		},
+		{
+			cty.TupleVal([]cty.Value{cty.True, cty.StringVal("a")}),
+			cty.StringVal(`[true, "a"]`),
+		},
+		{
+			cty.NullVal(cty.String),
+			cty.StringVal(`null`),
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(cty.String),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("encode_expr(%#v)", test.Input), func(t *testing.T) {
+			got, err := EncodeExpr(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -126,16 +126,15 @@ func TestNonsensitive(t *testing.T) {
 			``,
 		},
 
-		// Passing a value that is already non-sensitive is an error,
-		// because this function should always be used with specific
-		// intention, not just as a "make everything visible" hammer.
+		// Passing a value that is already non-sensitive returns it
+		// unchanged, matching Terraform 1.8 and later.
 		{
 			cty.NumberIntVal(1),
-			`the given value is not sensitive, so this call is redundant`,
+			``,
 		},
 		{
 			cty.NullVal(cty.String),
-			`the given value is not sensitive, so this call is redundant`,
+			``,
 		},
 
 		// Unknown values may become sensitive once they are known, so we
@@ -176,3 +175,29 @@ func TestNonsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+	}{
+		{cty.NumberIntVal(1).Mark(MarkedSensitive), cty.True},
+		{cty.UnknownVal(cty.String).Mark(MarkedSensitive), cty.True},
+		{cty.NumberIntVal(1), cty.False},
+		{cty.NullVal(cty.String), cty.False},
+		{cty.ListVal([]cty.Value{cty.NumberIntVal(1).Mark(MarkedSensitive)}), cty.False},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("issensitive(%#v)", test.Input), func(t *testing.T) {
+			got, err := IsSensitive(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
 )
@@ -46,8 +48,131 @@ var ReplaceFunc = function.New(&function.Spec{
 	},
 })
 
+// StartsWithFunc constructs a function that checks if a string starts with
+// a specific prefix.
+var StartsWithFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.HasPrefix(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+// EndsWithFunc constructs a function that checks if a string ends with a
+// specific suffix.
+var EndsWithFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name: "suffix",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.HasSuffix(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+// StrContainsFunc constructs a function that checks if a string contains a
+// substring.
+var StrContainsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name: "substr",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.Contains(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+// MakeTemplateStringFunc constructs a function that renders a string as a
+// template using HCL template syntax, in the same way as templatefile does
+// for files. The functions are provided by a callback for the same reasons
+// as MakeTemplateFileFunc.
+func MakeTemplateStringFunc(funcsCb func() map[string]function.Function) function.Function {
+	params := []function.Parameter{
+		{
+			Name: "template",
+			Type: cty.String,
+		},
+		{
+			Name: "vars",
+			Type: cty.DynamicPseudoType,
+		},
+	}
+
+	loadTmpl := func(tmpl string) (hcl.Expression, error) {
+		expr, diags := hclsyntax.ParseTemplate([]byte(tmpl), "<templatestring argument>", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		return expr, nil
+	}
+
+	return function.New(&function.Spec{
+		Params: params,
+		Type: func(args []cty.Value) (cty.Type, error) {
+			if !(args[0].IsKnown() && args[1].IsKnown()) {
+				return cty.DynamicPseudoType, nil
+			}
+
+			expr, err := loadTmpl(args[0].AsString())
+			if err != nil {
+				return cty.DynamicPseudoType, err
+			}
+
+			val, err := renderTemplate("templatestring", expr, args[1], funcsCb())
+			return val.Type(), err
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			expr, err := loadTmpl(args[0].AsString())
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+
+			return renderTemplate("templatestring", expr, args[1], funcsCb())
+		},
+	})
+}
+
 // Replace searches a given string for another given substring,
 // and replaces all occurrences with a given replacement string.
 func Replace(str, substr, replace cty.Value) (cty.Value, error) {
 	return ReplaceFunc.Call([]cty.Value{str, substr, replace})
 }
+
+// StartsWith checks if a string starts with a given prefix.
+func StartsWith(str, prefix cty.Value) (cty.Value, error) {
+	return StartsWithFunc.Call([]cty.Value{str, prefix})
+}
+
+// EndsWith checks if a string ends with a given suffix.
+func EndsWith(str, suffix cty.Value) (cty.Value, error) {
+	return EndsWithFunc.Call([]cty.Value{str, suffix})
+}
+
+// StrContains checks if a string contains a given substring.
+func StrContains(str, substr cty.Value) (cty.Value, error) {
+	return StrContainsFunc.Call([]cty.Value{str, substr})
+}
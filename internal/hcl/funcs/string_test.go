@@ -71,3 +71,35 @@ func TestReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestStringPredicates(t *testing.T) {
+	tests := []struct {
+		Name string
+		Fn   func(str, arg cty.Value) (cty.Value, error)
+		Str  cty.Value
+		Arg  cty.Value
+		Want cty.Value
+	}{
+		{"startswith", StartsWith, cty.StringVal("hello world"), cty.StringVal("hello"), cty.True},
+		{"startswith", StartsWith, cty.StringVal("hello world"), cty.StringVal("world"), cty.False},
+		{"startswith", StartsWith, cty.StringVal("hello"), cty.StringVal(""), cty.True},
+		{"endswith", EndsWith, cty.StringVal("hello world"), cty.StringVal("world"), cty.True},
+		{"endswith", EndsWith, cty.StringVal("hello world"), cty.StringVal("hello"), cty.False},
+		{"strcontains", StrContains, cty.StringVal("hello world"), cty.StringVal("o w"), cty.True},
+		{"strcontains", StrContains, cty.StringVal("hello world"), cty.StringVal("banana"), cty.False},
+		{"startswith", StartsWith, cty.UnknownVal(cty.String), cty.StringVal("hello"), cty.UnknownVal(cty.Bool)},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s(%#v, %#v)", test.Name, test.Str, test.Arg), func(t *testing.T) {
+			got, err := test.Fn(test.Str, test.Arg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+)
+
+// documentedFunctions is the list of built-in functions from the Terraform
+// and OpenTofu language documentation. The type function is excluded as it
+// is only available in the console.
+var documentedFunctions = []string{
+	// numeric
+	"abs", "ceil", "floor", "log", "max", "min", "parseint", "pow", "signum",
+	// string
+	"chomp", "endswith", "format", "formatlist", "indent", "join", "lower", "regex", "regexall", "replace",
+	"split", "startswith", "strcontains", "strrev", "substr", "templatestring", "title", "trim", "trimprefix",
+	"trimsuffix", "trimspace", "upper",
+	// collection
+	"alltrue", "anytrue", "chunklist", "coalesce", "coalescelist", "compact", "concat", "contains", "distinct",
+	"element", "flatten", "index", "keys", "length", "lookup", "matchkeys", "merge", "one", "range", "reverse",
+	"setintersection", "setproduct", "setsubtract", "setunion", "slice", "sort", "sum", "transpose", "values",
+	"zipmap",
+	// encoding
+	"base64decode", "base64encode", "base64gzip", "base64gunzip", "csvdecode", "jsondecode", "jsonencode",
+	"textdecodebase64", "textencodebase64", "urlencode", "urldecode", "yamldecode", "yamlencode",
+	// filesystem
+	"abspath", "dirname", "pathexpand", "basename", "file", "fileexists", "fileset", "filebase64", "templatefile",
+	// date and time
+	"formatdate", "plantimestamp", "timeadd", "timecmp", "timestamp",
+	// hash and crypto
+	"base64sha256", "base64sha512", "bcrypt", "filebase64sha256", "filebase64sha512", "filemd5", "filesha1",
+	"filesha256", "filesha512", "md5", "rsadecrypt", "sha1", "sha256", "sha512", "uuid", "uuidv5",
+	// ip network
+	"cidrcontains", "cidrhost", "cidrnetmask", "cidrsubnet", "cidrsubnets",
+	// type conversion
+	"can", "ephemeralasnull", "issensitive", "nonsensitive", "sensitive", "tobool", "tolist", "tomap", "tonumber",
+	"toset", "tostring", "try",
+	// terraform provider
+	"provider::terraform::encode_tfvars", "provider::terraform::decode_tfvars", "provider::terraform::encode_expr",
+}
+
+func TestExpFunctionsDocumented(t *testing.T) {
+	fns := expFunctions(t.TempDir(), newDiscardLogger())
+
+	for _, name := range documentedFunctions {
+		assert.Containsf(t, fns, name, "missing function %s", name)
+
+		if !hclsyntax.ValidIdentifier(name) {
+			continue
+		}
+		assert.Containsf(t, fns, "core::"+name, "missing function core::%s", name)
+	}
+}
+
+// TestExpFunctionsConformance evaluates the examples given in the Terraform
+// and OpenTofu function documentation and checks that they give the same
+// result. The expected value is also an HCL expression and results are
+// compared by their JSON encoding, so tuples and lists compare equal.
+func TestExpFunctionsConformance(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`abs(-12.4)`, `12.4`},
+		{`ceil(4.1)`, `5`},
+		{`floor(4.9)`, `4`},
+		{`log(16, 2)`, `4`},
+		{`max(12, 54, 3)`, `54`},
+		{`min(12, 54, 3)`, `3`},
+		{`parseint("FF", 16)`, `255`},
+		{`pow(3, 2)`, `9`},
+		{`signum(-13)`, `-1`},
+
+		{`chomp("hello\n")`, `"hello"`},
+		{`endswith("hello world", "world")`, `true`},
+		{`format("Hello, %s!", "Ander")`, `"Hello, Ander!"`},
+		{`formatlist("Hello, %s!", ["Valentina", "Ander"])`, `["Hello, Valentina!", "Hello, Ander!"]`},
+		{`join(", ", ["foo", "bar", "baz"])`, `"foo, bar, baz"`},
+		{`lower("HELLO")`, `"hello"`},
+		{`regex("[a-z]+", "53453453.345345aaabbbccc23454")`, `"aaabbbccc"`},
+		{`regexall("[a-z]+", "1234abcd5678efgh9")`, `["abcd", "efgh"]`},
+		{`replace("1 + 2 + 3", "+", "-")`, `"1 - 2 - 3"`},
+		{`replace("hello world", "/w.*d/", "everybody")`, `"hello everybody"`},
+		{`split(",", "foo,bar,baz")`, `["foo", "bar", "baz"]`},
+		{`startswith("hello world", "hello")`, `true`},
+		{`strcontains("hello world", "wor")`, `true`},
+		{`strcontains("hello world", "wod")`, `false`},
+		{`strrev("hello")`, `"olleh"`},
+		{`substr("hello world", 1, 4)`, `"ello"`},
+		{`templatestring("Hello, $${name}!", { name = "Jane" })`, `"Hello, Jane!"`},
+		{`title("hello world")`, `"Hello World"`},
+		{`trim("?!hello?!", "!?")`, `"hello"`},
+		{`trimprefix("helloworld", "hello")`, `"world"`},
+		{`trimsuffix("helloworld", "world")`, `"hello"`},
+		{`trimspace("  hello\n\n")`, `"hello"`},
+		{`upper("hello")`, `"HELLO"`},
+
+		{`alltrue(["true", true])`, `true`},
+		{`alltrue([true, false])`, `false`},
+		{`anytrue(["true"])`, `true`},
+		{`anytrue([])`, `false`},
+		{`chunklist(["a", "b", "c", "d", "e"], 2)`, `[["a", "b"], ["c", "d"], ["e"]]`},
+		{`coalesce("", "b")`, `"b"`},
+		{`coalescelist([], ["c", "d"])`, `["c", "d"]`},
+		{`compact(["a", "", "b", null, "c"])`, `["a", "b", "c"]`},
+		{`concat(["a", ""], ["b", "c"])`, `["a", "", "b", "c"]`},
+		{`contains(["a", "b", "c"], "a")`, `true`},
+		{`distinct(["a", "b", "a", "c", "d", "b"])`, `["a", "b", "c", "d"]`},
+		{`element(["a", "b", "c"], 3)`, `"a"`},
+		{`flatten([["a", "b"], [], ["c"]])`, `["a", "b", "c"]`},
+		{`index(["a", "b", "c"], "b")`, `1`},
+		{`keys({ a = 1, c = 2, d = 3 })`, `["a", "c", "d"]`},
+		{`length("hello")`, `5`},
+		{`lookup({ a = "ay", b = "bee" }, "c", "what?")`, `"what?"`},
+		{`matchkeys(["i-123", "i-abc", "i-def"], ["us-west", "us-east", "us-east"], ["us-east"])`, `["i-abc", "i-def"]`},
+		{`merge({ a = "b", c = "d" }, { e = "f", c = "z" })`, `{ a = "b", c = "z", e = "f" }`},
+		{`one(["hello"])`, `"hello"`},
+		{`one([])`, `null`},
+		{`range(1, 4)`, `[1, 2, 3]`},
+		{`reverse([1, 2, 3])`, `[3, 2, 1]`},
+		{`setintersection(["a", "b"], ["b", "c"], ["b", "d"])`, `["b"]`},
+		{`setsubtract(["a", "b", "c"], ["a", "c"])`, `["b"]`},
+		{`setunion(["a", "b"], ["b", "c"], ["d"])`, `["a", "b", "c", "d"]`},
+		{`slice(["a", "b", "c", "d"], 1, 3)`, `["b", "c"]`},
+		{`sort(["e", "d", "a", "x"])`, `["a", "d", "e", "x"]`},
+		{`sum([10, 13, 6, 4.5])`, `33.5`},
+		{`transpose({ a = ["1", "2"], b = ["2", "3"] })`, `{ "1" = ["a"], "2" = ["a", "b"], "3" = ["b"] }`},
+		{`values({ a = 3, c = 2, d = 1 })`, `[3, 2, 1]`},
+		{`zipmap(["a", "b"], [1, 2])`, `{ a = 1, b = 2 }`},
+
+		{`base64decode("SGVsbG8gV29ybGQ=")`, `"Hello World"`},
+		{`base64encode("Hello World")`, `"SGVsbG8gV29ybGQ="`},
+		{`base64gunzip(base64gzip("hello"))`, `"hello"`},
+		{`csvdecode("a,b\n1,2\n")`, `[{ a = "1", b = "2" }]`},
+		{`jsondecode("{\"hello\": \"world\"}")`, `{ hello = "world" }`},
+		{`jsonencode({ hello = "world" })`, `"{\"hello\":\"world\"}"`},
+		{`textdecodebase64("SABlAGwAbABvACAAVwBvAHIAbABkAA==", "UTF-16LE")`, `"Hello World"`},
+		{`textencodebase64("Hello World", "UTF-16LE")`, `"SABlAGwAbABvACAAVwBvAHIAbABkAA=="`},
+		{`urlencode("Hello World!")`, `"Hello+World%21"`},
+		{`urldecode("Hello+World%21")`, `"Hello World!"`},
+		{`yamldecode("hello: world")`, `{ hello = "world" }`},
+
+		{`basename("foo/bar/baz.txt")`, `"baz.txt"`},
+		{`dirname("foo/bar/baz.txt")`, `"foo/bar"`},
+
+		{`formatdate("DD MMM YYYY hh:mm ZZZ", "2018-01-02T23:12:01Z")`, `"02 Jan 2018 23:12 UTC"`},
+		{`timeadd("2017-11-22T00:00:00Z", "10m")`, `"2017-11-22T00:10:00Z"`},
+		{`timecmp("2017-11-22T00:00:00Z", "2017-11-22T00:00:00Z")`, `0`},
+		{`timecmp("2017-11-22T00:00:00Z", "2017-11-22T01:00:00Z")`, `-1`},
+
+		{`base64sha256("hello world")`, `"uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="`},
+		{`md5("hello world")`, `"5eb63bbbe01eeed093cb22bb8f5acdc3"`},
+		{`sha1("hello world")`, `"2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"`},
+		{`uuidv5("dns", "www.terraform.io")`, `"a5008fae-b28c-5ba5-96cd-82b4c53552d6"`},
+
+		{`cidrcontains("192.168.2.0/20", "192.168.2.1")`, `true`},
+		{`cidrhost("10.12.112.0/20", 16)`, `"10.12.112.16"`},
+		{`cidrnetmask("172.16.0.0/12")`, `"255.240.0.0"`},
+		{`cidrsubnet("172.16.0.0/12", 4, 2)`, `"172.18.0.0/16"`},
+		{`cidrsubnets("10.1.0.0/16", 4, 4, 8, 4)`, `["10.1.0.0/20", "10.1.16.0/20", "10.1.32.0/24", "10.1.48.0/20"]`},
+
+		{`can(regex("^ami-", "ami-123"))`, `true`},
+		{`ephemeralasnull("hello")`, `"hello"`},
+		{`issensitive(sensitive("hello"))`, `true`},
+		{`issensitive("hello")`, `false`},
+		{`nonsensitive(sensitive("hello"))`, `"hello"`},
+		{`tobool("true")`, `true`},
+		{`tolist(["a", "b"])`, `["a", "b"]`},
+		{`tomap({ a = 1, b = 2 })`, `{ a = 1, b = 2 }`},
+		{`tonumber("1")`, `1`},
+		{`toset(["c", "b", "b"])`, `["b", "c"]`},
+		{`tostring(1)`, `"1"`},
+		{`try(tonumber("foo"), 0)`, `0`},
+
+		{`provider::terraform::encode_expr({ a = 1 })`, `"{\n  a = 1\n}"`},
+		{`provider::terraform::encode_tfvars({ a = "b" })`, `"a = \"b\"\n"`},
+		{`provider::terraform::decode_tfvars("a = \"b\"")`, `{ a = "b" }`},
+		{`core::max(1, 2)`, `2`},
+	}
+
+	fns := expFunctions(t.TempDir(), newDiscardLogger())
+	ctx := &hcl.EvalContext{Functions: fns}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got := evalTestExpr(t, tt.expr, ctx)
+			want := evalTestExpr(t, tt.want, &hcl.EvalContext{})
+
+			gotJSON, err := ctyJson.SimpleJSONValue{Value: got}.MarshalJSON()
+			require.NoError(t, err)
+			wantJSON, err := ctyJson.SimpleJSONValue{Value: want}.MarshalJSON()
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(wantJSON), string(gotJSON))
+		})
+	}
+}
+
+func evalTestExpr(t *testing.T, src string, ctx *hcl.EvalContext) cty.Value {
+	t.Helper()
+
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "test.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	val, diags := expr.Value(ctx)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	val, _ = val.UnmarkDeep()
+	return val
+}
@@ -3,6 +3,8 @@ package hcl
 import (
 	"fmt"
 	"strings"
+
+	"github.com/zclconf/go-cty/cty"
 )
 
 // ModuleCall represents a call to a defined Module by a parent Module.
@@ -32,15 +34,139 @@ type Module struct {
 	Modules  []*Module
 	Parent   *Module
 	Warnings []Warning
+	// UnmockedDataSources are the data sources without data mocks that are
+	// referenced by resources, keyed by the resource address. This is only
+	// set when the Module is parsed with a data mocks file.
+	UnmockedDataSources map[string][]string
+	// Diagnostics are the values of the Module that are still unresolved or
+	// unknown after evaluation. This is only set when the Module is parsed with
+	// OptionWithDiagnostics.
+	Diagnostics []Diagnostic
+	// Variant is the Variant that the root Module was evaluated with, see
+	// Parser.ParseVariants.
+	Variant *Variant
 
 	HasChanges bool
 }
 
+// Move is a change of a resource or module address declared by a moved block.
+type Move struct {
+	From string
+	To   string
+}
+
+// PreviousAddress returns the address that the resource at addr had before
+// the Move. It returns false if the Move doesn't apply to addr. Moving a
+// resource or module without an index moves all of its instances.
+func (mv Move) PreviousAddress(addr string) (string, bool) {
+	if addr == mv.To {
+		return mv.From, true
+	}
+
+	if strings.HasPrefix(addr, mv.To+".") || strings.HasPrefix(addr, mv.To+"[") {
+		return mv.From + strings.TrimPrefix(addr, mv.To), true
+	}
+
+	return "", false
+}
+
+// Moves returns the moved blocks of the Module and its child Modules. The
+// addresses are relative to the root Module.
+func (m *Module) Moves() []Move {
+	var moves []Move
+
+	for _, block := range m.Blocks.OfType("moved") {
+		from := block.GetAttribute("from").AddressString()
+		to := block.GetAttribute("to").AddressString()
+		if from == "" || to == "" {
+			continue
+		}
+
+		moves = append(moves, Move{
+			From: m.absoluteAddress(from),
+			To:   m.absoluteAddress(to),
+		})
+	}
+
+	for _, child := range m.Modules {
+		moves = append(moves, child.Moves()...)
+	}
+
+	return moves
+}
+
+// Imports returns the resources that are imported by import blocks, as a
+// map of the resource address to the import ID. The ID is empty if it
+// can't be evaluated. Import blocks are only valid in the root Module.
+func (m *Module) Imports() map[string]string {
+	imports := make(map[string]string)
+
+	for _, block := range m.Blocks.OfType("import") {
+		to := block.GetAttribute("to").AddressString()
+		if to == "" {
+			continue
+		}
+
+		id := ""
+		if attr := block.GetAttribute("id"); attr != nil {
+			v := attr.Value()
+			if v.IsKnown() && !v.IsNull() && v.Type() == cty.String {
+				id = v.AsString()
+			}
+		}
+
+		imports[to] = id
+	}
+
+	return imports
+}
+
+// ForgottenAddresses returns the addresses of the resources and modules
+// that removed blocks remove from the state without destroying them, i.e.
+// that have lifecycle { destroy = false }.
+func (m *Module) ForgottenAddresses() []string {
+	var addrs []string
+
+	for _, block := range m.Blocks.OfType("removed") {
+		from := block.GetAttribute("from").AddressString()
+		if from == "" {
+			continue
+		}
+
+		destroy := block.GetChildBlock("lifecycle").GetAttribute("destroy")
+		if destroy == nil {
+			continue
+		}
+
+		v := destroy.Value()
+		if !v.IsKnown() || v.IsNull() || v.Type() != cty.Bool || v.True() {
+			continue
+		}
+
+		addrs = append(addrs, m.absoluteAddress(from))
+	}
+
+	for _, child := range m.Modules {
+		addrs = append(addrs, child.ForgottenAddresses()...)
+	}
+
+	return addrs
+}
+
+func (m *Module) absoluteAddress(addr string) string {
+	if m.Name == "" {
+		return addr
+	}
+
+	return m.Name + "." + addr
+}
+
 // WarningCode is used to delineate warnings across Infracost.
 type WarningCode int
 
 const (
 	WarningMissingVars WarningCode = iota + 1
+	WarningUnmockedDataSources
 )
 
 // Warning holds information about non-critical errors that occurred within a module evaluation.
@@ -68,6 +194,21 @@ func NewMissingVarsWarning(vars []string) Warning {
 	}
 }
 
+// NewUnmockedDataSourcesWarning returns a Warning using the WarningUnmockedDataSources error code. It expects
+// that addrs is a list of data source addresses that are referenced by priced resources but have no data mocks.
+func NewUnmockedDataSourcesWarning(addrs []string) Warning {
+	return Warning{
+		Code:  WarningUnmockedDataSources,
+		Title: "Unmocked data sources",
+		Data:  addrs,
+		FriendlyMessage: fmt.Sprintf(
+			"Values were not provided for the following data sources used by priced resources: %s. %s",
+			joinQuotes(addrs),
+			"Add them to the data_mocks file in the config file to specify them.",
+		),
+	}
+}
+
 func joinQuotes(elems []string) string {
 	quoted := make([]string, len(elems))
 	for i, elem := range elems {
@@ -6,7 +6,6 @@ import (
 	"sync"
 
 	goversion "github.com/hashicorp/go-version"
-	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +19,7 @@ import (
 type Cache struct {
 	keyMap sync.Map
 	disco  *Disco
+	mirror *Mirror
 	logger *logrus.Entry
 }
 
@@ -43,7 +43,7 @@ func (c *Cache) loadFromManifest(manifest *Manifest) {
 
 // lookupModule looks up a module in the cache by its key and checks that the
 // source and version are compatible with the module in the cache.
-func (c *Cache) lookupModule(key string, moduleCall *tfconfig.ModuleCall) (*ManifestModule, error) {
+func (c *Cache) lookupModule(key string, moduleCall *moduleCall) (*ManifestModule, error) {
 	value, ok := c.keyMap.Load(key)
 	if !ok {
 		return nil, errors.New("not in cache")
@@ -62,12 +62,18 @@ func (c *Cache) lookupModule(key string, moduleCall *tfconfig.ModuleCall) (*Mani
 		}
 	}
 
-	if manifestModule.Source == moduleCall.Source {
+	if manifestModule.Source == moduleCall.Source || manifestModule.Source == registrySource {
+		if c.mirror != nil {
+			return c.checkMirrorVersion(moduleCall, manifestModule)
+		}
+
 		return checkVersion(moduleCall, manifestModule)
 	}
 
-	if manifestModule.Source == registrySource {
-		return checkVersion(moduleCall, manifestModule)
+	// The module mirror is used when there is no network access, so don't try
+	// to discover the registry location of the source.
+	if c.mirror != nil {
+		return nil, errors.New("source has changed")
 	}
 
 	url, _, err := c.disco.ModuleLocation(moduleCall.Source)
@@ -82,7 +88,32 @@ func (c *Cache) lookupModule(key string, moduleCall *tfconfig.ModuleCall) (*Mani
 	return nil, errors.New("source has changed")
 }
 
-func checkVersion(moduleCall *tfconfig.ModuleCall, manifestModule *ManifestModule) (*ManifestModule, error) {
+// checkMirrorVersion checks that the module in the cache is the version that the
+// module mirror resolves the module call to, so that the cached module is
+// replaced when a newer matching version is added to the mirror.
+func (c *Cache) checkMirrorVersion(moduleCall *moduleCall, manifestModule *ManifestModule) (*ManifestModule, error) {
+	if isLocalSource(moduleCall.Source) {
+		return manifestModule, nil
+	}
+
+	moduleAddr, _, err := splitModuleSubDir(moduleCall.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorModule, err := c.mirror.lookup(moduleAddr, moduleCall.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirrorModule.Version != manifestModule.Version {
+		return nil, errors.New("version has changed in module mirror")
+	}
+
+	return manifestModule, nil
+}
+
+func checkVersion(moduleCall *moduleCall, manifestModule *ManifestModule) (*ManifestModule, error) {
 	if moduleCall.Version != "" && manifestModule.Version != "" {
 		constraints, err := goversion.NewConstraint(moduleCall.Version)
 		if err != nil {
@@ -4,7 +4,6 @@ import (
 	"io"
 	"testing"
 
-	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,24 +54,24 @@ func TestLookupModule(t *testing.T) {
 
 	tests := []struct {
 		key           string
-		moduleCall    *tfconfig.ModuleCall
+		moduleCall    *moduleCall
 		expected      *ManifestModule
 		expectedError string
 	}{
-		{"module-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/namespace/module-a/aws", Version: ">=1.0"}, toStore["module-a"], ""},
-		{"module-a", &tfconfig.ModuleCall{Source: "namespace/module-a/aws", Version: ">=1.0"}, toStore["module-a"], ""},
-		{"module-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/namespace/module-a/aws", Version: ">=2.0"}, nil, "version constraint doesn't match"},
-		{"module-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/different-namespace/module-a-/aws", Version: "1.0.0"}, nil, "source has changed"},
-		{"module-b", &tfconfig.ModuleCall{Source: "git::https://github.com/namespace/module-b.git?v=0.5.0"}, toStore["module-b"], ""},
-		{"module-b", &tfconfig.ModuleCall{Source: "git::https://github.com/namespace/module-b.git?v=0.6.0"}, nil, "source has changed"},
-		{"module-c", &tfconfig.ModuleCall{Source: "git::https://github.com/namespace/module-c.git?v=0.6.0"}, nil, "not in cache"},
-		{"module-d", &tfconfig.ModuleCall{Source: "app.terraform.io/infracost/ec2-instance/aws"}, toStore["module-d"], ""},
-		{"submodule-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/namespace/module-a/aws//submodule/path", Version: ">=1.0"}, toStore["submodule-a"], ""},
-		{"submodule-a", &tfconfig.ModuleCall{Source: "namespace/module-a/aws//submodule/path", Version: ">=1.0"}, toStore["submodule-a"], ""},
-		{"submodule-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/namespace/module-a/aws//submodule/path", Version: ">=2.0"}, nil, "version constraint doesn't match"},
-		{"submodule-a", &tfconfig.ModuleCall{Source: "registry.terraform.io/different-namespace/module-a-/aws//submodule/path", Version: "1.0.0"}, nil, "source has changed"},
-		{"submodule-b", &tfconfig.ModuleCall{Source: "git::https://github.com/namespace/module-b.git//submodule/path?v=0.5.0"}, toStore["submodule-b"], ""},
-		{"submodule-b", &tfconfig.ModuleCall{Source: "git::https://github.com/namespace/module-b.git//submodule/path?v=0.6.0"}, nil, "source has changed"},
+		{"module-a", &moduleCall{Source: "registry.terraform.io/namespace/module-a/aws", Version: ">=1.0"}, toStore["module-a"], ""},
+		{"module-a", &moduleCall{Source: "namespace/module-a/aws", Version: ">=1.0"}, toStore["module-a"], ""},
+		{"module-a", &moduleCall{Source: "registry.terraform.io/namespace/module-a/aws", Version: ">=2.0"}, nil, "version constraint doesn't match"},
+		{"module-a", &moduleCall{Source: "registry.terraform.io/different-namespace/module-a-/aws", Version: "1.0.0"}, nil, "source has changed"},
+		{"module-b", &moduleCall{Source: "git::https://github.com/namespace/module-b.git?v=0.5.0"}, toStore["module-b"], ""},
+		{"module-b", &moduleCall{Source: "git::https://github.com/namespace/module-b.git?v=0.6.0"}, nil, "source has changed"},
+		{"module-c", &moduleCall{Source: "git::https://github.com/namespace/module-c.git?v=0.6.0"}, nil, "not in cache"},
+		{"module-d", &moduleCall{Source: "app.terraform.io/infracost/ec2-instance/aws"}, toStore["module-d"], ""},
+		{"submodule-a", &moduleCall{Source: "registry.terraform.io/namespace/module-a/aws//submodule/path", Version: ">=1.0"}, toStore["submodule-a"], ""},
+		{"submodule-a", &moduleCall{Source: "namespace/module-a/aws//submodule/path", Version: ">=1.0"}, toStore["submodule-a"], ""},
+		{"submodule-a", &moduleCall{Source: "registry.terraform.io/namespace/module-a/aws//submodule/path", Version: ">=2.0"}, nil, "version constraint doesn't match"},
+		{"submodule-a", &moduleCall{Source: "registry.terraform.io/different-namespace/module-a-/aws//submodule/path", Version: "1.0.0"}, nil, "source has changed"},
+		{"submodule-b", &moduleCall{Source: "git::https://github.com/namespace/module-b.git//submodule/path?v=0.5.0"}, toStore["submodule-b"], ""},
+		{"submodule-b", &moduleCall{Source: "git::https://github.com/namespace/module-b.git//submodule/path?v=0.6.0"}, nil, "source has changed"},
 	}
 
 	for _, test := range tests {
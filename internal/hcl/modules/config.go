@@ -0,0 +1,319 @@
+package modules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+var (
+	configFileSuffixes = []string{".tf", ".tf.json"}
+	tofuFileSuffixes   = []string{".tofu", ".tofu.json"}
+
+	moduleCallFileSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "variable", LabelNames: []string{"name"}},
+			{Type: "locals"},
+			{Type: "module", LabelNames: []string{"name"}},
+		},
+	}
+	variableDefaultSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "default"}},
+	}
+	moduleCallSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source", Required: true},
+			{Name: "version"},
+		},
+	}
+)
+
+// moduleCall is a module block found in a Terraform or OpenTofu directory, with
+// its source and version evaluated early.
+type moduleCall struct {
+	Name    string
+	Source  string
+	Version string
+	// Inputs holds the arguments of the module block that could be evaluated
+	// early, so that they can be used in the sources of the module's own module
+	// calls.
+	Inputs map[string]cty.Value
+}
+
+// IsConfigFile returns if the file name is a Terraform or OpenTofu configuration file.
+func IsConfigFile(name string) bool {
+	return configFileBase(name) != ""
+}
+
+// IsJSONConfigFile returns if the file name is a Terraform or OpenTofu JSON configuration file.
+func IsJSONConfigFile(name string) bool {
+	return strings.HasSuffix(name, ".tf.json") || strings.HasSuffix(name, ".tofu.json")
+}
+
+func isTofuFile(name string) bool {
+	for _, suffix := range tofuFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// configFileBase returns the name of the file without its configuration file
+// suffix, so main.tf and main.tofu both return main. JSON files keep a .json
+// suffix as OpenTofu only overrides main.tf.json with main.tofu.json.
+func configFileBase(name string) string {
+	for _, suffix := range append(tofuFileSuffixes, configFileSuffixes...) {
+		if strings.HasSuffix(name, suffix) {
+			base := strings.TrimSuffix(name, suffix)
+			if strings.HasSuffix(suffix, ".json") {
+				return base + ".json"
+			}
+
+			return base
+		}
+	}
+
+	return ""
+}
+
+// ConfigFileNames returns the names of the Terraform and OpenTofu configuration
+// files in the directory entries, sorted by name. As with OpenTofu, a .tofu file
+// takes precedence over the .tf file of the same name, e.g. main.tofu is used
+// instead of main.tf and main.tofu.json instead of main.tf.json.
+func ConfigFileNames(entries []os.DirEntry) []string {
+	byBase := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		base := configFileBase(name)
+		if base == "" {
+			continue
+		}
+
+		if existing, ok := byBase[base]; ok && isTofuFile(existing) {
+			continue
+		}
+
+		byBase[base] = name
+	}
+
+	names := make([]string, 0, len(byBase))
+	for _, name := range byBase {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// loadModuleCalls returns the module calls of the Terraform or OpenTofu
+// directory. The source and version of each module call are evaluated early,
+// so they can reference variables and locals as long as their values are known
+// before the module is evaluated: variable defaults, overridden by inputVars,
+// and locals that only depend on these.
+func loadModuleCalls(dir string, inputVars map[string]cty.Value) ([]*moduleCall, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module directory %s: %w", dir, err)
+	}
+
+	parser := hclparse.NewParser()
+	variables := make(map[string]hcl.Expression)
+	locals := make(map[string]hcl.Expression)
+	var moduleBlocks []*hcl.Block
+	var diags hcl.Diagnostics
+
+	for _, name := range ConfigFileNames(entries) {
+		filename := filepath.Join(dir, name)
+
+		var file *hcl.File
+		var fileDiags hcl.Diagnostics
+		if IsJSONConfigFile(name) {
+			file, fileDiags = parser.ParseJSONFile(filename)
+		} else {
+			file, fileDiags = parser.ParseHCLFile(filename)
+		}
+		diags = append(diags, fileDiags...)
+		if file == nil {
+			continue
+		}
+
+		content, _, contentDiags := file.Body.PartialContent(moduleCallFileSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "variable":
+				variableContent, _, _ := block.Body.PartialContent(variableDefaultSchema)
+				if attr, ok := variableContent.Attributes["default"]; ok {
+					variables[block.Labels[0]] = attr.Expr
+				} else {
+					variables[block.Labels[0]] = nil
+				}
+			case "locals":
+				attrs, _ := block.Body.JustAttributes()
+				for name, attr := range attrs {
+					locals[name] = attr.Expr
+				}
+			case "module":
+				moduleBlocks = append(moduleBlocks, block)
+			}
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ctx := EarlyEvalContext(variables, locals, inputVars)
+
+	calls := make([]*moduleCall, 0, len(moduleBlocks))
+	for _, block := range moduleBlocks {
+		call, err := newModuleCall(block, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+func newModuleCall(block *hcl.Block, ctx *hcl.EvalContext) (*moduleCall, error) {
+	name := block.Labels[0]
+
+	content, remain, diags := block.Body.PartialContent(moduleCallSchema)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid module %s: %w", name, diags)
+	}
+
+	source, err := earlyEvalString(content.Attributes["source"], ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source for module %s: %w", name, err)
+	}
+
+	var version string
+	if attr, ok := content.Attributes["version"]; ok {
+		version, err = earlyEvalString(attr, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version for module %s: %w", name, err)
+		}
+	}
+
+	inputs := make(map[string]cty.Value)
+	attrs, _ := remain.JustAttributes()
+	for attrName, attr := range attrs {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() || !val.IsWhollyKnown() {
+			continue
+		}
+
+		inputs[attrName] = val
+	}
+
+	return &moduleCall{
+		Name:    name,
+		Source:  source,
+		Version: version,
+		Inputs:  inputs,
+	}, nil
+}
+
+func earlyEvalString(attr *hcl.Attribute, ctx *hcl.EvalContext) (string, error) {
+	val, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	if !val.IsWhollyKnown() {
+		return "", fmt.Errorf("value must be known before evaluation and can only reference variables and locals")
+	}
+
+	if val.IsNull() {
+		return "", fmt.Errorf("value must not be null")
+	}
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		return "", fmt.Errorf("value must be a string: %w", err)
+	}
+
+	return val.AsString(), nil
+}
+
+// EarlyEvalContext returns the context used to evaluate expressions that OpenTofu
+// evaluates before the rest of the module, e.g. module sources and backend
+// configuration. The context has the variables of the module, using the
+// defaults of the variable expressions overridden by inputVars, locals that can
+// be evaluated from these variables and path.module. path.module is ".", so
+// that module sources using it are still relative to the module directory.
+// Values that can't be known early are unknown.
+func EarlyEvalContext(variables map[string]hcl.Expression, locals map[string]hcl.Expression, inputVars map[string]cty.Value) *hcl.EvalContext {
+	vars := make(map[string]cty.Value, len(variables))
+	for name, expr := range variables {
+		vars[name] = cty.DynamicVal
+		if expr == nil {
+			continue
+		}
+
+		val, diags := expr.Value(nil)
+		if !diags.HasErrors() {
+			vars[name] = val
+		}
+	}
+
+	for name, val := range inputVars {
+		vars[name] = val
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(vars),
+			"path": cty.ObjectVal(map[string]cty.Value{
+				"module": cty.StringVal("."),
+			}),
+		},
+	}
+
+	localVals := make(map[string]cty.Value, len(locals))
+	for name := range locals {
+		localVals[name] = cty.DynamicVal
+	}
+
+	// locals can reference each other, so keep evaluating the unresolved locals
+	// until none of them can be resolved in a pass.
+	for resolved := true; resolved; {
+		resolved = false
+		ctx.Variables["local"] = cty.ObjectVal(localVals)
+
+		for name, expr := range locals {
+			if localVals[name].IsWhollyKnown() {
+				continue
+			}
+
+			val, diags := expr.Value(ctx)
+			if diags.HasErrors() || !val.IsWhollyKnown() {
+				continue
+			}
+
+			localVals[name] = val
+			resolved = true
+		}
+	}
+	ctx.Variables["local"] = cty.ObjectVal(localVals)
+
+	return ctx
+}
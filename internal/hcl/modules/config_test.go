@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConfigFileNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.tf", "main.tofu", "vars.tf", "outputs.tf.json", "outputs.tofu.json", "other.tofu", "terraform.tfvars", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "modules.tf"), 0755))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"main.tofu", "other.tofu", "outputs.tofu.json", "vars.tf"}, ConfigFileNames(entries))
+}
+
+func TestLoadModuleCalls(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.tofu"), []byte(`
+variable "registry" {
+	default = "app.terraform.io"
+}
+
+variable "module_version" {}
+
+variable "name" {}
+
+locals {
+	namespace = "${var.registry}/infracost"
+	source    = "${local.namespace}/ec2-instance/aws"
+}
+
+module "registry" {
+	source  = local.source
+	version = var.module_version
+	name    = var.name
+	size    = "small"
+}
+
+module "local" {
+	source = "${path.module}/modules/local"
+}
+`), 0600)
+	require.NoError(t, err)
+
+	inputVars := map[string]cty.Value{"module_version": cty.StringVal("~> 1.0")}
+	calls, err := loadModuleCalls(dir, inputVars)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*moduleCall{
+		{
+			Name:    "registry",
+			Source:  "app.terraform.io/infracost/ec2-instance/aws",
+			Version: "~> 1.0",
+			Inputs:  map[string]cty.Value{"size": cty.StringVal("small")},
+		},
+		{
+			Name:   "local",
+			Source: "./modules/local",
+			Inputs: map[string]cty.Value{},
+		},
+	}, calls)
+
+	err = os.WriteFile(filepath.Join(dir, "unknown.tf"), []byte(`
+module "unknown" {
+	source = var.name
+}
+`), 0600)
+	require.NoError(t, err)
+
+	_, err = loadModuleCalls(dir, inputVars)
+	assert.ErrorContains(t, err, "invalid source for module unknown: value must be known before evaluation")
+}
@@ -66,21 +66,12 @@ func (r *PackageFetcher) fetch(moduleAddr string, dest string) error {
 
 	r.logger.WithFields(logrus.Fields{"cached_module_addresses": cached}).Debugf("module %s does not exist in cache, proceeding to download", moduleAddr)
 
-	decompressors := map[string]getter.Decompressor{}
-	for k, decompressor := range getter.Decompressors {
-		decompressors[k] = decompressor
-	}
-	// This one is added by Terraform here: https://github.com/hashicorp/terraform/blob/affe2c329561f40f13c0e94f4570321977527a77/internal/getmodules/getter.go#L64
-	// But is not in the list of default compressors here: https://github.com/hashicorp/go-getter/blob/main/decompress.go#L32
-	// I'm not sure if we really need it, but added it just in case/
-	decompressors["tar.tbz2"] = new(getter.TarBzip2Decompressor)
-
 	client := getter.Client{
 		Src:           moduleAddr,
 		Dst:           dest,
 		Pwd:           dest,
 		Mode:          getter.ClientModeDir,
-		Decompressors: decompressors,
+		Decompressors: decompressors(),
 		// We don't need to specify any of the Getters, since Terraform uses the same as the default Getter values,
 		// but if we do need to at some point we can specify them here:
 		// Getters: getters,
@@ -95,3 +86,17 @@ func (r *PackageFetcher) fetch(moduleAddr string, dest string) error {
 
 	return nil
 }
+
+// decompressors returns the decompressors used to extract downloaded module archives.
+func decompressors() map[string]getter.Decompressor {
+	d := map[string]getter.Decompressor{}
+	for k, decompressor := range getter.Decompressors {
+		d[k] = decompressor
+	}
+	// This one is added by Terraform here: https://github.com/hashicorp/terraform/blob/affe2c329561f40f13c0e94f4570321977527a77/internal/getmodules/getter.go#L64
+	// But is not in the list of default compressors here: https://github.com/hashicorp/go-getter/blob/main/decompress.go#L32
+	// I'm not sure if we really need it, but added it just in case/
+	d["tar.tbz2"] = new(getter.TarBzip2Decompressor)
+
+	return d
+}
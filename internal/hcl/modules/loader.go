@@ -12,8 +12,8 @@ import (
 	"sync"
 
 	getter "github.com/hashicorp/go-getter"
-	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
 	"golang.org/x/sync/errgroup"
 
 	intSync "github.com/infracost/infracost/internal/sync"
@@ -49,6 +49,7 @@ type ModuleLoader struct {
 
 	packageFetcher *PackageFetcher
 	registryLoader *RegistryLoader
+	mirror         *Mirror
 	logger         *logrus.Entry
 }
 
@@ -71,6 +72,13 @@ func NewModuleLoader(cachePath string, credentialsSource *CredentialsSource, log
 	return m
 }
 
+// SetMirror sets the Mirror that registry and remote modules are loaded from
+// instead of being downloaded. Modules that aren't in the Mirror fail to load.
+func (m *ModuleLoader) SetMirror(mirror *Mirror) {
+	m.mirror = mirror
+	m.cache.mirror = mirror
+}
+
 // downloadDir returns the path to the directory where remote modules are downloaded relative to the current working directory
 func (m *ModuleLoader) downloadDir() string {
 	return filepath.Join(m.cachePath, downloadDir)
@@ -95,7 +103,8 @@ func (m *ModuleLoader) tfManifestFilePath(path string) string {
 // Load loads the modules from the given path.
 // For each module it checks if the module has already been downloaded, by checking if iut exists in the manifest
 // If not then it downloads the module from the registry or from a remote source and updates the module manifest with the latest metadata.
-func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
+// The inputVars are the root module variable values, which are used to evaluate module sources and versions that reference variables.
+func (m *ModuleLoader) Load(path string, inputVars map[string]cty.Value) (man *Manifest, err error) {
 	defer func() {
 		if man != nil {
 			man.cachePath = m.cachePath
@@ -149,7 +158,7 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 	}
 	m.cache.loadFromManifest(manifest)
 
-	metadatas, err := m.loadModules(path, "")
+	metadatas, err := m.loadModules(path, "", inputVars)
 	if err != nil {
 		return nil, err
 	}
@@ -167,17 +176,17 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 }
 
 // loadModules recursively loads the modules from the given path.
-func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModule, error) {
+func (m *ModuleLoader) loadModules(path string, prefix string, inputVars map[string]cty.Value) ([]*ManifestModule, error) {
 	manifestModules := make([]*ManifestModule, 0)
 
-	module, diags := tfconfig.LoadModule(path)
-	if diags.HasErrors() {
-		return nil, fmt.Errorf("failed to inspect module path %s diag: %w", path, diags.Err())
+	moduleCalls, err := loadModuleCalls(path, inputVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect module path %s diag: %w", path, err)
 	}
 
-	numJobs := len(module.ModuleCalls)
-	jobs := make(chan *tfconfig.ModuleCall, numJobs)
-	for _, moduleCall := range module.ModuleCalls {
+	numJobs := len(moduleCalls)
+	jobs := make(chan *moduleCall, numJobs)
+	for _, moduleCall := range moduleCalls {
 		jobs <- moduleCall
 	}
 	close(jobs)
@@ -198,7 +207,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 				manifestMu.Unlock()
 
 				moduleDir := filepath.Join(m.cachePath, metadata.Dir)
-				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".")
+				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".", moduleCall.Inputs)
 				if err != nil {
 					return err
 				}
@@ -212,7 +221,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 		})
 	}
 
-	err := errGroup.Wait()
+	err = errGroup.Wait()
 	if err != nil {
 		return manifestModules, fmt.Errorf("could not load modules for path %s %w", path, err)
 	}
@@ -226,7 +235,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 // 2. Checks if the module is a local module.
 // 3. Checks if the module is a registry module and downloads it.
 // 4. Checks if the module is a remote module and downloads it.
-func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath string, prefix string) (*ManifestModule, error) {
+func (m *ModuleLoader) loadModule(moduleCall *moduleCall, parentPath string, prefix string) (*ManifestModule, error) {
 	key := prefix + moduleCall.Name
 	source := moduleCall.Source
 
@@ -237,12 +246,12 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 		// Test if we can actually load the module. If not, then we should try re-loading it.
 		// This can happen if the directory the module was downloaded to has been deleted and moved
 		// so the existing manifest.json is out-of-date.
-		_, diags := tfconfig.LoadModule(path.Join(m.cachePath, manifestModule.Dir))
-		if !diags.HasErrors() {
+		_, loadErr := loadModuleCalls(path.Join(m.cachePath, manifestModule.Dir), moduleCall.Inputs)
+		if loadErr == nil {
 			return manifestModule, err
 		}
 
-		m.logger.Debugf("module %s cannot be loaded, re-loading: %s", key, diags.Err())
+		m.logger.Debugf("module %s cannot be loaded, re-loading: %s", key, loadErr)
 	} else {
 		m.logger.Debugf("module %s needs loading: %s", key, err.Error())
 	}
@@ -281,6 +290,20 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 	}
 	manifestModule.Dir = path.Clean(filepath.Join(moduleDownloadDir, submodulePath))
 
+	if m.mirror != nil {
+		mirrorModule, err := m.loadFromMirror(moduleAddr, moduleCall.Version, dest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load module %s: %w", key, err)
+		}
+
+		if mirrorModule.Version != "" {
+			manifestModule.Source = joinModuleSubDir(mirrorModule.Source, submodulePath)
+			manifestModule.Version = mirrorModule.Version
+		}
+
+		return manifestModule, nil
+	}
+
 	lookupResult, err := m.registryLoader.lookupModule(moduleAddr, moduleCall.Version)
 	if err != nil {
 		return nil, fmt.Errorf("error looking up registry module %s: %w", key, err)
@@ -321,13 +344,92 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 	return manifestModule, nil
 }
 
+// LoadSource downloads the module at the given remote source, e.g. the
+// Terraform source of a Terragrunt unit, and returns the path to the module.
+// Registry sources are looked up using the version constraint.
+func (m *ModuleLoader) LoadSource(source string, version string) (string, error) {
+	moduleAddr, submodulePath, err := splitModuleSubDir(source)
+	if err != nil {
+		return "", err
+	}
+
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(moduleAddr+version))) //nolint
+	dest := filepath.Join(m.downloadDir(), hash)
+
+	// lock the module address so that we don't interact with an incomplete download.
+	unlock := m.sync.Lock(moduleAddr)
+	defer unlock()
+
+	if _, err := os.Stat(dest); err == nil {
+		return filepath.Join(dest, submodulePath), nil
+	}
+
+	if m.mirror != nil {
+		_, err = m.loadFromMirror(moduleAddr, version, dest)
+		if err != nil {
+			return "", fmt.Errorf("failed to load module %s: %w", source, err)
+		}
+
+		return filepath.Join(dest, submodulePath), nil
+	}
+
+	lookupResult, err := m.registryLoader.lookupModule(moduleAddr, version)
+	if err != nil {
+		return "", fmt.Errorf("error looking up registry module %s: %w", source, err)
+	}
+
+	if lookupResult.OK {
+		err = m.registryLoader.downloadModule(lookupResult, dest)
+		if err != nil {
+			return "", fmt.Errorf("failed to download registry module %s: %w", source, err)
+		}
+
+		return filepath.Join(dest, submodulePath), nil
+	}
+
+	m.logger.Debugf("Downloading module from remote %s", source)
+
+	err = m.packageFetcher.fetch(moduleAddr, dest)
+	if err != nil {
+		return "", fmt.Errorf("failed to download remote module %s: %w", source, err)
+	}
+
+	return filepath.Join(dest, submodulePath), nil
+}
+
+// loadFromMirror copies the module from the Mirror to dest, unless dest already
+// exists, and returns the mirrored module.
+func (m *ModuleLoader) loadFromMirror(moduleAddr string, version string, dest string) (*MirrorModule, error) {
+	mirrorModule, err := m.mirror.lookup(moduleAddr, version)
+	if err != nil {
+		return nil, fmt.Errorf("%w, run infracost modules vendor to add it", err)
+	}
+
+	if _, err := os.Stat(dest); err == nil {
+		return mirrorModule, nil
+	}
+
+	m.logger.Debugf("loading module %s from module mirror %s", moduleAddr, mirrorModule.Path)
+
+	err = m.mirror.copyModule(mirrorModule, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return mirrorModule, nil
+}
+
 // isLocalModule checks if the module is a local module by checking
 // if the module source starts with any known local prefixes
-func (m *ModuleLoader) isLocalModule(moduleCall *tfconfig.ModuleCall) bool {
-	return strings.HasPrefix(moduleCall.Source, "./") ||
-		strings.HasPrefix(moduleCall.Source, "../") ||
-		strings.HasPrefix(moduleCall.Source, ".\\") ||
-		strings.HasPrefix(moduleCall.Source, "..\\")
+func (m *ModuleLoader) isLocalModule(moduleCall *moduleCall) bool {
+	return isLocalSource(moduleCall.Source)
+}
+
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") ||
+		strings.HasPrefix(source, "../") ||
+		strings.HasPrefix(source, ".\\") ||
+		strings.HasPrefix(source, "..\\")
 }
 
 func splitModuleSubDir(moduleSource string) (string, string, error) {
@@ -27,7 +27,7 @@ func testLoaderE2E(t *testing.T, path string, expectedModules []*ManifestModule,
 
 	moduleLoader := NewModuleLoader(path, &CredentialsSource{FetchToken: credentials.FindTerraformCloudToken}, logrus.NewEntry(logger), &sync2.KeyMutex{})
 
-	manifest, err := moduleLoader.Load(path)
+	manifest, err := moduleLoader.Load(path, nil)
 	if !assert.NoError(t, err) {
 		t.FailNow()
 	}
@@ -240,21 +240,21 @@ func TestMultiProject(t *testing.T) {
 	wg.Add(3)
 	go func(t *testing.T) {
 		t.Helper()
-		_, err := moduleLoader.Load(filepath.Join(path, "dev"))
+		_, err := moduleLoader.Load(filepath.Join(path, "dev"), nil)
 		wg.Done()
 		assert.NoError(t, err)
 	}(t)
 
 	go func(t *testing.T) {
 		t.Helper()
-		_, err := moduleLoader.Load(filepath.Join(path, "prod"))
+		_, err := moduleLoader.Load(filepath.Join(path, "prod"), nil)
 		wg.Done()
 		assert.NoError(t, err)
 	}(t)
 
 	go func(t *testing.T) {
 		t.Helper()
-		_, err := moduleLoader.Load(filepath.Join(path, "with_existing_terraform_mods"))
+		_, err := moduleLoader.Load(filepath.Join(path, "with_existing_terraform_mods"), nil)
 		wg.Done()
 		assert.NoError(t, err)
 	}(t)
@@ -414,7 +414,7 @@ func TestMultiProject(t *testing.T) {
 func assertModulesEqual(t *testing.T, moduleLoader *ModuleLoader, path string, expectedModules []*ManifestModule) {
 	t.Helper()
 
-	manifest, err := moduleLoader.Load(path)
+	manifest, err := moduleLoader.Load(path, nil)
 	assert.NoError(t, err)
 	actualModules := manifest.Modules
 
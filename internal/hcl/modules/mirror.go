@@ -0,0 +1,289 @@
+package modules
+
+import (
+	"crypto/md5" //nolint
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	getter "github.com/hashicorp/go-getter"
+	"github.com/otiai10/copy"
+)
+
+// mirrorIndexFile is the name of the file in the mirror directory that lists the mirrored modules.
+var mirrorIndexFile = "index.json"
+
+// ErrNotInMirror is returned when a module can't be found in the Mirror.
+var ErrNotInMirror = errors.New("module not found in module mirror")
+
+// Mirror is a local copy of registry and remote modules, which is used instead of
+// downloading modules, e.g. on CI runners without internet access. A mirror is a
+// directory with an index.json file that lists the modules and where their copies
+// are, relative to the mirror directory. A copy is either a directory or an archive,
+// e.g. a .tar.gz file:
+//
+//	{
+//	  "modules": [
+//	    {
+//	      "source": "registry.terraform.io/terraform-aws-modules/vpc/aws",
+//	      "version": "5.1.2",
+//	      "path": "registry/registry.terraform.io/terraform-aws-modules/vpc/aws/5.1.2"
+//	    },
+//	    {
+//	      "source": "git::https://github.com/infracost/example-module.git",
+//	      "ref": "v1.2.0",
+//	      "path": "remote/example-module-v1.2.0.tar.gz"
+//	    }
+//	  ]
+//	}
+//
+// Registry modules are matched by their normalized source and the latest version
+// that meets the version constraints of the module call. Remote modules are matched
+// by their source without the ref query parameter and the ref.
+type Mirror struct {
+	dir string
+	mu  sync.Mutex
+
+	Modules []*MirrorModule `json:"modules"`
+}
+
+// MirrorModule is a module in the Mirror.
+type MirrorModule struct {
+	// Source is the normalized registry source, e.g. registry.terraform.io/namespace/name/provider,
+	// or the remote source without the ref query parameter.
+	Source string `json:"source"`
+	// Version is the version of a registry module.
+	Version string `json:"version,omitempty"`
+	// Ref is the ref of a remote module, e.g. a git tag or commit.
+	Ref string `json:"ref,omitempty"`
+	// Path is the directory or archive of the module copy, relative to the mirror directory.
+	Path string `json:"path"`
+}
+
+// LoadMirror reads the Mirror in the directory. If the directory doesn't have an
+// index yet, an empty Mirror is returned that modules can be vendored into.
+func LoadMirror(dir string) (*Mirror, error) {
+	mirror := &Mirror{dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, mirrorIndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return mirror, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module mirror index: %w", err)
+	}
+
+	err = json.Unmarshal(data, mirror)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal module mirror index: %w", err)
+	}
+
+	return mirror, nil
+}
+
+// lookup returns the mirrored module for the module address, which must not have
+// a submodule path, and the version constraints of the module call. For registry
+// modules the returned module has the normalized registry source.
+func (m *Mirror) lookup(moduleAddr string, versionConstraints string) (*MirrorModule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if registrySource, err := normalizeRegistrySource(moduleAddr); err == nil {
+		byVersion := map[string]*MirrorModule{}
+		var versions []string
+		for _, module := range m.Modules {
+			if module.Source == registrySource && module.Version != "" {
+				byVersion[module.Version] = module
+				versions = append(versions, module.Version)
+			}
+		}
+
+		if len(versions) > 0 {
+			version, err := findLatestMatchingVersion(versions, versionConstraints)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrNotInMirror, err)
+			}
+
+			return byVersion[version], nil
+		}
+	}
+
+	source, ref := splitRef(moduleAddr)
+	for _, module := range m.Modules {
+		if module.Version == "" && module.Source == source && module.Ref == ref {
+			return module, nil
+		}
+	}
+
+	return nil, ErrNotInMirror
+}
+
+// copyModule copies the mirrored module to dest, extracting it if it is an archive.
+func (m *Mirror) copyModule(module *MirrorModule, dest string) error {
+	src := filepath.Join(m.dir, module.Path)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to find mirrored module %s: %w", module.Source, err)
+	}
+
+	if info.IsDir() {
+		err = copy.Copy(src, dest, copy.Options{
+			OnSymlink: func(src string) copy.SymlinkAction {
+				return copy.Shallow
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy mirrored module %s: %w", module.Source, err)
+		}
+
+		return nil
+	}
+
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+
+	client := getter.Client{
+		Src:           abs,
+		Dst:           dest,
+		Pwd:           dest,
+		Mode:          getter.ClientModeDir,
+		Decompressors: decompressors(),
+		Getters: map[string]getter.Getter{
+			"file": new(getter.FileGetter),
+		},
+	}
+
+	err = client.Get()
+	if err != nil {
+		return fmt.Errorf("failed to extract mirrored module %s: %w", module.Source, err)
+	}
+
+	return nil
+}
+
+// Add copies the registry and remote modules of the manifest into the Mirror and
+// adds them to its index. Modules that are already in the Mirror are skipped. Call
+// Save to write the index once all modules have been added.
+func (m *Mirror) Add(manifest *Manifest) error {
+	for _, manifestModule := range manifest.Modules {
+		if isLocalSource(manifestModule.Source) {
+			continue
+		}
+
+		moduleAddr, submodulePath, err := splitModuleSubDir(manifestModule.Source)
+		if err != nil {
+			return err
+		}
+
+		module := &MirrorModule{}
+		if registrySource, err := normalizeRegistrySource(moduleAddr); err == nil && manifestModule.Version != "" {
+			module.Source = registrySource
+			module.Version = manifestModule.Version
+			module.Path = filepath.Join("registry", registrySource, manifestModule.Version)
+		} else {
+			module.Source, module.Ref = splitRef(moduleAddr)
+			module.Path = filepath.Join("remote", fmt.Sprintf("%x", md5.Sum([]byte(moduleAddr)))) //nolint
+		}
+
+		if m.contains(module) {
+			continue
+		}
+
+		packageDir := filepath.Join(manifest.cachePath, manifestModule.Dir)
+		if submodulePath != "" {
+			packageDir = strings.TrimSuffix(filepath.Clean(packageDir), filepath.Clean(submodulePath))
+		}
+
+		err = copy.Copy(packageDir, filepath.Join(m.dir, module.Path), copy.Options{
+			Skip: func(src string) (bool, error) {
+				return filepath.Base(src) == ".git", nil
+			},
+			OnSymlink: func(src string) copy.SymlinkAction {
+				return copy.Shallow
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy module %s to module mirror: %w", manifestModule.Source, err)
+		}
+
+		m.mu.Lock()
+		m.Modules = append(m.Modules, module)
+		m.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (m *Mirror) contains(module *MirrorModule) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.Modules {
+		if existing.Source == module.Source && existing.Version == module.Version && existing.Ref == module.Ref {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Save writes the index of the Mirror to its directory.
+func (m *Mirror) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sort.Slice(m.Modules, func(i, j int) bool {
+		if m.Modules[i].Source != m.Modules[j].Source {
+			return m.Modules[i].Source < m.Modules[j].Source
+		}
+
+		return m.Modules[i].Version+m.Modules[i].Ref < m.Modules[j].Version+m.Modules[j].Ref
+	})
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal module mirror index: %w", err)
+	}
+
+	err = os.MkdirAll(m.dir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create module mirror directory: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(m.dir, mirrorIndexFile), b, 0644) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to write module mirror index: %w", err)
+	}
+
+	return nil
+}
+
+// splitRef splits the ref query parameter from the remote module address.
+func splitRef(moduleAddr string) (string, string) {
+	base, rawQuery, ok := strings.Cut(moduleAddr, "?")
+	if !ok {
+		return moduleAddr, ""
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return moduleAddr, ""
+	}
+
+	ref := query.Get("ref")
+	query.Del("ref")
+	if len(query) > 0 {
+		base += "?" + query.Encode()
+	}
+
+	return base, ref
+}
@@ -0,0 +1,193 @@
+package modules
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sync2 "github.com/infracost/infracost/internal/sync"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}))
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+}
+
+func newTestMirrorLoader(t *testing.T, path string, mirror *Mirror) *ModuleLoader {
+	t.Helper()
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+
+	loader := NewModuleLoader(path, nil, logrus.NewEntry(l), &sync2.KeyMutex{})
+	loader.SetMirror(mirror)
+
+	return loader
+}
+
+func TestMirrorLoad(t *testing.T) {
+	mirrorDir := t.TempDir()
+	writeFile(t, filepath.Join(mirrorDir, "registry/vpc/5.0.0/main.tf"), `variable "name" {}`)
+	writeFile(t, filepath.Join(mirrorDir, "registry/vpc/5.1.0/main.tf"), `variable "name" {}`)
+	writeFile(t, filepath.Join(mirrorDir, "registry/vpc/5.1.0/modules/nat/main.tf"), `variable "nat" {}`)
+	writeTarGz(t, filepath.Join(mirrorDir, "remote/network.tar.gz"), map[string]string{"main.tf": `variable "cidr" {}`})
+	writeFile(t, filepath.Join(mirrorDir, mirrorIndexFile), `{
+  "modules": [
+    {"source": "registry.terraform.io/terraform-aws-modules/vpc/aws", "version": "5.0.0", "path": "registry/vpc/5.0.0"},
+    {"source": "registry.terraform.io/terraform-aws-modules/vpc/aws", "version": "5.1.0", "path": "registry/vpc/5.1.0"},
+    {"source": "git::https://github.com/infracost/network.git", "ref": "v1.0.0", "path": "remote/network.tar.gz"}
+  ]
+}`)
+
+	mirror, err := LoadMirror(mirrorDir)
+	require.NoError(t, err)
+
+	path := t.TempDir()
+	writeFile(t, filepath.Join(path, "main.tf"), `
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+
+module "nat" {
+  source  = "terraform-aws-modules/vpc/aws//modules/nat"
+  version = "5.1.0"
+}
+
+module "network" {
+  source = "git::https://github.com/infracost/network.git?ref=v1.0.0"
+}
+`)
+
+	manifest, err := newTestMirrorLoader(t, path, mirror).Load(path, nil)
+	require.NoError(t, err)
+	require.Len(t, manifest.Modules, 3)
+
+	byKey := map[string]*ManifestModule{}
+	for _, module := range manifest.Modules {
+		byKey[module.Key] = module
+	}
+
+	assert.Equal(t, "registry.terraform.io/terraform-aws-modules/vpc/aws", byKey["vpc"].Source)
+	assert.Equal(t, "5.1.0", byKey["vpc"].Version)
+	assert.FileExists(t, filepath.Join(manifest.FindModulePath("vpc"), "main.tf"))
+
+	assert.Equal(t, "registry.terraform.io/terraform-aws-modules/vpc/aws//modules/nat", byKey["nat"].Source)
+	assert.FileExists(t, filepath.Join(manifest.FindModulePath("nat"), "main.tf"))
+
+	assert.Equal(t, "git::https://github.com/infracost/network.git?ref=v1.0.0", byKey["network"].Source)
+	assert.Empty(t, byKey["network"].Version)
+	assert.FileExists(t, filepath.Join(manifest.FindModulePath("network"), "main.tf"))
+
+	// Loading again uses the modules in the cache.
+	manifest, err = newTestMirrorLoader(t, path, mirror).Load(path, nil)
+	require.NoError(t, err)
+	assert.Len(t, manifest.Modules, 3)
+}
+
+func TestMirrorLoadMissingModule(t *testing.T) {
+	mirror, err := LoadMirror(t.TempDir())
+	require.NoError(t, err)
+
+	path := t.TempDir()
+	writeFile(t, filepath.Join(path, "main.tf"), `
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+`)
+
+	_, err = newTestMirrorLoader(t, path, mirror).Load(path, nil)
+	assert.ErrorIs(t, err, ErrNotInMirror)
+	assert.ErrorContains(t, err, "infracost modules vendor")
+}
+
+func TestMirrorAdd(t *testing.T) {
+	cachePath := t.TempDir()
+	writeFile(t, filepath.Join(cachePath, ".infracost/terraform_modules/a/main.tf"), `variable "name" {}`)
+	writeFile(t, filepath.Join(cachePath, ".infracost/terraform_modules/a/modules/nat/main.tf"), `variable "nat" {}`)
+	writeFile(t, filepath.Join(cachePath, ".infracost/terraform_modules/b/main.tf"), `variable "cidr" {}`)
+	writeFile(t, filepath.Join(cachePath, ".infracost/terraform_modules/b/.git/HEAD"), `ref: refs/heads/main`)
+
+	manifest := &Manifest{
+		cachePath: cachePath,
+		Modules: []*ManifestModule{
+			{Key: "local", Source: "./local", Dir: "local"},
+			{Key: "vpc", Source: "registry.terraform.io/terraform-aws-modules/vpc/aws", Version: "5.1.0", Dir: ".infracost/terraform_modules/a"},
+			{Key: "nat", Source: "registry.terraform.io/terraform-aws-modules/vpc/aws//modules/nat", Version: "5.1.0", Dir: ".infracost/terraform_modules/a/modules/nat"},
+			{Key: "network", Source: "git::https://github.com/infracost/network.git?ref=v1.0.0", Dir: ".infracost/terraform_modules/b"},
+		},
+	}
+
+	mirrorDir := t.TempDir()
+	mirror, err := LoadMirror(mirrorDir)
+	require.NoError(t, err)
+	require.NoError(t, mirror.Add(manifest))
+	require.NoError(t, mirror.Save())
+
+	mirror, err = LoadMirror(mirrorDir)
+	require.NoError(t, err)
+	require.Len(t, mirror.Modules, 2)
+
+	vpc, err := mirror.lookup("terraform-aws-modules/vpc/aws", ">= 5.0")
+	require.NoError(t, err)
+	assert.Equal(t, "5.1.0", vpc.Version)
+	assert.FileExists(t, filepath.Join(mirrorDir, vpc.Path, "modules/nat/main.tf"))
+
+	network, err := mirror.lookup("git::https://github.com/infracost/network.git?ref=v1.0.0", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", network.Ref)
+	assert.FileExists(t, filepath.Join(mirrorDir, network.Path, "main.tf"))
+	assert.NoDirExists(t, filepath.Join(mirrorDir, network.Path, ".git"))
+
+	_, err = mirror.lookup("git::https://github.com/infracost/network.git?ref=v2.0.0", "")
+	assert.ErrorIs(t, err, ErrNotInMirror)
+}
+
+func TestSplitRef(t *testing.T) {
+	tests := []struct {
+		source       string
+		expectedBase string
+		expectedRef  string
+	}{
+		{"git::https://github.com/infracost/network.git", "git::https://github.com/infracost/network.git", ""},
+		{"git::https://github.com/infracost/network.git?ref=v1.0.0", "git::https://github.com/infracost/network.git", "v1.0.0"},
+		{"git::https://github.com/infracost/network.git?depth=1&ref=main", "git::https://github.com/infracost/network.git?depth=1", "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			base, ref := splitRef(tt.source)
+			assert.Equal(t, tt.expectedBase, base)
+			assert.Equal(t, tt.expectedRef, ref)
+		})
+	}
+}
@@ -38,6 +38,39 @@ func OptionWithTFVarsPaths(paths []string) Option {
 	}
 }
 
+// OptionWithDataMocksPath sets the path of the data mocks file. Relative paths
+// are relative to the Parser initialPath. See DataMocks for the format of the file.
+func OptionWithDataMocksPath(name string) Option {
+	return func(p *Parser) {
+		if name == "" {
+			return
+		}
+
+		if filepath.IsAbs(name) {
+			p.dataMocksPath = name
+			return
+		}
+
+		p.dataMocksPath = path.Join(p.initialPath, name)
+	}
+}
+
+// OptionWithRemoteStates sets the outputs to use for terraform_remote_state data
+// sources. See RemoteState for how data sources are matched.
+func OptionWithRemoteStates(states RemoteStates) Option {
+	return func(p *Parser) {
+		p.remoteStates = append(p.remoteStates, states...)
+	}
+}
+
+// OptionWithDiagnostics sets the Parser to record the values that are still
+// unresolved or unknown after evaluation as Module Diagnostics.
+func OptionWithDiagnostics() Option {
+	return func(p *Parser) {
+		p.diagnostics = true
+	}
+}
+
 func OptionStopOnHCLError() Option {
 	return func(p *Parser) {
 		p.stopOnHCLError = true
@@ -191,6 +224,9 @@ type Parser struct {
 	tfEnvVars             map[string]cty.Value
 	defaultVarFiles       []string
 	tfvarsPaths           []string
+	dataMocksPath         string
+	remoteStates          RemoteStates
+	diagnostics           bool
 	inputVars             map[string]cty.Value
 	stopOnHCLError        bool
 	workspaceName         string
@@ -224,6 +260,12 @@ func LoadParsers(initialPath string, loader *modules.ModuleLoader, locatorConfig
 	return parsers, nil
 }
 
+// NewParser returns a Parser for the Terraform project at the given root path,
+// without locating the projects within it like LoadParsers.
+func NewParser(projectRoot RootPath, moduleLoader *modules.ModuleLoader, logger *logrus.Entry, options ...Option) *Parser {
+	return newParser(projectRoot, moduleLoader, logger, options...)
+}
+
 func newParser(projectRoot RootPath, moduleLoader *modules.ModuleLoader, logger *logrus.Entry, options ...Option) *Parser {
 	parserLogger := logger.WithFields(logrus.Fields{
 		"parser_path": projectRoot.Path,
@@ -276,37 +318,195 @@ func newParser(projectRoot RootPath, moduleLoader *modules.ModuleLoader, logger
 //
 // ParseDirectory returns the root Module that represents the top of the Terraform Config tree.
 func (p *Parser) ParseDirectory() (*Module, error) {
+	blocks, inputVars, err := p.loadRootBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	dataMocks, err := p.loadDataMocks()
+	if err != nil {
+		return nil, err
+	}
+
+	// load the modules. This downloads any remote modules to the local file system
+	modulesManifest, err := p.moduleLoader.Load(p.initialPath, inputVars)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading Terraform modules: %s", err)
+	}
+
+	return p.evaluate(blocks, inputVars, modulesManifest, dataMocks, p.workspaceName)
+}
+
+// Variant is a workspace and set of var files that the root Module is evaluated
+// with, see Parser.ParseVariants.
+type Variant struct {
+	Name string
+	// Workspace overrides the Terraform workspace of the Parser if set.
+	Workspace string
+	// VarFiles are loaded after the var files of the Parser. Paths are relative
+	// to the Parser initialPath.
+	VarFiles []string
+}
+
+// ParseVariants evaluates the root Module once for each Variant, returning the
+// Modules in the same order as the variants. Unlike calling ParseDirectory for
+// each Variant, the terraform files are only parsed once. The modules are
+// loaded with the input vars of the first Variant, and loaded again for each
+// Variant whose input vars differ from the previous load, as module sources and
+// versions can reference variables.
+func (p *Parser) ParseVariants(variants []Variant) ([]*Module, error) {
 	p.logger.Debugf("Beginning parse for directory '%s'...", p.initialPath)
 
-	// load the initial root directory into a list of hcl files
-	// at this point these files have no schema associated with them.
 	files, err := loadDirectory(p.logger, p.initialPath, p.stopOnHCLError)
 	if err != nil {
 		return nil, err
 	}
 
-	// load the files into given hcl block types. These are then wrapped with *Block structs.
-	blocks, err := p.parseDirectoryFiles(files)
+	dataMocks, err := p.loadDataMocks()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(blocks) == 0 {
-		return nil, errors.New("No valid terraform files found given path, try a different directory")
+	var modulesManifest *modules.Manifest
+	var manifestVars map[string]cty.Value
+	mods := make([]*Module, 0, len(variants))
+	for i := range variants {
+		variant := &variants[i]
+		p.logger.Debugf("Evaluating variant '%s'...", variant.Name)
+
+		// the Evaluator sets the context of the Blocks, so each Variant needs
+		// its own Blocks. These wrap the files that have already been parsed.
+		blocks, err := p.parseRootFiles(files)
+		if err != nil {
+			return nil, err
+		}
+
+		tfvarsPaths := make([]string, 0, len(p.tfvarsPaths)+len(variant.VarFiles))
+		tfvarsPaths = append(tfvarsPaths, p.tfvarsPaths...)
+		for _, name := range variant.VarFiles {
+			tfvarsPaths = append(tfvarsPaths, path.Join(p.initialPath, name))
+		}
+
+		inputVars, err := p.loadVars(blocks, tfvarsPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		// module sources and versions can reference variables, so the modules
+		// are loaded again for a variant with different input vars. Modules
+		// whose source and version haven't changed are reused from the cache.
+		if modulesManifest == nil || !equalInputVars(manifestVars, inputVars) {
+			modulesManifest, err = p.moduleLoader.Load(p.initialPath, inputVars)
+			if err != nil {
+				return nil, fmt.Errorf("Error loading Terraform modules: %s", err)
+			}
+			manifestVars = inputVars
+		}
+
+		workspace := p.workspaceName
+		if variant.Workspace != "" {
+			workspace = variant.Workspace
+		}
+
+		root, err := p.evaluate(blocks, inputVars, modulesManifest, dataMocks, workspace)
+		if err != nil {
+			return nil, err
+		}
+
+		root.Variant = variant
+		mods = append(mods, root)
 	}
 
-	p.logger.Debug("Loading TFVars...")
-	inputVars, err := p.loadVars(blocks, p.tfvarsPaths)
+	return mods, nil
+}
+
+// equalInputVars returns true if both sets of input vars have the same
+// variables with the same values.
+func equalInputVars(a, b map[string]cty.Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for name, v := range a {
+		other, ok := b[name]
+		if !ok || !v.RawEquals(other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LoadModules loads the modules of the root Module without evaluating it. This
+// downloads any remote modules to the local file system and returns the
+// manifest of the loaded modules.
+func (p *Parser) LoadModules() (*modules.Manifest, error) {
+	_, inputVars, err := p.loadRootBlocks()
 	if err != nil {
 		return nil, err
 	}
 
-	// load the modules. This downloads any remote modules to the local file system
-	modulesManifest, err := p.moduleLoader.Load(p.initialPath)
+	manifest, err := p.moduleLoader.Load(p.initialPath, inputVars)
 	if err != nil {
 		return nil, fmt.Errorf("Error loading Terraform modules: %s", err)
 	}
 
+	return manifest, nil
+}
+
+// loadRootBlocks parses the terraform files in the initialPath into Blocks and
+// loads the input vars of the root Module.
+func (p *Parser) loadRootBlocks() (Blocks, map[string]cty.Value, error) {
+	p.logger.Debugf("Beginning parse for directory '%s'...", p.initialPath)
+
+	// load the initial root directory into a list of hcl files
+	// at this point these files have no schema associated with them.
+	files, err := loadDirectory(p.logger, p.initialPath, p.stopOnHCLError)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks, err := p.parseRootFiles(files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.logger.Debug("Loading TFVars...")
+	inputVars, err := p.loadVars(blocks, p.tfvarsPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blocks, inputVars, nil
+}
+
+// parseRootFiles loads the files of the root Module into given hcl block types.
+// These are then wrapped with *Block structs.
+func (p *Parser) parseRootFiles(files []file) (Blocks, error) {
+	blocks, err := p.parseDirectoryFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blocks) == 0 {
+		return nil, errors.New("No valid terraform files found given path, try a different directory")
+	}
+
+	return blocks, nil
+}
+
+func (p *Parser) loadDataMocks() (DataMocks, error) {
+	if p.dataMocksPath == "" {
+		return nil, nil
+	}
+
+	p.logger.Debugf("Loading data mocks from %s...", p.dataMocksPath)
+	return LoadDataMocks(p.dataMocksPath)
+}
+
+// evaluate runs an Evaluator over the Blocks of the root Module in the given
+// workspace and returns the evaluated root Module.
+func (p *Parser) evaluate(blocks Blocks, inputVars map[string]cty.Value, modulesManifest *modules.Manifest, dataMocks DataMocks, workspace string) (*Module, error) {
 	p.logger.Debug("Evaluating expressions...")
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -327,8 +527,11 @@ func (p *Parser) ParseDirectory() (*Module, error) {
 		inputVars,
 		modulesManifest,
 		nil,
-		p.workspaceName,
+		workspace,
 		p.blockBuilder,
+		dataMocks,
+		p.remoteStates,
+		p.diagnostics,
 		p.newSpinner,
 		p.logger,
 	)
@@ -339,6 +542,10 @@ func (p *Parser) ParseDirectory() (*Module, error) {
 	}
 
 	root.HasChanges = p.hasChanges
+	if dataMocks != nil {
+		root.UnmockedDataSources = root.unmockedDataSources(dataMocks, p.remoteStates)
+	}
+
 	return root, nil
 }
 
@@ -377,12 +584,42 @@ func (p *Parser) parseDirectoryFiles(files []file) (Blocks, error) {
 }
 
 func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Value, error) {
-	combinedVars := p.tfEnvVars
-	if combinedVars == nil {
-		combinedVars = make(map[string]cty.Value)
+	// copy the env vars as the vars can be loaded more than once, see ParseVariants.
+	combinedVars := make(map[string]cty.Value, len(p.tfEnvVars))
+	for k, v := range p.tfEnvVars {
+		combinedVars[k] = v
+	}
+
+	localVars := make(map[string]cty.Value)
+	for _, name := range p.defaultVarFiles {
+		err := p.loadAndCombineVars(name, localVars)
+		if err != nil {
+			p.logger.WithError(err).Warnf("could not load vars from auto var file %s", name)
+			continue
+		}
+	}
+
+	for _, filename := range filenames {
+		err := p.loadAndCombineVars(filename, localVars)
+		if err != nil {
+			return combinedVars, err
+		}
+	}
+
+	for k, v := range p.inputVars {
+		localVars[k] = v
 	}
 
 	if p.remoteVariablesLoader != nil {
+		earlyVars := make(map[string]cty.Value, len(combinedVars)+len(localVars))
+		for k, v := range combinedVars {
+			earlyVars[k] = v
+		}
+		for k, v := range localVars {
+			earlyVars[k] = v
+		}
+		p.setEarlyEvalContext(blocks, earlyVars)
+
 		remoteVars, err := p.remoteVariablesLoader.Load(blocks)
 
 		if err != nil {
@@ -395,26 +632,39 @@ func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Val
 		}
 	}
 
-	for _, name := range p.defaultVarFiles {
-		err := p.loadAndCombineVars(name, combinedVars)
-		if err != nil {
-			p.logger.WithError(err).Warnf("could not load vars from auto var file %s", name)
-			continue
-		}
+	for k, v := range localVars {
+		combinedVars[k] = v
 	}
 
-	for _, filename := range filenames {
-		err := p.loadAndCombineVars(filename, combinedVars)
-		if err != nil {
-			return combinedVars, err
+	return combinedVars, nil
+}
+
+// setEarlyEvalContext sets the context of the terraform blocks so that the
+// backend and cloud configuration can reference variables and locals, as
+// OpenTofu allows. These are read before the module is evaluated, so only the
+// values that are known early can be used. The Evaluator replaces the context
+// when it runs.
+func (p *Parser) setEarlyEvalContext(blocks Blocks, inputVars map[string]cty.Value) {
+	variables := make(map[string]hcl.Expression)
+	locals := make(map[string]hcl.Expression)
+	for _, b := range blocks {
+		switch b.Type() {
+		case "variable":
+			variables[b.Label()] = nil
+			if attr := b.GetAttribute("default"); attr != nil {
+				variables[b.Label()] = attr.HCLAttr.Expr
+			}
+		case "locals":
+			for _, attr := range b.GetAttributes() {
+				locals[attr.Name()] = attr.HCLAttr.Expr
+			}
 		}
 	}
 
-	for k, v := range p.inputVars {
-		combinedVars[k] = v
+	ctx := modules.EarlyEvalContext(variables, locals, inputVars)
+	for _, b := range blocks.OfType("terraform") {
+		b.SetContext(NewContext(ctx, nil, p.logger))
 	}
-
-	return combinedVars, nil
 }
 
 func (p *Parser) loadAndCombineVars(filename string, combinedVars map[string]cty.Value) error {
@@ -492,26 +742,15 @@ func loadDirectory(logger *logrus.Entry, fullPath string, stopOnHCLError bool) (
 		return nil, err
 	}
 
-	for _, info := range fileInfos {
-		if info.IsDir() {
-			continue
-		}
-
-		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
-		if strings.HasSuffix(info.Name(), ".tf") {
-			parseFunc = hclParser.ParseHCLFile
-		}
-
-		if strings.HasSuffix(info.Name(), ".tf.json") {
+	// .tofu files take precedence over .tf files of the same name, so only one
+	// of them is returned.
+	for _, name := range modules.ConfigFileNames(fileInfos) {
+		parseFunc := hclParser.ParseHCLFile
+		if modules.IsJSONConfigFile(name) {
 			parseFunc = hclParser.ParseJSONFile
 		}
 
-		// this is not a file we can parse:
-		if parseFunc == nil {
-			continue
-		}
-
-		path := filepath.Join(fullPath, info.Name())
+		path := filepath.Join(fullPath, name)
 		_, diag := parseFunc(path)
 		if diag != nil && diag.HasErrors() {
 			if stopOnHCLError {
@@ -904,3 +904,524 @@ output "mod_result" {
 		`module.test["b"]`,
 	}, modLabels)
 }
+
+func Test_DataMocks(t *testing.T) {
+	path := createTestFileWithModule(`
+data "ibm_is_image" "ubuntu" {
+	name = "ibm-ubuntu-22-04"
+}
+
+data "ibm_is_image" "rhel" {
+	name = "ibm-redhat-8"
+}
+
+data "aws_ami" "web" {
+	count = 2
+	most_recent = true
+}
+
+data "ibm_resource_group" "rg" {
+	name = "default"
+}
+
+locals {
+	group = data.ibm_resource_group.rg.id
+}
+
+resource "ibm_is_instance" "ubuntu" {
+	image_os = data.ibm_is_image.ubuntu.os
+	group    = local.group
+}
+
+resource "ibm_is_instance" "rhel" {
+	image_os = data.ibm_is_image.rhel.os
+}
+
+resource "aws_instance" "web" {
+	ami = data.aws_ami.web[1].name
+}
+
+module "child" {
+	source = "../module"
+}
+`,
+		`
+data "aws_ami" "child" {
+	most_recent = true
+}
+
+resource "aws_instance" "child" {
+	ami = data.aws_ami.child.name
+}
+`,
+		"module",
+	)
+
+	err := os.WriteFile(filepath.Join(path, "mocks.yml"), []byte(`
+ibm_is_image:
+  os: ubuntu-22-04-amd64
+data.ibm_is_image.rhel:
+  os: red-8-amd64
+data.aws_ami.web[1]:
+  name: web-1
+module.child.data.aws_ami.child:
+  name: child
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(filepath.Dir(path), nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(path, loader, nil, logger, OptionStopOnHCLError(), OptionWithDataMocksPath("mocks.yml"))
+	require.NoError(t, err)
+	module, err := parsers[0].ParseDirectory()
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, m := range []*Module{module, module.Modules[0]} {
+		for _, b := range m.Blocks.OfType("resource") {
+			for _, name := range []string{"image_os", "ami"} {
+				if attr := b.GetAttribute(name); attr != nil {
+					values[b.FullName()] = attr.Value().AsString()
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"ibm_is_instance.ubuntu":          "ubuntu-22-04-amd64",
+		"ibm_is_instance.rhel":            "red-8-amd64",
+		"aws_instance.web":                "web-1",
+		"module.child.aws_instance.child": "child",
+	}, values)
+
+	assert.Equal(t, map[string][]string{
+		"ibm_is_instance.ubuntu": {"data.ibm_resource_group.rg"},
+	}, module.UnmockedDataSources)
+}
+
+func Test_DataMocksInvalidFile(t *testing.T) {
+	path := createTestFile("main.tf", `
+data "aws_ami" "web" {
+	most_recent = true
+}
+`)
+
+	err := os.WriteFile(filepath.Join(filepath.Dir(path), "mocks.yml"), []byte(`
+aws_ami: ami-123
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(filepath.Dir(path), nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(filepath.Dir(path), loader, nil, logger, OptionWithDataMocksPath("mocks.yml"))
+	require.NoError(t, err)
+	_, err = parsers[0].ParseDirectory()
+	assert.ErrorContains(t, err, "expected a map of attribute values for aws_ami")
+}
+
+func Test_OpenTofuFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+output "source" {
+	value = "main.tf"
+}
+`,
+		"main.tofu": `
+terraform {
+	encryption {
+		key_provider "pbkdf2" "default" {
+			passphrase = var.passphrase
+		}
+
+		method "aes_gcm" "default" {
+			keys = key_provider.pbkdf2.default
+		}
+
+		state {
+			method = method.aes_gcm.default
+		}
+	}
+}
+
+variable "passphrase" {
+	default = "a-long-enough-passphrase"
+}
+
+output "source" {
+	value = "main.tofu"
+}
+`,
+		"outputs.tf.json":   `{"output": {"json": {"value": "outputs.tf.json"}}}`,
+		"outputs.tofu.json": `{"output": {"json": {"value": "outputs.tofu.json"}}}`,
+		"other.tf": `
+output "other" {
+	value = "other.tf"
+}
+`,
+	}
+	for name, contents := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.ModePerm) //nolint:gosec
+		require.NoError(t, err)
+	}
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(dir, nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(dir, loader, nil, logger, OptionStopOnHCLError())
+	require.NoError(t, err)
+	module, err := parsers[0].ParseDirectory()
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, b := range module.Blocks.OfType("output") {
+		values[b.Label()] = b.GetAttribute("value").Value().AsString()
+	}
+
+	assert.Equal(t, map[string]string{
+		"source": "main.tofu",
+		"json":   "outputs.tofu.json",
+		"other":  "other.tf",
+	}, values)
+	assert.Len(t, module.Blocks.OfType("terraform"), 1)
+}
+
+func Test_EarlyEvaluatedModuleSource(t *testing.T) {
+	path := createTestFileWithModule(`
+variable "module_dir" {
+	default = "../module"
+}
+
+locals {
+	source = var.module_dir
+}
+
+module "my-mod" {
+	source = local.source
+	child_source = "./child"
+}
+`,
+		`
+variable "child_source" {}
+
+module "child" {
+	source = var.child_source
+}
+`,
+		"module",
+	)
+
+	childDir := filepath.Join(filepath.Dir(path), "module", "child")
+	require.NoError(t, os.Mkdir(childDir, 0755))
+	err := os.WriteFile(filepath.Join(childDir, "main.tofu"), []byte(`
+output "result" {
+	value = "child"
+}
+`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(filepath.Dir(path), nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(path, loader, nil, logger, OptionStopOnHCLError())
+	require.NoError(t, err)
+	module, err := parsers[0].ParseDirectory()
+	require.NoError(t, err)
+
+	require.Len(t, module.Modules, 1)
+	require.Len(t, module.Modules[0].Modules, 1)
+	outputs := module.Modules[0].Modules[0].Blocks.OfType("output")
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "child", outputs[0].GetAttribute("value").Value().AsString())
+}
+
+func Test_EarlyEvaluatedBackend(t *testing.T) {
+	path := createTestFile("main.tf", `
+terraform {
+	backend "remote" {
+		organization = var.organization
+
+		workspaces {
+			name = local.workspace
+		}
+	}
+}
+
+variable "organization" {}
+
+variable "env" {
+	default = "dev"
+}
+
+locals {
+	workspace = "app-${var.env}"
+}
+`)
+
+	logger := newDiscardLogger()
+	p := newParser(RootPath{Path: filepath.Dir(path)}, nil, logger, OptionWithInputVars(map[string]string{"organization": "infracost"}))
+	files, err := loadDirectory(logger, filepath.Dir(path), true)
+	require.NoError(t, err)
+	blocks, err := p.parseDirectoryFiles(files)
+	require.NoError(t, err)
+
+	p.setEarlyEvalContext(blocks, p.inputVars)
+	conf, err := (&RemoteVariablesLoader{}).getBackendOrganizationWorkspace(blocks)
+	require.NoError(t, err)
+	assert.Equal(t, "infracost", conf.organization)
+	assert.Equal(t, "app-dev", conf.workspace)
+}
+
+func Test_Diagnostics(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+variable "instance_type" {}
+
+variable "region" {
+	default = "us-east-1"
+}
+
+locals {
+	instance_type = var.instance_type
+	zones         = { for z in ["a", "b"] : z => "${var.region}${z}" }
+}
+
+resource "aws_instance" "missing_var" {
+	ami           = "ami-123"
+	instance_type = local.instance_type
+}
+
+resource "aws_instance" "function" {
+	ami           = "ami-123"
+	instance_type = element([], 0)
+}
+
+resource "aws_instance" "unresolved" {
+	ami           = "ami-123"
+	instance_type = local.does_not_exist
+}
+
+resource "aws_instance" "unknown_count" {
+	count         = provider::aws::arn_parse("arn") == null ? 1 : 2
+	ami           = "ami-123"
+	instance_type = "t3.micro"
+}
+
+resource "aws_instance" "unknown_for_each" {
+	for_each      = provider::aws::arn_parse("arn")
+	ami           = "ami-123"
+	instance_type = "t3.micro"
+}
+
+resource "aws_instance" "unknown_value" {
+	ami           = "ami-123"
+	instance_type = "t3.${provider::aws::arn_parse("arn").region}"
+	subnet_id     = aws_instance.missing_var.subnet_id
+}
+
+resource "aws_instance" "ok" {
+	for_each          = local.zones
+	ami               = "ami-123"
+	instance_type     = "t3.micro"
+	availability_zone = each.value
+}
+
+module "child" {
+	source = "./child"
+}
+
+resource "aws_instance" "module_output" {
+	ami           = module.child.ami
+	instance_type = module.child.missing
+}
+`,
+		"child/main.tf": `
+variable "size" {}
+
+output "ami" {
+	value = "ami-123"
+}
+
+resource "aws_ebs_volume" "data" {
+	availability_zone = "us-east-1a"
+	size              = var.size
+}
+`,
+	}
+	for name, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm))
+		err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.ModePerm) //nolint:gosec
+		require.NoError(t, err)
+	}
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(dir, nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(dir, loader, nil, logger, OptionWithDiagnostics())
+	require.NoError(t, err)
+	module, err := parsers[0].ParseDirectory()
+	require.NoError(t, err)
+
+	type result struct {
+		Kind      DiagnosticKind
+		Address   string
+		Line      int
+		Resources []string
+	}
+
+	var actual []result
+	for _, d := range module.AllDiagnostics() {
+		assert.Equal(t, "main.tf", filepath.Base(d.Range.Filename))
+		assert.NotEmpty(t, d.Message)
+		actual = append(actual, result{d.Kind, d.Address, d.Range.Start.Line, d.Resources})
+	}
+
+	assert.Equal(t, []result{
+		{DiagnosticFailedFunctionCall, "aws_instance.function.instance_type", 20, []string{"aws_instance.function"}},
+		{DiagnosticUnresolvedReference, "aws_instance.module_output.instance_type", 59, []string{"aws_instance.module_output"}},
+		{DiagnosticUnexpandedCount, "aws_instance.unknown_count.count", 29, []string{"aws_instance.unknown_count[0]"}},
+		{DiagnosticUnexpandedForEach, "aws_instance.unknown_for_each.for_each", 35, nil},
+		{DiagnosticUnknownValue, "aws_instance.unknown_value.instance_type", 42, []string{"aws_instance.unknown_value"}},
+		{DiagnosticUnresolvedReference, "aws_instance.unresolved.instance_type", 25, []string{"aws_instance.unresolved"}},
+		{DiagnosticMissingVar, "module.child.var.size", 2, []string{"module.child.aws_ebs_volume.data"}},
+		{DiagnosticMissingVar, "var.instance_type", 2, []string{"aws_instance.missing_var"}},
+	}, actual)
+
+	parsers, err = LoadParsers(dir, loader, nil, logger)
+	require.NoError(t, err)
+	module, err = parsers[0].ParseDirectory()
+	require.NoError(t, err)
+	assert.Empty(t, module.AllDiagnostics())
+}
+
+func Test_ParseVariants(t *testing.T) {
+	path := createTestFileWithModule(`
+variable "instance_type" {
+	default = "t3.micro"
+}
+
+variable "region" {}
+
+module "my-mod" {
+	source = "../module"
+	size   = terraform.workspace == "prod" ? 100 : 10
+}
+
+resource "aws_instance" "web" {
+	ami           = "ami-123"
+	instance_type = var.instance_type
+	tags = {
+		Region    = var.region
+		Workspace = terraform.workspace
+	}
+}
+`,
+		`
+variable "size" {}
+
+output "size" {
+	value = var.size
+}
+`,
+		"module",
+	)
+
+	require.NoError(t, os.WriteFile(filepath.Join(path, "common.tfvars"), []byte(`region = "us-east-1"`), os.ModePerm))     //nolint:gosec
+	require.NoError(t, os.WriteFile(filepath.Join(path, "prod.tfvars"), []byte(`instance_type = "m5.large"`), os.ModePerm)) //nolint:gosec
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(filepath.Dir(path), nil, logger, &sync.KeyMutex{})
+	parsers, err := LoadParsers(path, loader, nil, logger, OptionStopOnHCLError(), OptionWithTFVarsPaths([]string{"common.tfvars"}))
+	require.NoError(t, err)
+
+	mods, err := parsers[0].ParseVariants([]Variant{
+		{Name: "default"},
+		{Name: "prod", Workspace: "prod", VarFiles: []string{"prod.tfvars"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, mods, 2)
+
+	tests := []struct {
+		variant      string
+		instanceType string
+		workspace    string
+		size         int64
+	}{
+		{"default", "t3.micro", "default", 10},
+		{"prod", "m5.large", "prod", 100},
+	}
+
+	for i, tt := range tests {
+		mod := mods[i]
+		assert.Equal(t, tt.variant, mod.Variant.Name)
+
+		resources := mod.Blocks.OfType("resource")
+		require.Len(t, resources, 1)
+		assert.Equal(t, tt.instanceType, resources[0].GetAttribute("instance_type").Value().AsString())
+
+		tags := resources[0].GetAttribute("tags").Value().AsValueMap()
+		assert.Equal(t, "us-east-1", tags["Region"].AsString())
+		assert.Equal(t, tt.workspace, tags["Workspace"].AsString())
+
+		require.Len(t, mod.Modules, 1)
+		outputs := mod.Modules[0].Blocks.OfType("output")
+		require.Len(t, outputs, 1)
+		size, _ := outputs[0].GetAttribute("value").Value().AsBigFloat().Int64()
+		assert.Equal(t, tt.size, size)
+	}
+}
+
+func Test_ParseVariantsWithVarDependentModuleVersion(t *testing.T) {
+	mirrorDir := t.TempDir()
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		dir := filepath.Join(mirrorDir, "registry", "app", version)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+output "version" {
+	value = "`+version+`"
+}
+`), os.ModePerm) //nolint:gosec
+		require.NoError(t, err)
+	}
+	err := os.WriteFile(filepath.Join(mirrorDir, "index.json"), []byte(`{
+  "modules": [
+    {"source": "registry.terraform.io/infracost/app/aws", "version": "1.0.0", "path": "registry/app/1.0.0"},
+    {"source": "registry.terraform.io/infracost/app/aws", "version": "2.0.0", "path": "registry/app/2.0.0"}
+  ]
+}`), os.ModePerm) //nolint:gosec
+	require.NoError(t, err)
+
+	mirror, err := modules.LoadMirror(mirrorDir)
+	require.NoError(t, err)
+
+	path := filepath.Dir(createTestFile("main.tf", `
+variable "app_version" {
+	default = "1.0.0"
+}
+
+module "app" {
+	source  = "infracost/app/aws"
+	version = var.app_version
+}
+`))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "next.tfvars"), []byte(`app_version = "2.0.0"`), os.ModePerm)) //nolint:gosec
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(path, nil, logger, &sync.KeyMutex{})
+	loader.SetMirror(mirror)
+	parsers, err := LoadParsers(path, loader, nil, logger, OptionStopOnHCLError())
+	require.NoError(t, err)
+
+	mods, err := parsers[0].ParseVariants([]Variant{
+		{Name: "current"},
+		{Name: "next", VarFiles: []string{"next.tfvars"}},
+		{Name: "current-again"},
+	})
+	require.NoError(t, err)
+	require.Len(t, mods, 3)
+
+	for i, version := range []string{"1.0.0", "2.0.0", "1.0.0"} {
+		require.Len(t, mods[i].Modules, 1)
+		outputs := mods[i].Modules[0].Blocks.OfType("output")
+		require.Len(t, outputs, 1)
+		assert.Equal(t, version, outputs[0].GetAttribute("value").Value().AsString(), mods[i].Variant.Name)
+	}
+}
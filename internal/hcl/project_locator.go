@@ -5,11 +5,12 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
 )
 
 // ProjectLocator finds Terraform projects for given paths.
@@ -187,25 +188,13 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int) []string {
 	}
 
 	var dirs []string
-	for _, info := range fileInfos {
-		if info.IsDir() {
-			continue
-		}
-
-		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
-		if strings.HasSuffix(info.Name(), ".tf") {
-			parseFunc = hclParser.ParseHCLFile
-		}
-
-		if strings.HasSuffix(info.Name(), ".tf.json") {
+	for _, name := range modules.ConfigFileNames(fileInfos) {
+		parseFunc := hclParser.ParseHCLFile
+		if modules.IsJSONConfigFile(name) {
 			parseFunc = hclParser.ParseJSONFile
 		}
 
-		if parseFunc == nil {
-			continue
-		}
-
-		path := filepath.Join(fullPath, info.Name())
+		path := filepath.Join(fullPath, name)
 		_, diag := parseFunc(path)
 		if diag != nil && diag.HasErrors() {
 			p.logger.Warnf("skipping file: %s hcl parsing err: %s", path, diag.Error())
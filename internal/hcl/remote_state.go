@@ -0,0 +1,99 @@
+package hcl
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// RemoteState holds the outputs to use for terraform_remote_state data sources,
+// which would otherwise need the state to be read from the backend. Data sources
+// are matched by address, using the same keys as DataMocks, by backend type and
+// config, or by both if both are set.
+type RemoteState struct {
+	// Address is the address of the data sources, e.g.
+	// data.terraform_remote_state.network.
+	Address string
+	// Backend is the backend type of the data sources, e.g. s3.
+	Backend string
+	// Config are the backend config values that the data sources must have, e.g.
+	// key: network/terraform.tfstate. Other config values aren't checked.
+	Config map[string]string
+	// Outputs is an object of the output values of the state.
+	Outputs cty.Value
+}
+
+// RemoteStates is a list of RemoteState, where the first match for a data
+// source is used.
+type RemoteStates []RemoteState
+
+// Outputs returns the outputs for the terraform_remote_state data Block. It
+// returns false if no RemoteState matches the Block.
+func (r RemoteStates) Outputs(b *Block) (cty.Value, bool) {
+	if len(r) == 0 || b.Type() != "data" || b.TypeLabel() != "terraform_remote_state" {
+		return cty.NilVal, false
+	}
+
+	for _, state := range r {
+		if state.matches(b) {
+			return state.Outputs, true
+		}
+	}
+
+	return cty.NilVal, false
+}
+
+func (s RemoteState) matches(b *Block) bool {
+	if s.Address == "" && s.Backend == "" {
+		return false
+	}
+
+	if s.Address != "" && !s.matchesAddress(b) {
+		return false
+	}
+
+	if s.Backend != "" && !s.matchesBackend(b) {
+		return false
+	}
+
+	return true
+}
+
+func (s RemoteState) matchesAddress(b *Block) bool {
+	for _, k := range dataSourceKeys(b) {
+		if k == s.Address {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s RemoteState) matchesBackend(b *Block) bool {
+	if b.GetAttribute("backend").AsString() != s.Backend {
+		return false
+	}
+
+	if len(s.Config) == 0 {
+		return true
+	}
+
+	config := b.GetAttribute("config").Value()
+	if config.IsNull() || !config.IsKnown() || !config.CanIterateElements() {
+		return false
+	}
+
+	values := config.AsValueMap()
+	for k, want := range s.Config {
+		v, ok := values[k]
+		if !ok || v.IsNull() || !v.IsKnown() {
+			return false
+		}
+
+		v, err := convert.Convert(v, cty.String)
+		if err != nil || v.AsString() != want {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,942 @@
+package hcl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+)
+
+const (
+	// DefaultTerragruntConfigName is the name of the Terragrunt config file in each unit.
+	DefaultTerragruntConfigName = "terragrunt.hcl"
+
+	maxTerragruntDepth = 10
+
+	mergeStrategyNoMerge = "no_merge"
+	mergeStrategyShallow = "shallow"
+	mergeStrategyDeep    = "deep"
+)
+
+var terragruntSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "inputs"},
+		{Name: "skip"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "include"},
+		{Type: "locals"},
+		{Type: "dependency", LabelNames: []string{"name"}},
+		{Type: "terraform"},
+	},
+}
+
+var terragruntTerraformSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "source"},
+	},
+}
+
+// TerragruntConfig is the evaluated config of a Terragrunt unit, i.e. a
+// directory with a terragrunt.hcl file.
+type TerragruntConfig struct {
+	// Path is the path to the terragrunt.hcl file of the unit.
+	Path string
+	// Dir is the directory of the unit.
+	Dir string
+	// Source is the Terraform source of the unit. Local sources are resolved
+	// relative to the unit directory. Source is empty if the config doesn't
+	// set a source, in which case the Terraform files are in the unit directory.
+	Source string
+	// Inputs are the values of the Terraform input variables of the unit.
+	Inputs map[string]cty.Value
+	// Skip is true if the unit sets skip = true.
+	Skip bool
+	// Includes are the paths of the config files included by the unit.
+	Includes []string
+}
+
+// terragruntFile is the evaluated content of a single Terragrunt config
+// file, merged with any files it includes.
+type terragruntFile struct {
+	locals       map[string]cty.Value
+	inputs       map[string]cty.Value
+	dependencies map[string]cty.Value
+	source       *string
+	skip         *bool
+}
+
+// terragruntEvaluator evaluates the Terragrunt config of a unit. Terragrunt
+// evaluates the functions in included files in the context of the unit, so
+// the evaluator keeps track of the unit directory and the included files.
+type terragruntEvaluator struct {
+	logger      *logrus.Entry
+	dir         string
+	originalDir string
+	includes    map[string]string
+	depth       int
+}
+
+// LoadTerragruntConfig parses the Terragrunt config file at path and evaluates
+// its include, locals, dependency, terraform and inputs blocks, without
+// running Terragrunt. Dependency outputs are set from their mock_outputs,
+// since they would otherwise require the state of the dependency.
+func LoadTerragruntConfig(path string, logger *logrus.Entry) (*TerragruntConfig, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e := newTerragruntEvaluator(filepath.Dir(absPath), filepath.Dir(absPath), logger, 0)
+
+	f, err := e.evaluateFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &TerragruntConfig{
+		Path:   path,
+		Dir:    filepath.Dir(path),
+		Inputs: knownInputs(f.inputs),
+	}
+
+	if f.source != nil {
+		cfg.Source = resolveTerragruntSource(cfg.Dir, *f.source)
+	}
+
+	if f.skip != nil {
+		cfg.Skip = *f.skip
+	}
+
+	for _, p := range e.includes {
+		cfg.Includes = append(cfg.Includes, p)
+	}
+	sort.Strings(cfg.Includes)
+
+	return cfg, nil
+}
+
+func newTerragruntEvaluator(dir string, originalDir string, logger *logrus.Entry, depth int) *terragruntEvaluator {
+	return &terragruntEvaluator{
+		logger:      logger,
+		dir:         dir,
+		originalDir: originalDir,
+		includes:    map[string]string{},
+		depth:       depth,
+	}
+}
+
+// evaluateFile evaluates the Terragrunt config file at path. The blocks are
+// evaluated in the same order as Terragrunt, so that each block can
+// reference the blocks before it: include, locals, dependency, terraform
+// and then inputs.
+func (e *terragruntEvaluator) evaluateFile(path string) (*terragruntFile, error) {
+	if e.depth > maxTerragruntDepth {
+		return nil, fmt.Errorf("Terragrunt config %s exceeds the maximum include depth of %d", path, maxTerragruntDepth)
+	}
+
+	content, err := parseTerragruntFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &terragruntFile{
+		locals:       map[string]cty.Value{},
+		inputs:       map[string]cty.Value{},
+		dependencies: map[string]cty.Value{},
+	}
+
+	vars := map[string]cty.Value{}
+	exposed := map[string]cty.Value{}
+	deepMergeInputs := false
+
+	for _, block := range content.Blocks.OfType("include") {
+		name := ""
+		if len(block.Labels) > 0 {
+			name = block.Labels[0]
+		}
+
+		parent, expose, err := e.evaluateInclude(path, name, block)
+		if err != nil {
+			return nil, err
+		}
+
+		if expose && name != "" {
+			exposed[name] = cty.ObjectVal(map[string]cty.Value{
+				"locals": cty.ObjectVal(parent.file.locals),
+				"inputs": cty.ObjectVal(parent.file.inputs),
+			})
+		}
+
+		f.merge(parent.file, parent.mergeStrategy)
+		if parent.mergeStrategy == mergeStrategyDeep {
+			deepMergeInputs = true
+		}
+	}
+
+	vars["include"] = cty.ObjectVal(exposed)
+
+	for _, block := range content.Blocks.OfType("locals") {
+		attrs, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("Error parsing locals in %s: %s", path, diags.Error())
+		}
+
+		// Locals aren't inherited from included files, they have to be exposed
+		f.locals = e.evaluateLocals(attrs, vars)
+	}
+	vars["local"] = cty.ObjectVal(f.locals)
+
+	for _, block := range content.Blocks.OfType("dependency") {
+		f.dependencies[block.Labels[0]] = e.evaluateDependency(path, block, vars)
+	}
+	vars["dependency"] = cty.ObjectVal(f.dependencies)
+
+	for _, block := range content.Blocks.OfType("terraform") {
+		tfContent, _, diags := block.Body.PartialContent(terragruntTerraformSchema)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("Error parsing terraform block in %s: %s", path, diags.Error())
+		}
+
+		if attr, ok := tfContent.Attributes["source"]; ok {
+			v, diags := attr.Expr.Value(e.evalContext(vars))
+			if diags.HasErrors() || !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+				return nil, fmt.Errorf("Error evaluating terraform source in %s: %s", path, diags.Error())
+			}
+
+			source := v.AsString()
+			f.source = &source
+		}
+	}
+
+	if attr, ok := content.Attributes["skip"]; ok {
+		v, diags := attr.Expr.Value(e.evalContext(vars))
+		if !diags.HasErrors() && v.IsKnown() && !v.IsNull() && v.Type() == cty.Bool {
+			skip := v.True()
+			f.skip = &skip
+		}
+	}
+
+	if attr, ok := content.Attributes["inputs"]; ok {
+		for k, v := range e.evaluateInputs(path, attr, vars) {
+			if existing, ok := f.inputs[k]; ok && deepMergeInputs {
+				v = deepMergeValues(existing, v)
+			}
+			f.inputs[k] = v
+		}
+	}
+
+	return f, nil
+}
+
+type terragruntInclude struct {
+	file          *terragruntFile
+	mergeStrategy string
+}
+
+// evaluateInclude evaluates an include block and the config file it includes.
+func (e *terragruntEvaluator) evaluateInclude(path string, name string, block *hcl.Block) (*terragruntInclude, bool, error) {
+	attrs, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("Error parsing include in %s: %s", path, diags.Error())
+	}
+
+	pathAttr, ok := attrs["path"]
+	if !ok {
+		return nil, false, fmt.Errorf("include in %s is missing the path attribute", path)
+	}
+
+	// The include path can only use functions since nothing else has been evaluated yet
+	ctx := e.evalContext(nil)
+
+	v, diags := pathAttr.Expr.Value(ctx)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+		return nil, false, fmt.Errorf("Error evaluating include path in %s: %s", path, diags.Error())
+	}
+
+	includePath := v.AsString()
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(filepath.Dir(path), includePath)
+	}
+
+	var expose bool
+	if attr, ok := attrs["expose"]; ok {
+		v, diags := attr.Expr.Value(ctx)
+		if !diags.HasErrors() && v.Type() == cty.Bool && v.IsKnown() && !v.IsNull() {
+			expose = v.True()
+		}
+	}
+
+	mergeStrategy := mergeStrategyShallow
+	if attr, ok := attrs["merge_strategy"]; ok {
+		v, diags := attr.Expr.Value(ctx)
+		if !diags.HasErrors() && v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+			mergeStrategy = v.AsString()
+		}
+	}
+
+	e.includes[name] = includePath
+
+	e.depth++
+	defer func() { e.depth-- }()
+
+	f, err := e.evaluateFile(includePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("Error evaluating %s included from %s: %w", includePath, path, err)
+	}
+
+	return &terragruntInclude{file: f, mergeStrategy: mergeStrategy}, expose, nil
+}
+
+// evaluateLocals evaluates the locals, which can reference each other, by
+// repeatedly evaluating the locals that haven't been evaluated yet until no
+// more can be evaluated.
+func (e *terragruntEvaluator) evaluateLocals(attrs hcl.Attributes, vars map[string]cty.Value) map[string]cty.Value {
+	locals := map[string]cty.Value{}
+
+	pending := make(map[string]*hcl.Attribute, len(attrs))
+	for k, v := range attrs {
+		pending[k] = v
+	}
+
+	for len(pending) > 0 {
+		progress := false
+
+		for _, name := range sortedAttributeNames(pending) {
+			localVars := copyVars(vars)
+			localVars["local"] = cty.ObjectVal(locals)
+
+			v, diags := pending[name].Expr.Value(e.evalContext(localVars))
+			if diags.HasErrors() {
+				continue
+			}
+
+			locals[name] = v
+			delete(pending, name)
+			progress = true
+		}
+
+		if !progress {
+			break
+		}
+	}
+
+	for _, name := range sortedAttributeNames(pending) {
+		localVars := copyVars(vars)
+		localVars["local"] = cty.ObjectVal(locals)
+
+		_, diags := pending[name].Expr.Value(e.evalContext(localVars))
+		e.logger.Debugf("could not evaluate Terragrunt local %s: %s", name, diags.Error())
+		locals[name] = cty.DynamicVal
+	}
+
+	return locals
+}
+
+// evaluateDependency returns the value of a dependency block. The outputs
+// of the dependency are set from its mock_outputs, or are unknown if it
+// doesn't have any.
+func (e *terragruntEvaluator) evaluateDependency(path string, block *hcl.Block, vars map[string]cty.Value) cty.Value {
+	name := block.Labels[0]
+
+	attrs, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		e.logger.Debugf("could not parse Terragrunt dependency %s in %s: %s", name, path, diags.Error())
+		return cty.ObjectVal(map[string]cty.Value{"outputs": cty.DynamicVal})
+	}
+
+	ctx := e.evalContext(vars)
+
+	configPath := cty.StringVal("")
+	if attr, ok := attrs["config_path"]; ok {
+		v, diags := attr.Expr.Value(ctx)
+		if !diags.HasErrors() && v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+			configPath = v
+		}
+	}
+
+	outputs := cty.DynamicVal
+	if attr, ok := attrs["mock_outputs"]; ok {
+		v, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			e.logger.Debugf("could not evaluate mock_outputs for Terragrunt dependency %s in %s: %s", name, path, diags.Error())
+		} else {
+			outputs = v
+		}
+	} else {
+		e.logger.Debugf("Terragrunt dependency %s in %s has no mock_outputs, so its outputs are unknown", name, path)
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"config_path": configPath,
+		"outputs":     outputs,
+	})
+}
+
+// evaluateInputs evaluates the inputs attribute. If the inputs are an object
+// each input is evaluated separately, so that one input that can't be
+// evaluated doesn't stop the others from being used.
+func (e *terragruntEvaluator) evaluateInputs(path string, attr *hcl.Attribute, vars map[string]cty.Value) map[string]cty.Value {
+	ctx := e.evalContext(vars)
+	inputs := map[string]cty.Value{}
+
+	if obj, ok := attr.Expr.(*hclsyntax.ObjectConsExpr); ok {
+		for _, item := range obj.Items {
+			k, diags := item.KeyExpr.Value(ctx)
+			if diags.HasErrors() || !k.IsKnown() || k.IsNull() || k.Type() != cty.String {
+				e.logger.Debugf("could not evaluate Terragrunt input key in %s: %s", path, diags.Error())
+				continue
+			}
+
+			v, diags := item.ValueExpr.Value(ctx)
+			if diags.HasErrors() {
+				e.logger.Debugf("could not evaluate Terragrunt input %s in %s: %s", k.AsString(), path, diags.Error())
+				continue
+			}
+
+			inputs[k.AsString()] = v
+		}
+
+		return inputs
+	}
+
+	v, diags := attr.Expr.Value(ctx)
+	if diags.HasErrors() {
+		e.logger.Debugf("could not evaluate Terragrunt inputs in %s: %s", path, diags.Error())
+		return inputs
+	}
+
+	if v.IsKnown() && !v.IsNull() && (v.Type().IsObjectType() || v.Type().IsMapType()) {
+		for k, val := range v.AsValueMap() {
+			inputs[k] = val
+		}
+	}
+
+	return inputs
+}
+
+// merge merges an included file into f, using the include merge strategy.
+// The included files are merged before the blocks of f are evaluated, so
+// the values of f are set afterwards and take precedence.
+func (f *terragruntFile) merge(parent *terragruntFile, strategy string) {
+	if strategy == mergeStrategyNoMerge {
+		return
+	}
+
+	if parent.source != nil && f.source == nil {
+		f.source = parent.source
+	}
+
+	if parent.skip != nil && f.skip == nil {
+		f.skip = parent.skip
+	}
+
+	for k, v := range parent.dependencies {
+		if _, ok := f.dependencies[k]; !ok {
+			f.dependencies[k] = v
+		}
+	}
+
+	for k, v := range parent.inputs {
+		if existing, ok := f.inputs[k]; ok && strategy == mergeStrategyDeep {
+			v = deepMergeValues(existing, v)
+		}
+		f.inputs[k] = v
+	}
+}
+
+// deepMergeValues merges override into base. Objects and maps are merged
+// recursively and lists are concatenated, like the Terragrunt deep merge.
+func deepMergeValues(base cty.Value, override cty.Value) cty.Value {
+	if !base.IsWhollyKnown() || !override.IsWhollyKnown() || base.IsNull() || override.IsNull() {
+		return override
+	}
+
+	baseType, overrideType := base.Type(), override.Type()
+
+	if (baseType.IsObjectType() || baseType.IsMapType()) && (overrideType.IsObjectType() || overrideType.IsMapType()) {
+		merged := base.AsValueMap()
+		if merged == nil {
+			merged = map[string]cty.Value{}
+		}
+
+		for k, v := range override.AsValueMap() {
+			if existing, ok := merged[k]; ok {
+				merged[k] = deepMergeValues(existing, v)
+				continue
+			}
+			merged[k] = v
+		}
+
+		return cty.ObjectVal(merged)
+	}
+
+	if (baseType.IsListType() || baseType.IsTupleType()) && (overrideType.IsListType() || overrideType.IsTupleType()) {
+		vals := append(base.AsValueSlice(), override.AsValueSlice()...)
+		if len(vals) == 0 {
+			return override
+		}
+		return cty.TupleVal(vals)
+	}
+
+	return override
+}
+
+func (e *terragruntEvaluator) evalContext(vars map[string]cty.Value) *hcl.EvalContext {
+	functions := expFunctions(e.dir, e.logger)
+	for k, v := range e.functions() {
+		functions[k] = v
+	}
+
+	return &hcl.EvalContext{
+		Variables: vars,
+		Functions: functions,
+	}
+}
+
+// functions returns the Terragrunt built-in functions. Functions that would
+// need to run commands or call cloud APIs return placeholder values.
+func (e *terragruntEvaluator) functions() map[string]function.Function {
+	stringFunc := func(f func() string) function.Function {
+		return function.New(&function.Spec{
+			Type: function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				return cty.StringVal(f()), nil
+			},
+		})
+	}
+
+	listFunc := func(vals ...string) function.Function {
+		return function.New(&function.Spec{
+			Type: function.StaticReturnType(cty.List(cty.String)),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				l := make([]cty.Value, len(vals))
+				for i, v := range vals {
+					l[i] = cty.StringVal(v)
+				}
+				return cty.ListVal(l), nil
+			},
+		})
+	}
+
+	optionalNameFunc := func(f func(name string) string) function.Function {
+		return function.New(&function.Spec{
+			VarParam: &function.Parameter{Name: "name", Type: cty.String},
+			Type:     function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				name := ""
+				if len(args) > 0 {
+					name = args[0].AsString()
+				}
+				return cty.StringVal(f(name)), nil
+			},
+		})
+	}
+
+	placeholder := func(name string, value string) function.Function {
+		return function.New(&function.Spec{
+			VarParam: &function.Parameter{Name: "args", Type: cty.DynamicPseudoType},
+			Type:     function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				e.logger.Debugf("Terragrunt function %s is not supported, using %q", name, value)
+				return cty.StringVal(value), nil
+			},
+		})
+	}
+
+	return map[string]function.Function{
+		"find_in_parent_folders":      e.findInParentFoldersFunc(),
+		"path_relative_to_include":    optionalNameFunc(e.pathRelativeToInclude),
+		"path_relative_from_include":  optionalNameFunc(e.pathRelativeFromInclude),
+		"get_parent_terragrunt_dir":   optionalNameFunc(e.parentTerragruntDir),
+		"get_terragrunt_dir":          stringFunc(func() string { return e.dir }),
+		"get_original_terragrunt_dir": stringFunc(func() string { return e.originalDir }),
+		"get_working_dir":             stringFunc(func() string { return e.dir }),
+		"get_repo_root":               stringFunc(e.repoRoot),
+		"get_path_from_repo_root": stringFunc(func() string {
+			rel, _ := filepath.Rel(e.repoRoot(), e.dir)
+			return filepath.ToSlash(rel)
+		}),
+		"get_path_to_repo_root": stringFunc(func() string {
+			rel, _ := filepath.Rel(e.dir, e.repoRoot())
+			return filepath.ToSlash(rel)
+		}),
+		"get_platform":          stringFunc(func() string { return runtime.GOOS }),
+		"get_terraform_command": stringFunc(func() string { return "plan" }),
+		"get_terraform_cli_args": function.New(&function.Spec{
+			Type: function.StaticReturnType(cty.List(cty.String)),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				return cty.ListValEmpty(cty.String), nil
+			},
+		}),
+		"get_terraform_commands_that_need_vars":        listFunc("apply", "console", "destroy", "import", "plan", "push", "refresh"),
+		"get_terraform_commands_that_need_input":       listFunc("apply", "import", "init", "plan", "refresh"),
+		"get_terraform_commands_that_need_locking":     listFunc("apply", "destroy", "import", "init", "plan", "refresh", "taint", "untaint"),
+		"get_terraform_commands_that_need_parallelism": listFunc("apply", "plan", "destroy"),
+		"get_env":                         e.getEnvFunc(),
+		"get_aws_account_id":              placeholder("get_aws_account_id", "123456789012"),
+		"get_aws_caller_identity_arn":     placeholder("get_aws_caller_identity_arn", "arn:aws:iam::123456789012:user/infracost"),
+		"get_aws_caller_identity_user_id": placeholder("get_aws_caller_identity_user_id", "AIDAINFRACOST"),
+		"run_cmd":                         placeholder("run_cmd", ""),
+		"sops_decrypt_file":               placeholder("sops_decrypt_file", "{}"),
+		"mark_as_read": function.New(&function.Spec{
+			Params: []function.Parameter{{Name: "path", Type: cty.String}},
+			Type:   function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				return args[0], nil
+			},
+		}),
+		"read_terragrunt_config": e.readTerragruntConfigFunc(),
+		"read_tfvars_file":       e.readTfvarsFileFunc(),
+	}
+}
+
+func (e *terragruntEvaluator) findInParentFoldersFunc() function.Function {
+	return function.New(&function.Spec{
+		VarParam: &function.Parameter{Name: "args", Type: cty.String},
+		Type:     function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			name := DefaultTerragruntConfigName
+			if len(args) > 0 {
+				name = args[0].AsString()
+			}
+
+			dir := filepath.Dir(e.dir)
+			for {
+				candidate := filepath.Join(dir, name)
+				if _, err := os.Stat(candidate); err == nil {
+					return cty.StringVal(candidate), nil
+				}
+
+				parent := filepath.Dir(dir)
+				if parent == dir {
+					break
+				}
+				dir = parent
+			}
+
+			if len(args) > 1 {
+				return args[1], nil
+			}
+
+			return cty.NilVal, fmt.Errorf("could not find %s in any of the parent folders of %s", name, e.dir)
+		},
+	})
+}
+
+func (e *terragruntEvaluator) getEnvFunc() function.Function {
+	return function.New(&function.Spec{
+		Params:   []function.Parameter{{Name: "name", Type: cty.String}},
+		VarParam: &function.Parameter{Name: "default", Type: cty.String},
+		Type:     function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			if v, ok := os.LookupEnv(args[0].AsString()); ok {
+				return cty.StringVal(v), nil
+			}
+
+			if len(args) > 1 {
+				return args[1], nil
+			}
+
+			return cty.StringVal(""), nil
+		},
+	})
+}
+
+func (e *terragruntEvaluator) readTerragruntConfigFunc() function.Function {
+	return function.New(&function.Spec{
+		Params:   []function.Parameter{{Name: "path", Type: cty.String}},
+		VarParam: &function.Parameter{Name: "default", Type: cty.DynamicPseudoType},
+		Type:     function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(e.dir, path)
+			}
+
+			// The config is evaluated in the context of its own directory
+			other := newTerragruntEvaluator(filepath.Dir(path), e.originalDir, e.logger, e.depth+1)
+
+			f, err := other.evaluateFile(path)
+			if err != nil {
+				if len(args) > 1 {
+					return args[1], nil
+				}
+				return cty.NilVal, err
+			}
+
+			source := cty.NullVal(cty.String)
+			if f.source != nil {
+				source = cty.StringVal(*f.source)
+			}
+
+			return cty.ObjectVal(map[string]cty.Value{
+				"locals":     cty.ObjectVal(f.locals),
+				"inputs":     cty.ObjectVal(f.inputs),
+				"dependency": cty.ObjectVal(f.dependencies),
+				"terraform":  cty.ObjectVal(map[string]cty.Value{"source": source}),
+			}), nil
+		},
+	})
+}
+
+func (e *terragruntEvaluator) readTfvarsFileFunc() function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(e.dir, path)
+			}
+
+			parser := hclparse.NewParser()
+
+			var file *hcl.File
+			var diags hcl.Diagnostics
+			if strings.HasSuffix(path, ".json") {
+				file, diags = parser.ParseJSONFile(path)
+			} else {
+				file, diags = parser.ParseHCLFile(path)
+			}
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+
+			attrs, diags := file.Body.JustAttributes()
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+
+			vals := make(map[string]cty.Value, len(attrs))
+			for name, attr := range attrs {
+				v, diags := attr.Expr.Value(nil)
+				if diags.HasErrors() {
+					return cty.NilVal, diags
+				}
+				vals[name] = v
+			}
+
+			obj := cty.ObjectVal(vals)
+			b, err := ctyJson.Marshal(obj, obj.Type())
+			if err != nil {
+				return cty.NilVal, err
+			}
+
+			return cty.StringVal(string(b)), nil
+		},
+	})
+}
+
+func (e *terragruntEvaluator) includeDir(name string) (string, bool) {
+	if p, ok := e.includes[name]; ok {
+		return filepath.Dir(p), true
+	}
+
+	// An unnamed include can be referenced without a name, and if there is
+	// only one include it doesn't need to be named.
+	if name == "" && len(e.includes) == 1 {
+		for _, p := range e.includes {
+			return filepath.Dir(p), true
+		}
+	}
+
+	return "", false
+}
+
+func (e *terragruntEvaluator) pathRelativeToInclude(name string) string {
+	dir, ok := e.includeDir(name)
+	if !ok {
+		return "."
+	}
+
+	rel, err := filepath.Rel(dir, e.dir)
+	if err != nil {
+		return "."
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func (e *terragruntEvaluator) pathRelativeFromInclude(name string) string {
+	dir, ok := e.includeDir(name)
+	if !ok {
+		return "."
+	}
+
+	rel, err := filepath.Rel(e.dir, dir)
+	if err != nil {
+		return "."
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func (e *terragruntEvaluator) parentTerragruntDir(name string) string {
+	dir, ok := e.includeDir(name)
+	if !ok {
+		return e.dir
+	}
+
+	return dir
+}
+
+// repoRoot returns the root of the git repository the unit is in, or the
+// unit directory if it isn't in a git repository.
+func (e *terragruntEvaluator) repoRoot() string {
+	dir := e.dir
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return e.dir
+		}
+		dir = parent
+	}
+}
+
+// parseTerragruntFile parses a Terragrunt config file in either HCL or JSON
+// syntax. Include blocks can have an optional label in HCL syntax, which
+// can't be expressed in a schema, so the blocks are read from the syntax
+// tree instead.
+func parseTerragruntFile(path string) (*hcl.BodyContent, error) {
+	parser := hclparse.NewParser()
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSONFile(path)
+	} else {
+		file, diags = parser.ParseHCLFile(path)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("Error parsing Terragrunt config %s: %s", path, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		content, _, diags := file.Body.PartialContent(terragruntSchema)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("Error parsing Terragrunt config %s: %s", path, diags.Error())
+		}
+		return content, nil
+	}
+
+	content := &hcl.BodyContent{
+		Attributes:       hcl.Attributes{},
+		MissingItemRange: body.MissingItemRange(),
+	}
+
+	for name, attr := range body.Attributes {
+		if name == "inputs" || name == "skip" {
+			content.Attributes[name] = attr.AsHCLAttribute()
+		}
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "include", "locals", "terraform":
+			content.Blocks = append(content.Blocks, block.AsHCLBlock())
+		case "dependency":
+			if len(block.Labels) != 1 {
+				return nil, fmt.Errorf("dependency block in %s must have a name", path)
+			}
+			content.Blocks = append(content.Blocks, block.AsHCLBlock())
+		}
+	}
+
+	return content, nil
+}
+
+// resolveTerragruntSource returns the Terraform source relative to the unit
+// directory if it is a local path, removing the // that separates the
+// module directory. Remote sources are returned unchanged.
+func resolveTerragruntSource(dir string, source string) string {
+	if source == "" || IsRemoteTerragruntSource(source) {
+		return source
+	}
+
+	source = strings.TrimPrefix(source, "file://")
+	if filepath.IsAbs(source) {
+		return filepath.Clean(source)
+	}
+
+	return filepath.Join(dir, source)
+}
+
+// IsRemoteTerragruntSource returns true if the Terragrunt terraform source
+// needs to be downloaded, e.g. it's a git or registry source.
+func IsRemoteTerragruntSource(source string) bool {
+	if strings.HasPrefix(source, "file://") {
+		return false
+	}
+
+	if strings.Contains(source, "::") || strings.Contains(source, "://") {
+		return true
+	}
+
+	for _, prefix := range []string{"github.com/", "bitbucket.org/", "git@"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// knownInputs returns the inputs that are known, replacing any unknown
+// values nested in the inputs with null. Inputs that are unknown, e.g.
+// because they use the output of a dependency without mock outputs, are
+// removed so the variable default is used instead.
+func knownInputs(inputs map[string]cty.Value) map[string]cty.Value {
+	known := make(map[string]cty.Value, len(inputs))
+
+	for k, v := range inputs {
+		if !v.IsKnown() {
+			continue
+		}
+
+		if !v.IsWhollyKnown() {
+			v, _ = cty.Transform(v, func(p cty.Path, v cty.Value) (cty.Value, error) {
+				if !v.IsKnown() {
+					return cty.NullVal(v.Type()), nil
+				}
+				return v, nil
+			})
+		}
+
+		known[k] = v
+	}
+
+	return known
+}
+
+func copyVars(vars map[string]cty.Value) map[string]cty.Value {
+	c := make(map[string]cty.Value, len(vars)+1)
+	for k, v := range vars {
+		c[k] = v
+	}
+	return c
+}
+
+func sortedAttributeNames(attrs map[string]*hcl.Attribute) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
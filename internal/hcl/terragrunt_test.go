@@ -0,0 +1,66 @@
+package hcl
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLoadTerragruntConfig(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("include with deep merge and dependencies", func(t *testing.T) {
+		cfg, err := LoadTerragruntConfig("testdata/terragrunt/live/prod/app/terragrunt.hcl", logger)
+		require.NoError(t, err)
+
+		abs, err := filepath.Abs("testdata/terragrunt/modules/app")
+		require.NoError(t, err)
+		assert.Equal(t, abs, cfg.Source)
+		assert.False(t, cfg.Skip)
+
+		assert.Equal(t, cty.StringVal("m5.large"), cfg.Inputs["instance_type"])
+		assert.Equal(t, cty.StringVal("subnet-mock"), cfg.Inputs["subnet_id"])
+		assert.Equal(t, cty.StringVal("prod/app"), cfg.Inputs["path"])
+		assert.Equal(t, cty.StringVal("us-east-1"), cfg.Inputs["region"])
+		assert.Equal(t, cty.StringVal("us-east-1"), cfg.Inputs["root_region"])
+		assert.Equal(t, cty.StringVal("prod"), cfg.Inputs["env"])
+		assert.Equal(t, cty.ObjectVal(map[string]cty.Value{
+			"Owner": cty.StringVal("platform"),
+			"Team":  cty.StringVal("web"),
+		}), cfg.Inputs["tags"])
+
+		// Dependency outputs without mocks are unknown so the input is removed
+		_, ok := cfg.Inputs["unknown"]
+		assert.False(t, ok)
+	})
+
+	t.Run("include with shallow merge", func(t *testing.T) {
+		cfg, err := LoadTerragruntConfig("testdata/terragrunt/live/dev/app/terragrunt.hcl", logger)
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join("testdata", "terragrunt", "modules", "app"), cfg.Source)
+		assert.Equal(t, cty.StringVal("t3.micro"), cfg.Inputs["instance_type"])
+		assert.Equal(t, cty.StringVal("dev"), cfg.Inputs["env"])
+		assert.Equal(t, cty.ObjectVal(map[string]cty.Value{
+			"Team": cty.StringVal("web"),
+		}), cfg.Inputs["tags"])
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		cfg, err := LoadTerragruntConfig("testdata/terragrunt/live/skipped/terragrunt.hcl", logger)
+		require.NoError(t, err)
+		assert.True(t, cfg.Skip)
+	})
+}
+
+func TestIsRemoteTerragruntSource(t *testing.T) {
+	assert.True(t, IsRemoteTerragruntSource("git::https://github.com/org/modules.git//app?ref=v1.0.0"))
+	assert.True(t, IsRemoteTerragruntSource("tfr:///terraform-aws-modules/vpc/aws?version=3.3.0"))
+	assert.True(t, IsRemoteTerragruntSource("github.com/org/modules//app"))
+	assert.False(t, IsRemoteTerragruntSource("../modules//app"))
+	assert.False(t, IsRemoteTerragruntSource("file:///modules/app"))
+}
@@ -0,0 +1,14 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform {
+  source = "../../../modules/app"
+}
+
+inputs = {
+  instance_type = "t3.micro"
+  tags = {
+    Team = "web"
+  }
+}
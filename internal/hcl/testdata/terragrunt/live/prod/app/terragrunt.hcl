@@ -0,0 +1,41 @@
+include "root" {
+  path           = find_in_parent_folders()
+  expose         = true
+  merge_strategy = "deep"
+}
+
+locals {
+  instance_type = "m5.${local.size}"
+  size          = "large"
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+
+  mock_outputs = {
+    subnet_id = "subnet-mock"
+  }
+}
+
+dependency "unknown" {
+  config_path = "../unknown"
+}
+
+terraform {
+  source = "${get_parent_terragrunt_dir("root")}/../modules//app"
+
+  extra_arguments "common" {
+    commands = get_terraform_commands_that_need_vars()
+  }
+}
+
+inputs = {
+  instance_type = local.instance_type
+  subnet_id     = dependency.vpc.outputs.subnet_id
+  unknown       = dependency.unknown.outputs.id
+  path          = path_relative_to_include()
+  root_region   = include.root.locals.region
+  tags = {
+    Team = "web"
+  }
+}
@@ -0,0 +1,5 @@
+skip = true
+
+terraform {
+  source = "../../modules/app"
+}
@@ -0,0 +1,12 @@
+locals {
+  env_vars = read_terragrunt_config(find_in_parent_folders("env.hcl"))
+  region   = "us-east-1"
+}
+
+inputs = {
+  region = local.region
+  env    = local.env_vars.locals.env
+  tags = {
+    Owner = "platform"
+  }
+}
@@ -0,0 +1,25 @@
+variable "instance_type" {
+  type = string
+}
+
+variable "env" {
+  type = string
+}
+
+variable "region" {
+  type    = string
+  default = "eu-west-1"
+}
+
+provider "aws" {
+  region = var.region
+}
+
+resource "aws_instance" "app" {
+  ami           = "ami-12345678"
+  instance_type = var.instance_type
+
+  tags = {
+    Name = "app-${var.env}"
+  }
+}
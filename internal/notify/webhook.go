@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// Webhook posts messages to a webhook URL. Requests that fail with a network
+// error, a 429 or a 5xx response are retried with an exponential backoff.
+type Webhook struct {
+	URL     string
+	Headers http.Header
+	// MaxRetries is the number of times a failed request is retried.
+	MaxRetries int
+	// RetryWait is the time waited before the first retry, it's doubled for
+	// each retry after that unless the webhook returns a Retry-After header.
+	RetryWait time.Duration
+
+	client *http.Client
+}
+
+// NewWebhook returns a new Webhook.
+func NewWebhook(url string, headers http.Header, maxRetries int, retryWait time.Duration) *Webhook {
+	return &Webhook{
+		URL:        url,
+		Headers:    headers,
+		MaxRetries: maxRetries,
+		RetryWait:  retryWait,
+		client:     &http.Client{Timeout: time.Second * 30},
+	}
+}
+
+// Send posts the body to the webhook, retrying if the request fails with an
+// error that might be temporary.
+func (w *Webhook) Send(ctx context.Context, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		retryAfter, retryable, err := w.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable || attempt >= w.MaxRetries {
+			if attempt > 0 {
+				return errors.Wrapf(err, "Webhook request failed after %d attempts", attempt+1)
+			}
+
+			return err
+		}
+
+		wait := w.RetryWait * time.Duration(1<<attempt)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+
+		logging.Logger.WithError(err).Debugf("webhook request failed, retrying in %s", wait)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send makes a single request to the webhook. It returns whether the request
+// can be retried and how long the webhook asked to wait before retrying.
+func (w *Webhook) send(ctx context.Context, body []byte) (time.Duration, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error creating webhook request")
+	}
+
+	for name, values := range w.Headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, true, errors.Wrap(err, "Error sending webhook request")
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return 0, false, nil
+	}
+
+	err = errors.Errorf("Webhook returned %s", res.Status)
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		return retryAfter(res.Header.Get("Retry-After")), true, err
+	}
+
+	return 0, false, err
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or a date.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// RenderHeaders parses headers in the format "Name: value" and returns them
+// with their values rendered as Go templates. The templates are executed with
+// data and can read environment variables with the env function, e.g.
+// "Authorization: Bearer {{ env "WEBHOOK_TOKEN" }}".
+func RenderHeaders(headers []string, data interface{}) (http.Header, error) {
+	rendered := http.Header{}
+
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("header %q is not valid, headers must be in the format 'Name: value'", h)
+		}
+
+		tmpl, err := template.New(name).Funcs(template.FuncMap{"env": os.Getenv}).Parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing template of header %s", name)
+		}
+
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error rendering template of header %s", name)
+		}
+
+		rendered.Add(name, buf.String())
+	}
+
+	return rendered, nil
+}
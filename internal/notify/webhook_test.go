@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSend(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      string
+	}{
+		{
+			name:         "success",
+			statuses:     []int{http.StatusOK},
+			wantRequests: 1,
+		},
+		{
+			name:         "retries server errors",
+			statuses:     []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusNoContent},
+			wantRequests: 3,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusBadRequest},
+			wantRequests: 1,
+			wantErr:      "Webhook returned 400 Bad Request",
+		},
+		{
+			name:         "stops after max retries",
+			statuses:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			wantRequests: 3,
+			wantErr:      "Webhook request failed after 3 attempts: Webhook returned 500 Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(b))
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				w.WriteHeader(tt.statuses[len(bodies)-1])
+			}))
+			defer ts.Close()
+
+			w := NewWebhook(ts.URL, http.Header{"Content-Type": []string{"application/json"}}, 2, time.Millisecond)
+			err := w.Send(context.Background(), []byte(`{"text":"hello"}`))
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, bodies, tt.wantRequests)
+			for _, b := range bodies {
+				assert.Equal(t, `{"text":"hello"}`, b)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), retryAfter(""))
+	assert.Equal(t, 3*time.Second, retryAfter("3"))
+	assert.Equal(t, time.Duration(0), retryAfter("soon"))
+}
+
+func TestRenderHeaders(t *testing.T) {
+	t.Setenv("WEBHOOK_TOKEN", "secret")
+
+	headers, err := RenderHeaders([]string{
+		`Authorization: Bearer {{ env "WEBHOOK_TOKEN" }}`,
+		"X-Currency: {{ .Currency }}",
+		"X-Static:value:with:colons",
+	}, struct{ Currency string }{Currency: "USD"})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.Header{
+		"Authorization": []string{"Bearer secret"},
+		"X-Currency":    []string{"USD"},
+		"X-Static":      []string{"value:with:colons"},
+	}, headers)
+
+	_, err = RenderHeaders([]string{"no-colon"}, nil)
+	assert.EqualError(t, err, `header "no-colon" is not valid, headers must be in the format 'Name: value'`)
+
+	_, err = RenderHeaders([]string{"X-Bad: {{ .Missing"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Annotation is a message about a resource that is anchored to the lines of
+// the file where the resource is defined, e.g. for GitHub check runs.
+type Annotation struct {
+	// Path is relative to the root of the repo.
+	Path      string
+	StartLine int
+	EndLine   int
+	// Level is warning if the cost of the resource increases, otherwise notice.
+	Level   string
+	Title   string
+	Message string
+}
+
+// ToAnnotations returns an annotation for each resource that changes the cost.
+// Only resources that have a file location in their metadata are annotated,
+// which are the resources of projects evaluated from a Terraform directory.
+func ToAnnotations(out Root) []Annotation {
+	var annotations []Annotation
+
+	for _, project := range out.Projects {
+		for _, result := range sarifResourceResults(project, out.Currency, DefaultSARIFCostThreshold) {
+			if result.RuleID != sarifRuleCostChange || len(result.Locations) == 0 {
+				continue
+			}
+
+			loc := result.Locations[0]
+			if loc.PhysicalLocation == nil || loc.PhysicalLocation.Region == nil {
+				continue
+			}
+
+			level := "notice"
+			if result.Level == "warning" {
+				level = "warning"
+			}
+
+			var title string
+			if len(loc.LogicalLocations) > 0 {
+				title = loc.LogicalLocations[0].FullyQualifiedName
+			}
+
+			endLine := loc.PhysicalLocation.Region.EndLine
+			if endLine < loc.PhysicalLocation.Region.StartLine {
+				endLine = loc.PhysicalLocation.Region.StartLine
+			}
+
+			annotations = append(annotations, Annotation{
+				Path:      loc.PhysicalLocation.ArtifactLocation.URI,
+				StartLine: loc.PhysicalLocation.Region.StartLine,
+				EndLine:   endLine,
+				Level:     level,
+				Title:     title,
+				Message:   result.Message.Text,
+			})
+		}
+	}
+
+	return annotations
+}
+
+// CostChangeTitle returns a sentence that summarizes the change in the total
+// monthly cost, e.g. for the title of a GitHub check run.
+func CostChangeTitle(out Root) string {
+	cost := out.TotalMonthlyCost
+	if cost == nil {
+		zero := decimal.Zero
+		cost = &zero
+	}
+
+	s := formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, cost, false)
+	return strings.ToUpper(s[:1]) + s[1:]
+}
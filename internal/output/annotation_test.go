@@ -0,0 +1,66 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToAnnotations(t *testing.T) {
+	resource := func(name string, cost int64, metadata map[string]interface{}) Resource {
+		return Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost)), Metadata: metadata}
+	}
+
+	webMetadata := map[string]interface{}{"filename": "/repo/infra/main.tf", "startLine": float64(3), "endLine": float64(9)}
+	dbMetadata := map[string]interface{}{"filename": "/repo/infra/db.tf", "startLine": float64(1), "endLine": float64(12)}
+
+	out := Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(454)),
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(370)),
+		Projects: []Project{
+			{
+				Name:     "infra",
+				Metadata: &schema.ProjectMetadata{Path: "/repo/infra", VCSSubPath: "infra"},
+				PastBreakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 50, webMetadata),
+					resource("aws_db_instance.db", 400, dbMetadata),
+					resource("aws_eip.old", 4, nil),
+				}},
+				Breakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 70, webMetadata),
+					resource("aws_db_instance.db", 300, dbMetadata),
+				}},
+				Diff: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 20, nil),
+					resource("aws_db_instance.db", -100, nil),
+					resource("aws_eip.old", -4, nil),
+				}},
+			},
+		},
+	}
+
+	assert.Equal(t, []Annotation{
+		{
+			Path:      "infra/main.tf",
+			StartLine: 3,
+			EndLine:   9,
+			Level:     "warning",
+			Title:     "aws_instance.web",
+			Message:   "aws_instance.web changes the monthly cost by +$20.00 ($50.00 → $70.00)",
+		},
+		{
+			Path:      "infra/db.tf",
+			StartLine: 1,
+			EndLine:   12,
+			Level:     "notice",
+			Title:     "aws_db_instance.db",
+			Message:   "aws_db_instance.db changes the monthly cost by -$100 ($400 → $300)",
+		},
+	}, ToAnnotations(out))
+
+	assert.Equal(t, "Monthly cost will decrease by $84.00 (-19%) ↓", CostChangeTitle(out))
+}
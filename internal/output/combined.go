@@ -25,6 +25,8 @@ var (
 	minOutputVersion     = "0.2"
 	maxOutputVersion     = "0.9"
 	GitHubMaxMessageSize = 262144 // bytes
+	// GitHubCheckRunMaxSummarySize is the maximum size of the summary of a GitHub check run.
+	GitHubCheckRunMaxSummarySize = 65535 // bytes
 )
 
 type ReportInput struct {
@@ -292,6 +294,10 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		addCurrencyFormat(opts.CurrencyFormat)
 	}
 
+	if len(opts.GroupBy) > 0 {
+		r.CostGroups = GroupCosts(r, opts.GroupBy)
+	}
+
 	switch format {
 	case "json":
 		b, err = ToJSON(r, opts)
@@ -301,7 +307,7 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = ToDiff(r, opts)
 	case "github-comment":
 		b, err = ToMarkdown(r, opts, MarkdownOptions{MaxMessageSize: GitHubMaxMessageSize})
-	case "gitlab-comment", "azure-repos-comment":
+	case "gitlab-comment", "azure-repos-comment", "gitea-comment":
 		b, err = ToMarkdown(r, opts, MarkdownOptions{})
 	case "bitbucket-comment":
 		b, err = ToMarkdown(r, opts, MarkdownOptions{BasicSyntax: true})
@@ -309,6 +315,16 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = ToMarkdown(r, opts, MarkdownOptions{BasicSyntax: true, OmitDetails: true})
 	case "slack-message":
 		b, err = ToSlackMessage(r, opts)
+	case "teams-message":
+		b, err = ToTeamsMessage(r, opts)
+	case "sarif":
+		b, err = ToSARIF(r, opts)
+	case "csv":
+		b, err = ToCSV(r, opts)
+	case "xlsx":
+		b, err = ToXLSX(r, opts)
+	case "focus":
+		b, err = ToFOCUS(r, opts)
 	default:
 		b, err = ToTable(r, opts)
 	}
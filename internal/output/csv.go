@@ -0,0 +1,266 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var spreadsheetHeaders = []string{
+	"Project",
+	"Resource",
+	"Resource type",
+	"Tags",
+	"Sub-resource",
+	"Cost component",
+	"Unit",
+	"Hourly quantity",
+	"Monthly quantity",
+	"Price",
+	"Hourly cost",
+	"Monthly cost",
+	"Past monthly quantity",
+	"Past monthly cost",
+	"Diff monthly cost",
+}
+
+// spreadsheetRow is a single cost component of the breakdown tree, together
+// with the resource and sub-resources it belongs to and the values of the same
+// cost component in the past breakdown.
+type spreadsheetRow struct {
+	Project             string
+	Resource            string
+	ResourceType        string
+	Tags                string
+	SubResource         string
+	CostComponent       string
+	Unit                string
+	HourlyQuantity      *decimal.Decimal
+	MonthlyQuantity     *decimal.Decimal
+	Price               *decimal.Decimal
+	HourlyCost          *decimal.Decimal
+	MonthlyCost         *decimal.Decimal
+	PastMonthlyQuantity *decimal.Decimal
+	PastMonthlyCost     *decimal.Decimal
+	DiffMonthlyCost     *decimal.Decimal
+}
+
+func (r spreadsheetRow) strings() []string {
+	return []string{
+		r.Project,
+		r.Resource,
+		r.ResourceType,
+		r.Tags,
+		r.SubResource,
+		r.CostComponent,
+		r.Unit,
+		formatSpreadsheetDecimal(r.HourlyQuantity),
+		formatSpreadsheetDecimal(r.MonthlyQuantity),
+		formatSpreadsheetDecimal(r.Price),
+		formatSpreadsheetDecimal(r.HourlyCost),
+		formatSpreadsheetDecimal(r.MonthlyCost),
+		formatSpreadsheetDecimal(r.PastMonthlyQuantity),
+		formatSpreadsheetDecimal(r.PastMonthlyCost),
+		formatSpreadsheetDecimal(r.DiffMonthlyCost),
+	}
+}
+
+// ToCSV returns Root r as a CSV with a row for each cost component of each
+// resource, including the cost components of sub-resources.
+func ToCSV(out Root, opts Options) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	w := csv.NewWriter(buf)
+
+	err := w.Write(spreadsheetHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range out.Projects {
+		for _, row := range spreadsheetRows(project) {
+			err = w.Write(row.strings())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// spreadsheetRows flattens the breakdown of the project to a row for each cost
+// component. If the project has a past breakdown, the past values of each cost
+// component are added to its row, and the cost components that only exist in
+// the past breakdown are added at the end with no current values.
+func spreadsheetRows(project Project) []spreadsheetRow {
+	var current, past []spreadsheetRow
+	if project.Breakdown != nil {
+		current = flattenResources(project.Label(), project.Breakdown.Resources)
+	}
+
+	if project.PastBreakdown == nil {
+		return current
+	}
+
+	past = flattenResources(project.Label(), project.PastBreakdown.Resources)
+	pastKeys := spreadsheetRowKeys(past, nil)
+
+	pastByKey := make(map[string]spreadsheetRow, len(past))
+	for i, row := range past {
+		pastByKey[pastKeys[i]] = row
+	}
+
+	previousNames := make(map[string]string)
+	if project.Breakdown != nil {
+		for _, res := range project.Breakdown.Resources {
+			if prev := res.PreviousName(); prev != "" {
+				previousNames[res.Name] = prev
+			}
+		}
+	}
+
+	currentKeys := spreadsheetRowKeys(current, previousNames)
+
+	rows := make([]spreadsheetRow, 0, len(current))
+	for i, row := range current {
+		if pastRow, ok := pastByKey[currentKeys[i]]; ok {
+			delete(pastByKey, currentKeys[i])
+			row.PastMonthlyQuantity = pastRow.MonthlyQuantity
+			row.PastMonthlyCost = pastRow.MonthlyCost
+		}
+
+		row.DiffMonthlyCost = diffMonthlyCost(row.PastMonthlyCost, row.MonthlyCost)
+		rows = append(rows, row)
+	}
+
+	for i, row := range past {
+		if _, ok := pastByKey[pastKeys[i]]; !ok {
+			continue
+		}
+
+		rows = append(rows, spreadsheetRow{
+			Project:             row.Project,
+			Resource:            row.Resource,
+			ResourceType:        row.ResourceType,
+			Tags:                row.Tags,
+			SubResource:         row.SubResource,
+			CostComponent:       row.CostComponent,
+			Unit:                row.Unit,
+			PastMonthlyQuantity: row.MonthlyQuantity,
+			PastMonthlyCost:     row.MonthlyCost,
+			DiffMonthlyCost:     diffMonthlyCost(row.MonthlyCost, nil),
+		})
+	}
+
+	return rows
+}
+
+// spreadsheetRowKeys returns the keys used to match the rows of the current
+// and past breakdowns. Resources that have been moved are matched using their
+// previous name, and cost components with the same name are matched in order.
+func spreadsheetRowKeys(rows []spreadsheetRow, previousNames map[string]string) []string {
+	keys := make([]string, len(rows))
+	seen := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		resource := row.Resource
+		if prev, ok := previousNames[resource]; ok {
+			resource = prev
+		}
+
+		key := strings.Join([]string{resource, row.SubResource, row.CostComponent}, "\x00")
+		keys[i] = fmt.Sprintf("%s\x00%d", key, seen[key])
+		seen[key]++
+	}
+
+	return keys
+}
+
+func flattenResources(project string, resources []Resource) []spreadsheetRow {
+	var rows []spreadsheetRow
+
+	for _, res := range resources {
+		rows = append(rows, flattenResource(project, res, res, "")...)
+	}
+
+	return rows
+}
+
+func flattenResource(project string, root Resource, res Resource, subResource string) []spreadsheetRow {
+	rows := make([]spreadsheetRow, 0, len(res.CostComponents))
+
+	for _, c := range res.CostComponents {
+		price := c.Price
+		rows = append(rows, spreadsheetRow{
+			Project:         project,
+			Resource:        root.Name,
+			ResourceType:    root.ResourceType(),
+			Tags:            formatSpreadsheetTags(root.Tags),
+			SubResource:     subResource,
+			CostComponent:   c.Name,
+			Unit:            c.Unit,
+			HourlyQuantity:  c.HourlyQuantity,
+			MonthlyQuantity: c.MonthlyQuantity,
+			Price:           &price,
+			HourlyCost:      c.HourlyCost,
+			MonthlyCost:     c.MonthlyCost,
+		})
+	}
+
+	for _, sub := range res.SubResources {
+		path := sub.Name
+		if subResource != "" {
+			path = subResource + " / " + sub.Name
+		}
+
+		rows = append(rows, flattenResource(project, root, sub, path)...)
+	}
+
+	return rows
+}
+
+// diffMonthlyCost returns the change from the past to the current monthly
+// cost, treating a missing cost as zero unless both are missing.
+func diffMonthlyCost(pastCost, cost *decimal.Decimal) *decimal.Decimal {
+	if pastCost == nil && cost == nil {
+		return nil
+	}
+
+	diff := decimal.Zero
+	if cost != nil {
+		diff = diff.Add(*cost)
+	}
+
+	if pastCost != nil {
+		diff = diff.Sub(*pastCost)
+	}
+
+	return &diff
+}
+
+func formatSpreadsheetTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, "; ")
+}
+
+func formatSpreadsheetDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spreadsheetTestRoot() Root {
+	component := func(name string, quantity, price int64) CostComponent {
+		q := decimal.NewFromInt(quantity)
+		cost := q.Mul(decimal.NewFromInt(price))
+		return CostComponent{Name: name, Unit: "hours", MonthlyQuantity: &q, Price: decimal.NewFromInt(price), MonthlyCost: &cost}
+	}
+
+	return Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(25)),
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(30)),
+		DiffTotalMonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+		Projects: []Project{
+			{
+				Name: "infra/dev",
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(25)),
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.old_name",
+							CostComponents: []CostComponent{component("Instance usage", 10, 2)},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{component("Storage", 5, 1)}},
+							},
+						},
+						{Name: "aws_eip.removed", CostComponents: []CostComponent{component("IP address", 10, 1)}},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30)),
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							Tags:           map[string]string{"team": "infra", "env": "dev"},
+							Metadata:       map[string]interface{}{"previousAddress": "aws_instance.old_name"},
+							CostComponents: []CostComponent{component("Instance usage", 10, 2)},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{component("Storage", 10, 1)}},
+							},
+						},
+					},
+				},
+				Diff: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+			},
+		},
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	b, err := ToCSV(spreadsheetTestRoot(), Options{})
+	require.NoError(t, err)
+
+	expected := "Project,Resource,Resource type,Tags,Sub-resource,Cost component,Unit,Hourly quantity,Monthly quantity,Price,Hourly cost,Monthly cost,Past monthly quantity,Past monthly cost,Diff monthly cost\n" +
+		"infra/dev,aws_instance.web,aws_instance,env=dev; team=infra,,Instance usage,hours,,10,2,,20,10,20,0\n" +
+		"infra/dev,aws_instance.web,aws_instance,env=dev; team=infra,root_block_device,Storage,hours,,10,1,,10,5,5,5\n" +
+		"infra/dev,aws_eip.removed,aws_eip,,,IP address,hours,,,,,,10,10,-10\n"
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToXLSX(t *testing.T) {
+	root := spreadsheetTestRoot()
+	root.Projects = append(root.Projects, Project{Name: "infra:dev", Breakdown: &Breakdown{}})
+
+	b, err := ToXLSX(root, Options{})
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/sheet2.xml",
+		"xl/worksheets/sheet3.xml",
+	}, fileNames(files))
+
+	workbook := files["xl/workbook.xml"]
+	assert.Contains(t, workbook, `<sheet name="Summary" sheetId="1" r:id="rId1"></sheet>`)
+	assert.Contains(t, workbook, `<sheet name="infra_dev" sheetId="2" r:id="rId2"></sheet>`)
+	assert.Contains(t, workbook, `<sheet name="infra_dev (2)" sheetId="3" r:id="rId3"></sheet>`)
+
+	summary := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, summary, `<row r="2"><c r="A2" t="inlineStr"><is><t>infra/dev</t></is></c><c r="B2"><v>25</v></c><c r="C2"><v>30</v></c><c r="D2"><v>5</v></c></row>`)
+	assert.Contains(t, summary, `<c r="A4" t="inlineStr"><is><t>Total</t></is></c>`)
+
+	project := files["xl/worksheets/sheet2.xml"]
+	assert.Contains(t, project, `<c r="A1" t="inlineStr"><is><t>Resource</t></is></c>`)
+	assert.Contains(t, project, `<c r="D3" t="inlineStr"><is><t>root_block_device</t></is></c>`)
+	assert.Contains(t, project, `<c r="N4"><v>-10</v></c>`)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
+
+func fileNames(m map[string]string) []string {
+	k := make([]string, 0, len(m))
+	for key := range m {
+		k = append(k, key)
+	}
+
+	return k
+}
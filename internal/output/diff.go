@@ -8,6 +8,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/shopspring/decimal"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 )
 
@@ -62,7 +63,21 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 			oldResource := findResourceByName(project.PastBreakdown.Resources, diffResource.Name)
 			newResource := findResourceByName(project.Breakdown.Resources, diffResource.Name)
 
-			s += resourceToDiff(out.Currency, diffResource, oldResource, newResource, true)
+			note := ""
+			if oldResource == nil && newResource != nil && newResource.PreviousName() != "" {
+				oldResource = findResourceByName(project.PastBreakdown.Resources, newResource.PreviousName())
+				if oldResource != nil {
+					note = fmt.Sprintf("moved from %s", newResource.PreviousName())
+				}
+			}
+
+			if oldResource == nil && newResource != nil && newResource.IsImported() {
+				note = "imported"
+			} else if newResource == nil && isForgotten(project.Metadata, diffResource.Name) {
+				note = "forgotten"
+			}
+
+			s += resourceToDiff(out.Currency, diffResource, oldResource, newResource, true, note)
 			s += "\n"
 		}
 
@@ -120,7 +135,9 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 	return []byte(s), nil
 }
 
-func resourceToDiff(currency string, diffResource Resource, oldResource *Resource, newResource *Resource, isTopLevel bool) string {
+// resourceToDiff returns the diff output for the resource. The note is
+// shown after the name, e.g. to show that a resource was imported.
+func resourceToDiff(currency string, diffResource Resource, oldResource *Resource, newResource *Resource, isTopLevel bool, note string) string {
 	s := ""
 
 	op := UPDATED
@@ -145,6 +162,10 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		nameLabel = ui.BoldString(nameLabel)
 	}
 
+	if note != "" {
+		nameLabel += " " + ui.FaintStringf("(%s)", note)
+	}
+
 	s += fmt.Sprintf("%s %s\n", opChar(op), nameLabel)
 
 	if isTopLevel {
@@ -185,7 +206,7 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		}
 
 		s += "\n"
-		s += ui.Indent(resourceToDiff(currency, diffSubResource, oldSubResource, newSubResource, false), "    ")
+		s += ui.Indent(resourceToDiff(currency, diffSubResource, oldSubResource, newSubResource, false, ""), "    ")
 	}
 
 	return s
@@ -248,6 +269,22 @@ func opChar(op int) string {
 	}
 }
 
+// isForgotten returns true if the resource is removed from the state without
+// being destroyed, either directly or because its module is.
+func isForgotten(metadata *schema.ProjectMetadata, name string) bool {
+	if metadata == nil {
+		return false
+	}
+
+	for _, addr := range metadata.ForgottenResources {
+		if name == addr || strings.HasPrefix(name, addr+".") || strings.HasPrefix(name, addr+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
 func findResourceByName(resources []Resource, name string) *Resource {
 	for _, r := range resources {
 		if r.Name == name {
@@ -0,0 +1,193 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// focusHeaders are the FinOps Open Cost and Usage Specification (FOCUS)
+// columns of the FOCUS output. Columns that FOCUS doesn't define are prefixed
+// with x_ as the specification requires.
+var focusHeaders = []string{
+	"BilledCost",
+	"BillingCurrency",
+	"ChargeCategory",
+	"ChargeDescription",
+	"ChargeFrequency",
+	"ChargePeriodStart",
+	"ChargePeriodEnd",
+	"EffectiveCost",
+	"ListCost",
+	"ListUnitPrice",
+	"PricingQuantity",
+	"PricingUnit",
+	"ProviderName",
+	"PublisherName",
+	"RegionId",
+	"ResourceId",
+	"ResourceName",
+	"ResourceType",
+	"ServiceName",
+	"SubAccountName",
+	"Tags",
+	"x_SubResource",
+}
+
+// focusProviderNames are the FOCUS provider names of the vendor names used in
+// product filters.
+var focusProviderNames = map[string]string{
+	"aws":   "AWS",
+	"azure": "Microsoft",
+	"gcp":   "Google Cloud",
+	"ibm":   "IBM",
+}
+
+// focusResourceTypePrefixes are used to find the vendor of cost components
+// that have no vendor name, e.g. ones read from older Infracost JSON files.
+var focusResourceTypePrefixes = map[string]string{
+	"aws_":     "aws",
+	"azurerm_": "azure",
+	"google_":  "gcp",
+	"ibm_":     "ibm",
+}
+
+// ToFOCUS returns Root r as a FOCUS dataset in CSV format, with a row for each
+// cost component of each resource. The estimates are monthly, so the charge
+// period of every row is the calendar month that the run was generated in.
+func ToFOCUS(out Root, opts Options) ([]byte, error) {
+	generated := out.TimeGenerated
+	if generated.IsZero() {
+		generated = time.Now()
+	}
+
+	generated = generated.UTC()
+	periodStart := time.Date(generated.Year(), generated.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	buf := bytes.NewBuffer([]byte{})
+	w := csv.NewWriter(buf)
+
+	err := w.Write(focusHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range out.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, res := range project.Breakdown.Resources {
+			tags, err := focusTags(res.Tags)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, c := range focusCostComponents(res, "") {
+				cost := decimal.Zero
+				if c.MonthlyCost != nil {
+					cost = *c.MonthlyCost
+				}
+
+				providerName := focusProviderName(c.VendorName, res.ResourceType())
+
+				err = w.Write([]string{
+					cost.String(),
+					out.Currency,
+					"Usage",
+					c.Name,
+					"Usage-Based",
+					periodStart.Format(time.RFC3339),
+					periodEnd.Format(time.RFC3339),
+					cost.String(),
+					cost.String(),
+					c.Price.String(),
+					formatSpreadsheetDecimal(c.MonthlyQuantity),
+					c.Unit,
+					providerName,
+					providerName,
+					c.Region,
+					res.Name,
+					res.Name,
+					res.ResourceType(),
+					c.Service,
+					project.Label(),
+					tags,
+					c.subResource,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type focusCostComponent struct {
+	CostComponent
+	subResource string
+}
+
+// focusCostComponents returns the cost components of the resource and its
+// sub-resources, together with the path of the sub-resource they belong to.
+func focusCostComponents(res Resource, subResource string) []focusCostComponent {
+	comps := make([]focusCostComponent, 0, len(res.CostComponents))
+	for _, c := range res.CostComponents {
+		comps = append(comps, focusCostComponent{CostComponent: c, subResource: subResource})
+	}
+
+	for _, sub := range res.SubResources {
+		path := sub.Name
+		if subResource != "" {
+			path = subResource + " / " + sub.Name
+		}
+
+		comps = append(comps, focusCostComponents(sub, path)...)
+	}
+
+	return comps
+}
+
+func focusProviderName(vendorName, resourceType string) string {
+	if vendorName == "" {
+		for prefix, vendor := range focusResourceTypePrefixes {
+			if strings.HasPrefix(resourceType, prefix) {
+				vendorName = vendor
+				break
+			}
+		}
+	}
+
+	if name, ok := focusProviderNames[vendorName]; ok {
+		return name
+	}
+
+	return vendorName
+}
+
+// focusTags returns the tags as the JSON object that FOCUS uses for the Tags
+// column.
+func focusTags(tags map[string]string) (string, error) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToFOCUS(t *testing.T) {
+	instance := &schema.CostComponent{
+		Name:            "Instance usage (Linux/UNIX, on-demand, t3.micro)",
+		Unit:            "hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+		MonthlyCost:     decimalPtr(decimal.RequireFromString("7.592")),
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			Region:     strPtr("us-east-1"),
+		},
+	}
+	instance.SetPrice(decimal.RequireFromString("0.0104"))
+
+	storage := &schema.CostComponent{
+		Name:            "Storage (general purpose SSD, gp3)",
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(8)),
+		MonthlyCost:     decimalPtr(decimal.RequireFromString("0.64")),
+	}
+	storage.SetPrice(decimal.RequireFromString("0.08"))
+
+	out := Root{
+		Currency:      "USD",
+		TimeGenerated: time.Date(2024, 2, 14, 10, 30, 0, 0, time.UTC),
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					{
+						Name:           "aws_instance.web",
+						Tags:           map[string]string{"team": "platform"},
+						CostComponents: outputCostComponents([]*schema.CostComponent{instance}),
+						SubResources: []Resource{
+							{Name: "root_block_device", CostComponents: outputCostComponents([]*schema.CostComponent{storage})},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	b, err := ToFOCUS(out, Options{})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	rows := make([]map[string]string, 0, 2)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(record))
+		for i, v := range record {
+			row[records[0][i]] = v
+		}
+		rows = append(rows, row)
+	}
+
+	assert.Equal(t, "7.592", rows[0]["EffectiveCost"])
+	assert.Equal(t, "0.0104", rows[0]["ListUnitPrice"])
+	assert.Equal(t, "730", rows[0]["PricingQuantity"])
+	assert.Equal(t, "hours", rows[0]["PricingUnit"])
+	assert.Equal(t, "AWS", rows[0]["ProviderName"])
+	assert.Equal(t, "AmazonEC2", rows[0]["ServiceName"])
+	assert.Equal(t, "us-east-1", rows[0]["RegionId"])
+	assert.Equal(t, "aws_instance.web", rows[0]["ResourceId"])
+	assert.Equal(t, "aws_instance", rows[0]["ResourceType"])
+	assert.Equal(t, "infra", rows[0]["SubAccountName"])
+	assert.Equal(t, `{"team":"platform"}`, rows[0]["Tags"])
+	assert.Equal(t, "2024-02-01T00:00:00Z", rows[0]["ChargePeriodStart"])
+	assert.Equal(t, "2024-03-01T00:00:00Z", rows[0]["ChargePeriodEnd"])
+
+	assert.Equal(t, "AWS", rows[1]["ProviderName"], "provider should fall back to the resource type")
+	assert.Equal(t, "", rows[1]["ServiceName"])
+	assert.Equal(t, "root_block_device", rows[1]["x_SubResource"])
+	assert.Equal(t, "0.64", rows[1]["BilledCost"])
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,165 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// Forecast is the month-by-month cost of a set of projects, calculated from
+// usage that changes over time.
+type Forecast struct {
+	Currency   string            `json:"currency"`
+	StartMonth string            `json:"startMonth,omitempty"`
+	Months     []ForecastMonth   `json:"months"`
+	Projects   []ForecastProject `json:"projects"`
+	TotalCost  *decimal.Decimal  `json:"totalCost"`
+}
+
+// ForecastMonth is the total cost of all the projects for a single month.
+type ForecastMonth struct {
+	Month       int              `json:"month"`
+	Period      string           `json:"period,omitempty"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+}
+
+type ForecastProject struct {
+	Name      string                 `json:"name"`
+	Months    []ForecastProjectMonth `json:"months"`
+	TotalCost *decimal.Decimal       `json:"totalCost"`
+}
+
+type ForecastProjectMonth struct {
+	Month       int                `json:"month"`
+	MonthlyCost *decimal.Decimal   `json:"monthlyCost"`
+	Resources   []ForecastResource `json:"resources"`
+}
+
+type ForecastResource struct {
+	Name        string           `json:"name"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+}
+
+// AddForecastMonth adds the costs of each project for a month to the costs of
+// the projects for the previous months. Projects are matched by name. A project
+// that isn't in monthProjects has no costs that month, and a project that is
+// only in monthProjects has no costs for the previous months.
+func AddForecastMonth(projects []ForecastProject, monthProjects []ForecastProject, month int) []ForecastProject {
+	indexes := make(map[string]int, len(projects))
+	for i, p := range projects {
+		indexes[p.Name] = i
+	}
+
+	for _, p := range monthProjects {
+		i, ok := indexes[p.Name]
+		if !ok {
+			i = len(projects)
+			indexes[p.Name] = i
+			projects = append(projects, ForecastProject{Name: p.Name})
+		}
+
+		projects[i].Months = padForecastMonths(projects[i].Months, month)
+
+		for _, m := range p.Months {
+			// Projects with the same name are combined into a single project.
+			if len(projects[i].Months) > month {
+				existing := &projects[i].Months[month]
+				existing.MonthlyCost = decimalPtr(existing.MonthlyCost.Add(*m.MonthlyCost))
+				existing.Resources = append(existing.Resources, m.Resources...)
+				continue
+			}
+
+			projects[i].Months = append(projects[i].Months, m)
+		}
+	}
+
+	for i := range projects {
+		projects[i].Months = padForecastMonths(projects[i].Months, month+1)
+	}
+
+	return projects
+}
+
+// padForecastMonths adds months with no costs until there are n months.
+func padForecastMonths(months []ForecastProjectMonth, n int) []ForecastProjectMonth {
+	for len(months) < n {
+		months = append(months, ForecastProjectMonth{
+			Month:       len(months) + 1,
+			MonthlyCost: decimalPtr(decimal.Zero),
+			Resources:   []ForecastResource{},
+		})
+	}
+
+	return months
+}
+
+// ToForecastTable renders the forecast as a table with a row for each month
+// and a column for each project, followed by the total for the period.
+func ToForecastTable(f Forecast) []byte {
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+
+	headers := table.Row{ui.UnderlineString("Month")}
+	columns := []table.ColumnConfig{
+		{Number: 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft},
+	}
+
+	// Only show a column for each project if there's more than one since the
+	// total column would show the same costs otherwise.
+	showProjects := len(f.Projects) > 1
+	if showProjects {
+		for i, p := range f.Projects {
+			headers = append(headers, ui.UnderlineString(p.Name))
+			columns = append(columns, table.ColumnConfig{Number: i + 2, Align: text.AlignRight, AlignHeader: text.AlignRight})
+		}
+	}
+
+	headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Monthly Cost", f.Currency)))
+	columns = append(columns, table.ColumnConfig{Number: len(headers), Align: text.AlignRight, AlignHeader: text.AlignRight})
+
+	t.SetColumnConfigs(columns)
+	t.AppendHeader(headers)
+	t.AppendRow(table.Row{""})
+
+	for i, m := range f.Months {
+		label := fmt.Sprintf("Month %d", m.Month)
+		if m.Period != "" {
+			label = m.Period
+		}
+
+		row := table.Row{label}
+		if showProjects {
+			for _, p := range f.Projects {
+				var cost *decimal.Decimal
+				if i < len(p.Months) {
+					cost = p.Months[i].MonthlyCost
+				}
+				row = append(row, FormatCost2DP(f.Currency, cost))
+			}
+		}
+		row = append(row, FormatCost2DP(f.Currency, m.MonthlyCost))
+
+		t.AppendRow(row)
+	}
+
+	t.AppendRow(table.Row{""})
+
+	totalRow := table.Row{ui.BoldString(fmt.Sprintf("Total for %d months", len(f.Months)))}
+	if showProjects {
+		for _, p := range f.Projects {
+			totalRow = append(totalRow, FormatCost2DP(f.Currency, p.TotalCost))
+		}
+	}
+	totalRow = append(totalRow, ui.BoldString(FormatCost2DP(f.Currency, f.TotalCost)))
+	t.AppendRow(totalRow)
+
+	return []byte(t.Render() + "\n")
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func forecastTestProject(name string, month int, cost int64) ForecastProject {
+	return ForecastProject{
+		Name: name,
+		Months: []ForecastProjectMonth{{
+			Month:       month + 1,
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+			Resources:   []ForecastResource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}},
+		}},
+	}
+}
+
+func forecastMonthlyCosts(projects []ForecastProject) map[string][]string {
+	costs := make(map[string][]string, len(projects))
+	for _, p := range projects {
+		for i, m := range p.Months {
+			if m.Month != i+1 {
+				costs[p.Name] = append(costs[p.Name], "wrong month")
+				continue
+			}
+			costs[p.Name] = append(costs[p.Name], m.MonthlyCost.String())
+		}
+	}
+
+	return costs
+}
+
+func TestAddForecastMonth(t *testing.T) {
+	var projects []ForecastProject
+
+	projects = AddForecastMonth(projects, []ForecastProject{
+		forecastTestProject("first", 0, 10),
+		forecastTestProject("second", 0, 20),
+		forecastTestProject("third", 0, 30),
+	}, 0)
+
+	// The first project disappears.
+	projects = AddForecastMonth(projects, []ForecastProject{
+		forecastTestProject("second", 1, 21),
+		forecastTestProject("third", 1, 31),
+	}, 1)
+
+	// The first project comes back and a new project is added.
+	projects = AddForecastMonth(projects, []ForecastProject{
+		forecastTestProject("first", 2, 12),
+		forecastTestProject("third", 2, 32),
+		forecastTestProject("fourth", 2, 42),
+	}, 2)
+
+	require.Len(t, projects, 4)
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, []string{projects[0].Name, projects[1].Name, projects[2].Name, projects[3].Name})
+	assert.Equal(t, map[string][]string{
+		"first":  {"10", "0", "12"},
+		"second": {"20", "21", "0"},
+		"third":  {"30", "31", "32"},
+		"fourth": {"0", "0", "42"},
+	}, forecastMonthlyCosts(projects))
+
+	assert.Empty(t, projects[0].Months[1].Resources)
+	assert.NotNil(t, projects[0].Months[1].Resources)
+}
+
+func TestAddForecastMonthCombinesProjectsWithTheSameName(t *testing.T) {
+	projects := AddForecastMonth(nil, []ForecastProject{
+		forecastTestProject("main", 0, 10),
+		forecastTestProject("main", 0, 5),
+	}, 0)
+
+	require.Len(t, projects, 1)
+	assert.Equal(t, map[string][]string{"main": {"15"}}, forecastMonthlyCosts(projects))
+	assert.Len(t, projects[0].Months[0].Resources, 2)
+}
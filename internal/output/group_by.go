@@ -0,0 +1,278 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	groupByTagPrefix    = "tag:"
+	groupByResourceType = "resource_type"
+	groupByRegion       = "region"
+	groupByModule       = "module"
+
+	untaggedGroup    = "untagged"
+	unknownRegion    = "unknown"
+	rootModuleGroup  = "root"
+	groupSubtotalFmt = "%s subtotal"
+)
+
+var moduleAddressRegex = regexp.MustCompile(`^((?:module\.[^.\[]+(?:\[[^\]]*\])?\.)*)`)
+
+// CostGroups are the monthly costs of all the resources grouped by the values of
+// the group by keys, e.g. by the value of the team tag.
+type CostGroups struct {
+	Keys   []string    `json:"keys"`
+	Groups []CostGroup `json:"groups"`
+}
+
+// HasPast returns true if the groups have past and diff monthly costs.
+func (g *CostGroups) HasPast() bool {
+	return len(g.Groups) > 0 && g.Groups[0].PastMonthlyCost != nil
+}
+
+// Cells returns a cell for each group by key with the values of the group. The
+// label of a subtotal group is in the first cell and its other cells are empty.
+func (g *CostGroups) Cells(group CostGroup) []string {
+	if !group.Subtotal {
+		return group.Values
+	}
+
+	cells := make([]string, len(g.Keys))
+	cells[0] = group.Label()
+
+	return cells
+}
+
+// CostGroup is the monthly cost of the resources with the same values for the
+// group by keys. When grouping by more than one key, the groups with the same
+// value for the first key are followed by a subtotal group that only has that
+// value.
+type CostGroup struct {
+	Values          []string         `json:"values"`
+	Subtotal        bool             `json:"subtotal,omitempty"`
+	PastMonthlyCost *decimal.Decimal `json:"pastMonthlyCost"`
+	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	DiffMonthlyCost *decimal.Decimal `json:"diffMonthlyCost"`
+}
+
+// Label returns the values of the group joined for display.
+func (g CostGroup) Label() string {
+	if g.Subtotal {
+		return fmt.Sprintf(groupSubtotalFmt, g.Values[0])
+	}
+
+	return strings.Join(g.Values, ", ")
+}
+
+// ValidateGroupBy returns an error if any of the group by keys isn't
+// supported. Valid keys are tag:<name>, resource_type, region and module.
+func ValidateGroupBy(keys []string) error {
+	for _, key := range keys {
+		switch {
+		case key == groupByResourceType, key == groupByRegion, key == groupByModule:
+		case strings.HasPrefix(key, groupByTagPrefix) && len(key) > len(groupByTagPrefix):
+		default:
+			return fmt.Errorf("Invalid group by key '%s', valid keys are tag:<name>, %s, %s and %s", key, groupByResourceType, groupByRegion, groupByModule)
+		}
+	}
+
+	return nil
+}
+
+// GroupCosts groups the resources of all the projects by the values of the
+// keys and sums their monthly costs. Resources that only exist in the past
+// breakdown are included so that the diff of each group adds up. Past and diff
+// costs are only set if any project has a past breakdown.
+func GroupCosts(out Root, keys []string) *CostGroups {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	hasPast := false
+	groups := map[string]*CostGroup{}
+
+	add := func(res Resource, past bool) {
+		if res.MonthlyCost == nil {
+			return
+		}
+
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = groupValue(key, res)
+		}
+
+		id := strings.Join(values, "\x00")
+		g, ok := groups[id]
+		if !ok {
+			g = &CostGroup{Values: values, MonthlyCost: decimalPtr(decimal.Zero)}
+			groups[id] = g
+		}
+
+		if past {
+			if g.PastMonthlyCost == nil {
+				g.PastMonthlyCost = decimalPtr(decimal.Zero)
+			}
+
+			g.PastMonthlyCost = decimalPtr(g.PastMonthlyCost.Add(*res.MonthlyCost))
+			return
+		}
+
+		g.MonthlyCost = decimalPtr(g.MonthlyCost.Add(*res.MonthlyCost))
+	}
+
+	for _, project := range out.Projects {
+		if project.PastBreakdown != nil {
+			hasPast = true
+			for _, res := range project.PastBreakdown.Resources {
+				add(res, true)
+			}
+		}
+
+		if project.Breakdown != nil {
+			for _, res := range project.Breakdown.Resources {
+				add(res, false)
+			}
+		}
+	}
+
+	list := make([]CostGroup, 0, len(groups))
+	for _, g := range groups {
+		if hasPast {
+			if g.PastMonthlyCost == nil {
+				g.PastMonthlyCost = decimalPtr(decimal.Zero)
+			}
+
+			g.DiffMonthlyCost = decimalPtr(g.MonthlyCost.Sub(*g.PastMonthlyCost))
+		} else {
+			g.PastMonthlyCost = nil
+		}
+
+		list = append(list, *g)
+	}
+
+	sortCostGroups(list)
+
+	if len(keys) > 1 {
+		list = addSubtotals(list, hasPast)
+	}
+
+	return &CostGroups{Keys: keys, Groups: list}
+}
+
+// groupValue returns the value of the group by key for the resource.
+func groupValue(key string, res Resource) string {
+	switch {
+	case strings.HasPrefix(key, groupByTagPrefix):
+		if v, ok := res.Tags[strings.TrimPrefix(key, groupByTagPrefix)]; ok && v != "" {
+			return v
+		}
+
+		return untaggedGroup
+	case key == groupByResourceType:
+		return res.ResourceType()
+	case key == groupByRegion:
+		if region := resourceRegion(res); region != "" {
+			return region
+		}
+
+		return unknownRegion
+	case key == groupByModule:
+		module := strings.TrimSuffix(moduleAddressRegex.FindString(res.Name), ".")
+		if module == "" {
+			return rootModuleGroup
+		}
+
+		return module
+	}
+
+	return ""
+}
+
+// resourceRegion returns the region of the first cost component of the
+// resource or its sub-resources that has one.
+func resourceRegion(res Resource) string {
+	for _, c := range res.CostComponents {
+		if c.Region != "" {
+			return c.Region
+		}
+	}
+
+	for _, sub := range res.SubResources {
+		if region := resourceRegion(sub); region != "" {
+			return region
+		}
+	}
+
+	return ""
+}
+
+// sortCostGroups sorts the groups by the total monthly cost of their first
+// value, and then by their own monthly cost, so that the most expensive groups
+// are first. The untagged and unknown groups are always last.
+func sortCostGroups(groups []CostGroup) {
+	firstTotals := map[string]decimal.Decimal{}
+	for _, g := range groups {
+		firstTotals[g.Values[0]] = firstTotals[g.Values[0]].Add(*g.MonthlyCost)
+	}
+
+	isFallback := func(v string) bool {
+		return v == untaggedGroup || v == unknownRegion
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Values[0] != b.Values[0] {
+			if isFallback(a.Values[0]) != isFallback(b.Values[0]) {
+				return !isFallback(a.Values[0])
+			}
+
+			ta, tb := firstTotals[a.Values[0]], firstTotals[b.Values[0]]
+			if !ta.Equal(tb) {
+				return ta.GreaterThan(tb)
+			}
+
+			return a.Values[0] < b.Values[0]
+		}
+
+		if !a.MonthlyCost.Equal(*b.MonthlyCost) {
+			return a.MonthlyCost.GreaterThan(*b.MonthlyCost)
+		}
+
+		return strings.Join(a.Values, "\x00") < strings.Join(b.Values, "\x00")
+	})
+}
+
+// addSubtotals adds a subtotal group after the groups with the same first
+// value.
+func addSubtotals(groups []CostGroup, hasPast bool) []CostGroup {
+	result := make([]CostGroup, 0, len(groups)*2)
+
+	for i := 0; i < len(groups); {
+		first := groups[i].Values[0]
+		subtotal := CostGroup{Values: []string{first}, Subtotal: true, MonthlyCost: decimalPtr(decimal.Zero)}
+		if hasPast {
+			subtotal.PastMonthlyCost = decimalPtr(decimal.Zero)
+			subtotal.DiffMonthlyCost = decimalPtr(decimal.Zero)
+		}
+
+		for ; i < len(groups) && groups[i].Values[0] == first; i++ {
+			g := groups[i]
+			result = append(result, g)
+
+			subtotal.MonthlyCost = decimalPtr(subtotal.MonthlyCost.Add(*g.MonthlyCost))
+			if hasPast {
+				subtotal.PastMonthlyCost = decimalPtr(subtotal.PastMonthlyCost.Add(*g.PastMonthlyCost))
+				subtotal.DiffMonthlyCost = decimalPtr(subtotal.DiffMonthlyCost.Add(*g.DiffMonthlyCost))
+			}
+		}
+
+		result = append(result, subtotal)
+	}
+
+	return result
+}
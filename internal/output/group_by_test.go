@@ -0,0 +1,105 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupByTestResource(name string, tags map[string]string, region string, cost int64) Resource {
+	c := decimal.NewFromInt(cost)
+	return Resource{
+		Name:           name,
+		Tags:           tags,
+		MonthlyCost:    &c,
+		CostComponents: []CostComponent{{Name: "Usage", Region: region, MonthlyCost: &c}},
+	}
+}
+
+func groupValues(groups *CostGroups) [][]string {
+	values := make([][]string, 0, len(groups.Groups))
+	for _, g := range groups.Groups {
+		values = append(values, []string{g.Label(), g.MonthlyCost.String()})
+	}
+
+	return values
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	assert.NoError(t, ValidateGroupBy([]string{"tag:team", "resource_type", "region", "module"}))
+	assert.Error(t, ValidateGroupBy([]string{"tag:"}))
+	assert.Error(t, ValidateGroupBy([]string{"team"}))
+}
+
+func TestGroupCostsByTag(t *testing.T) {
+	out := Root{Projects: []Project{
+		{Breakdown: &Breakdown{Resources: []Resource{
+			groupByTestResource("aws_instance.a", map[string]string{"team": "a"}, "us-east-1", 10),
+			groupByTestResource("aws_instance.b", map[string]string{"team": "b"}, "us-east-1", 30),
+			groupByTestResource("aws_instance.c", nil, "", 50),
+		}}},
+		{Breakdown: &Breakdown{Resources: []Resource{
+			groupByTestResource("module.db.aws_db_instance.a", map[string]string{"team": "a"}, "eu-west-1", 25),
+		}}},
+	}}
+
+	groups := GroupCosts(out, []string{"tag:team"})
+	require.NotNil(t, groups)
+	assert.False(t, groups.HasPast())
+	assert.Equal(t, [][]string{{"a", "35"}, {"b", "30"}, {"untagged", "50"}}, groupValues(groups))
+
+	groups = GroupCosts(out, []string{"module"})
+	assert.Equal(t, [][]string{{"root", "90"}, {"module.db", "25"}}, groupValues(groups))
+
+	groups = GroupCosts(out, []string{"region"})
+	assert.Equal(t, [][]string{{"us-east-1", "40"}, {"eu-west-1", "25"}, {"unknown", "50"}}, groupValues(groups))
+
+	assert.Nil(t, GroupCosts(out, nil))
+}
+
+func TestGroupCostsSubtotals(t *testing.T) {
+	out := Root{Projects: []Project{
+		{Breakdown: &Breakdown{Resources: []Resource{
+			groupByTestResource("aws_instance.a", map[string]string{"team": "a", "env": "prod"}, "", 20),
+			groupByTestResource("aws_instance.b", map[string]string{"team": "a", "env": "dev"}, "", 5),
+			groupByTestResource("aws_s3_bucket.c", map[string]string{"team": "b", "env": "prod"}, "", 10),
+		}}},
+	}}
+
+	groups := GroupCosts(out, []string{"tag:team", "resource_type"})
+	assert.Equal(t, [][]string{
+		{"a, aws_instance", "25"},
+		{"a subtotal", "25"},
+		{"b, aws_s3_bucket", "10"},
+		{"b subtotal", "10"},
+	}, groupValues(groups))
+	assert.Equal(t, []string{"a subtotal", ""}, groups.Cells(groups.Groups[1]))
+}
+
+func TestGroupCostsDiff(t *testing.T) {
+	out := Root{Projects: []Project{
+		{
+			PastBreakdown: &Breakdown{Resources: []Resource{
+				groupByTestResource("aws_instance.a", map[string]string{"team": "a"}, "", 10),
+				groupByTestResource("aws_instance.removed", map[string]string{"team": "b"}, "", 40),
+			}},
+			Breakdown: &Breakdown{Resources: []Resource{
+				groupByTestResource("aws_instance.a", map[string]string{"team": "a"}, "", 350),
+			}},
+		},
+	}}
+
+	groups := GroupCosts(out, []string{"tag:team"})
+	require.True(t, groups.HasPast())
+	require.Len(t, groups.Groups, 2)
+
+	assert.Equal(t, "a", groups.Groups[0].Label())
+	assert.Equal(t, "10", groups.Groups[0].PastMonthlyCost.String())
+	assert.Equal(t, "340", groups.Groups[0].DiffMonthlyCost.String())
+
+	assert.Equal(t, "b", groups.Groups[1].Label())
+	assert.Equal(t, "0", groups.Groups[1].MonthlyCost.String())
+	assert.Equal(t, "-40", groups.Groups[1].DiffMonthlyCost.String())
+}
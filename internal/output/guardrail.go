@@ -0,0 +1,159 @@
+package output
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// guardrailScope is the part of the estimate that a guardrail checks.
+type guardrailScope struct {
+	label           string
+	pastMonthlyCost *decimal.Decimal
+	monthlyCost     *decimal.Decimal
+	diffMonthlyCost *decimal.Decimal
+}
+
+// CheckGuardrails checks the guardrails from the config file against the
+// estimate without calling Infracost Cloud. All failures are commentable, and
+// the failures of guardrails with the block action are also blocking.
+func CheckGuardrails(out Root, guardrails []*config.Guardrail) GuardrailCheck {
+	check := GuardrailCheck{}
+	if len(guardrails) == 0 {
+		return check
+	}
+
+	check.TotalChecked = int64(len(guardrails))
+	check.Comment = true
+
+	for _, g := range guardrails {
+		for _, scope := range guardrailScopes(out, g) {
+			for _, failure := range checkGuardrailScope(out.Currency, g, scope) {
+				check.CommentableFailures = append(check.CommentableFailures, failure)
+				if g.Blocks() {
+					check.BlockingFailures = append(check.BlockingFailures, failure)
+				}
+			}
+		}
+	}
+
+	return check
+}
+
+// Add returns the guardrail check combined with another one, e.g. the local
+// guardrails with the ones returned from Infracost Cloud.
+func (g GuardrailCheck) Add(other GuardrailCheck) GuardrailCheck {
+	return GuardrailCheck{
+		TotalChecked:        g.TotalChecked + other.TotalChecked,
+		Comment:             g.Comment || other.Comment,
+		CommentableFailures: append(append(GuardrailFailures{}, g.CommentableFailures...), other.CommentableFailures...),
+		BlockingFailures:    append(append(GuardrailFailures{}, g.BlockingFailures...), other.BlockingFailures...),
+	}
+}
+
+// guardrailScopes returns the costs that the guardrail should be checked
+// against. This is the total of all projects unless the guardrail is limited
+// to projects, a tag or resource types.
+func guardrailScopes(out Root, g *config.Guardrail) []guardrailScope {
+	switch {
+	case g.Project != "":
+		var scopes []guardrailScope
+		for _, project := range out.Projects {
+			if ok, _ := path.Match(g.Project, project.Name); !ok {
+				continue
+			}
+
+			scope := guardrailScope{label: fmt.Sprintf("project %s", project.Label())}
+			if project.PastBreakdown != nil {
+				scope.pastMonthlyCost = project.PastBreakdown.TotalMonthlyCost
+			}
+			if project.Breakdown != nil {
+				scope.monthlyCost = project.Breakdown.TotalMonthlyCost
+			}
+			if project.Diff != nil {
+				scope.diffMonthlyCost = project.Diff.TotalMonthlyCost
+			}
+
+			scopes = append(scopes, scope)
+		}
+
+		return scopes
+	case g.Tag != "":
+		key, value, hasValue := strings.Cut(g.Tag, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var scopes []guardrailScope
+		for _, group := range GroupCosts(out, []string{groupByTagPrefix + key}).Groups {
+			tagValue := group.Values[0]
+			if tagValue == untaggedGroup || (hasValue && tagValue != value) {
+				continue
+			}
+
+			scopes = append(scopes, costGroupScope(fmt.Sprintf("tag %s=%s", key, tagValue), group))
+		}
+
+		return scopes
+	case g.ResourceType != "":
+		var scopes []guardrailScope
+		for _, group := range GroupCosts(out, []string{groupByResourceType}).Groups {
+			if ok, _ := path.Match(g.ResourceType, group.Values[0]); !ok {
+				continue
+			}
+
+			scopes = append(scopes, costGroupScope(fmt.Sprintf("resource type %s", group.Values[0]), group))
+		}
+
+		return scopes
+	}
+
+	return []guardrailScope{{
+		label:           "all projects",
+		pastMonthlyCost: out.PastTotalMonthlyCost,
+		monthlyCost:     out.TotalMonthlyCost,
+		diffMonthlyCost: out.DiffTotalMonthlyCost,
+	}}
+}
+
+func costGroupScope(label string, group CostGroup) guardrailScope {
+	return guardrailScope{
+		label:           label,
+		pastMonthlyCost: group.PastMonthlyCost,
+		monthlyCost:     group.MonthlyCost,
+		diffMonthlyCost: group.DiffMonthlyCost,
+	}
+}
+
+// checkGuardrailScope returns a failure for each threshold of the guardrail
+// that the costs of the scope are above. Diff thresholds are skipped if there
+// is no diff, and the percentage is skipped if there is no past cost.
+func checkGuardrailScope(currency string, g *config.Guardrail, scope guardrailScope) []string {
+	var failures []string
+
+	if g.TotalMonthlyCost != nil && scope.monthlyCost != nil {
+		threshold := decimal.NewFromFloat(*g.TotalMonthlyCost)
+		if scope.monthlyCost.GreaterThan(threshold) {
+			failures = append(failures, fmt.Sprintf("Guardrail %q: monthly cost of %s is %s, above %s", g.Name, scope.label, FormatCost2DP(currency, scope.monthlyCost), FormatCost2DP(currency, &threshold)))
+		}
+	}
+
+	if g.DiffMonthlyCost != nil && scope.diffMonthlyCost != nil {
+		threshold := decimal.NewFromFloat(*g.DiffMonthlyCost)
+		if scope.diffMonthlyCost.GreaterThan(threshold) {
+			failures = append(failures, fmt.Sprintf("Guardrail %q: monthly cost of %s increases by %s, above %s", g.Name, scope.label, FormatCost2DP(currency, scope.diffMonthlyCost), FormatCost2DP(currency, &threshold)))
+		}
+	}
+
+	if g.DiffPercentage != nil && scope.diffMonthlyCost != nil && scope.pastMonthlyCost != nil && scope.pastMonthlyCost.IsPositive() {
+		percentage := scope.diffMonthlyCost.Div(*scope.pastMonthlyCost).Mul(decimal.NewFromInt(100))
+		if percentage.GreaterThan(decimal.NewFromFloat(*g.DiffPercentage)) {
+			failures = append(failures, fmt.Sprintf("Guardrail %q: monthly cost of %s increases by %s%%, above %s%%", g.Name, scope.label, percentage.Round(1).String(), strconv.FormatFloat(*g.DiffPercentage, 'f', -1, 64)))
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func guardrailTestRoot() Root {
+	past := decimal.NewFromInt(100)
+	total := decimal.NewFromInt(180)
+	diff := decimal.NewFromInt(80)
+
+	return Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: &past,
+		TotalMonthlyCost:     &total,
+		DiffTotalMonthlyCost: &diff,
+		Projects: []Project{
+			{
+				Name: "infra/prod",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: &past, Resources: []Resource{
+					groupByTestResource("aws_instance.a", map[string]string{"team": "a"}, "", 100),
+				}},
+				Breakdown: &Breakdown{TotalMonthlyCost: &total, Resources: []Resource{
+					groupByTestResource("aws_instance.a", map[string]string{"team": "a"}, "", 100),
+					groupByTestResource("aws_db_instance.b", map[string]string{"team": "b"}, "", 80),
+				}},
+				Diff: &Breakdown{TotalMonthlyCost: &diff},
+			},
+		},
+	}
+}
+
+func TestCheckGuardrails(t *testing.T) {
+	check := CheckGuardrails(guardrailTestRoot(), []*config.Guardrail{
+		{Name: "Total", TotalMonthlyCost: float64Ptr(150), DiffPercentage: float64Ptr(50), Action: config.GuardrailActionBlock},
+		{Name: "Prod", Project: "infra/*", DiffMonthlyCost: float64Ptr(100), Action: config.GuardrailActionBlock},
+		{Name: "Team b", Tag: "team=b", DiffMonthlyCost: float64Ptr(50), Action: config.GuardrailActionWarn},
+		{Name: "Databases", ResourceType: "aws_db_*", TotalMonthlyCost: float64Ptr(100), Action: config.GuardrailActionWarn},
+	})
+
+	assert.Equal(t, int64(4), check.TotalChecked)
+	assert.True(t, check.Comment)
+	assert.Equal(t, GuardrailFailures{
+		`Guardrail "Total": monthly cost of all projects is $180.00, above $150.00`,
+		`Guardrail "Total": monthly cost of all projects increases by 80%, above 50%`,
+		`Guardrail "Team b": monthly cost of tag team=b increases by $80.00, above $50.00`,
+	}, check.CommentableFailures)
+	assert.Equal(t, check.CommentableFailures[:2], check.BlockingFailures)
+}
+
+func TestCheckGuardrailsWithoutPastBreakdown(t *testing.T) {
+	total := decimal.NewFromInt(180)
+	out := Root{Currency: "USD", TotalMonthlyCost: &total}
+
+	check := CheckGuardrails(out, []*config.Guardrail{
+		{Name: "Increase", DiffMonthlyCost: float64Ptr(10), DiffPercentage: float64Ptr(10), Action: config.GuardrailActionBlock},
+	})
+
+	assert.True(t, check.Comment)
+	assert.Empty(t, check.CommentableFailures)
+	assert.Empty(t, check.BlockingFailures)
+
+	assert.Equal(t, GuardrailCheck{}, CheckGuardrails(out, nil))
+}
+
+func TestGuardrailCheckAdd(t *testing.T) {
+	cloud := GuardrailCheck{TotalChecked: 1, BlockingFailures: GuardrailFailures{"cloud"}}
+	local := GuardrailCheck{TotalChecked: 2, Comment: true, CommentableFailures: GuardrailFailures{"local"}}
+
+	assert.Equal(t, GuardrailCheck{
+		TotalChecked:        3,
+		Comment:             true,
+		CommentableFailures: GuardrailFailures{"local"},
+		BlockingFailures:    GuardrailFailures{"cloud"},
+	}, cloud.Add(local))
+}
@@ -121,6 +121,10 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 		diffMsg = ui.StripColor(string(diff))
 	}
 
+	if len(opts.GroupBy) > 0 && out.CostGroups == nil {
+		out.CostGroups = GroupCosts(out, opts.GroupBy)
+	}
+
 	hasModulePath, hasWorkspace := calculateMetadataToDisplay(out.Projects)
 
 	var buf bytes.Buffer
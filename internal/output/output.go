@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
@@ -32,6 +34,7 @@ type Root struct {
 	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
 	TimeGenerated        time.Time        `json:"timeGenerated"`
 	Summary              *Summary         `json:"summary"`
+	CostGroups           *CostGroups      `json:"costGroups,omitempty"`
 	FullSummary          *Summary         `json:"-"`
 	IsCIRun              bool             `json:"-"`
 }
@@ -80,12 +83,31 @@ func convertOutputResources(outResources []Resource) []*schema.Resource {
 			HourlyCost:     resource.HourlyCost,
 			MonthlyCost:    resource.MonthlyCost,
 			ResourceType:   resource.ResourceType(),
+			Metadata:       convertMetadata(resource.Metadata),
 		}
 	}
 
 	return resources
 }
 
+func convertMetadata(metadata map[string]interface{}) map[string]gjson.Result {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	result := make(map[string]gjson.Result, len(metadata))
+	for k, v := range metadata {
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+
+		result[k] = gjson.ParseBytes(b)
+	}
+
+	return result
+}
+
 func convertCostComponents(outComponents []CostComponent) []*schema.CostComponent {
 	components := make([]*schema.CostComponent, len(outComponents))
 
@@ -127,6 +149,7 @@ func convertActualCosts(outActualCosts []ActualCosts) []*schema.ActualCosts {
 type Projects []Project
 
 var exampleProjectsRegex = regexp.MustCompile(`^infracost\/(infracost\/examples|example-terraform)\/`)
+var addressIndexRegex = regexp.MustCompile(`\[[^\]]*\]`)
 
 func (r *Root) ExampleProjectName() string {
 	if len(r.Projects) == 0 {
@@ -192,6 +215,11 @@ type CostComponent struct {
 	MonthlyCost     *decimal.Decimal   `json:"monthlyCost"`
 	Metric          string             `json:"metric"`
 	TierData        []schema.PriceTier `json:"tiers,omitempty"`
+	// VendorName, Service and Region are from the product filter used to
+	// price the cost component.
+	VendorName string `json:"vendorName,omitempty"`
+	Service    string `json:"service,omitempty"`
+	Region     string `json:"region,omitempty"`
 }
 
 type ActualCosts struct {
@@ -212,8 +240,23 @@ type Resource struct {
 	SubResources   []Resource             `json:"subresources,omitempty"`
 }
 
+// PreviousName returns the name the resource had before it was moved, e.g.
+// by a Terraform moved block.
+func (r Resource) PreviousName() string {
+	prev, _ := r.Metadata["previousAddress"].(string)
+	return prev
+}
+
+// IsImported returns true if the resource is being imported, e.g. by a
+// Terraform import block, rather than being created.
+func (r Resource) IsImported() bool {
+	imported, _ := r.Metadata["imported"].(bool)
+	return imported
+}
+
 func (r Resource) ResourceType() string {
-	pieces := strings.Split(r.Name, ".")
+	// Remove the indexes first as for_each keys can contain dots.
+	pieces := strings.Split(addressIndexRegex.ReplaceAllString(r.Name, ""), ".")
 
 	if len(pieces) >= 2 {
 		return pieces[len(pieces)-2]
@@ -257,6 +300,11 @@ type Options struct {
 	GuardrailCheck    GuardrailCheck
 	diffMsg           string
 	CurrencyFormat    string
+	// GroupBy are the keys that the costs are grouped by, see GroupCosts.
+	GroupBy []string
+	// SARIFCostThreshold is the monthly cost above which unchanged resources are
+	// reported in the SARIF output. DefaultSARIFCostThreshold is used if it's nil.
+	SARIFCostThreshold *decimal.Decimal
 }
 
 // PolicyCheck holds information if a given run has any policy checks enabled.
@@ -265,6 +313,11 @@ type PolicyCheck struct {
 	Enabled  bool
 	Failures PolicyCheckFailures
 	Passed   []string
+	// Warnings are the messages of the warn rules that failed. Unlike Failures
+	// they are only reported and don't fail the run.
+	Warnings []string
+	// Infos are the messages of the info rules.
+	Infos []string
 }
 
 // HasFailed returns if the PolicyCheck has any cost policy failures
@@ -391,7 +444,7 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 		if c.PriceFilter != nil && c.PriceFilter.Unit != nil {
 			price_metric = *c.PriceFilter.Unit
 		}
-		comps = append(comps, CostComponent{
+		comp := CostComponent{
 			Name:            c.Name,
 			Unit:            c.Unit,
 			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
@@ -401,7 +454,13 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 			MonthlyCost:     c.MonthlyCost,
 			Metric:          price_metric,
 			TierData:        c.PriceTiers(),
-		})
+		}
+		if f := c.ProductFilter; f != nil {
+			comp.VendorName = derefString(f.VendorName)
+			comp.Service = derefString(f.Service)
+			comp.Region = derefString(f.Region)
+		}
+		comps = append(comps, comp)
 	}
 	return comps
 }
@@ -854,6 +913,14 @@ func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
 }
 
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
 func mergeCounts(c1 *map[string]int, c2 *map[string]int) *map[string]int {
 	if c1 == nil && c2 == nil {
 		return nil
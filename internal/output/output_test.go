@@ -32,3 +32,24 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestResourceType(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "aws_instance.web", want: "aws_instance"},
+		{name: "aws_instance.web[0]", want: "aws_instance"},
+		{name: `aws_instance.instance_named["test.1"]`, want: "aws_instance"},
+		{name: `module.app["eu.west"].aws_db_instance.db`, want: "aws_db_instance"},
+		{name: "module.db.module.db_instance.aws_db_instance.this[0]", want: "aws_db_instance"},
+		{name: "data.aws_ami.ubuntu", want: "aws_ami"},
+		{name: "web", want: "web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resource{Name: tt.name}.ResourceType())
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifRuleCostChange        = "cost-change"
+	sarifRuleExpensiveResource = "expensive-resource"
+	sarifRulePolicyCheck       = "policy-check"
+	sarifRuleGuardrailCheck    = "guardrail-check"
+)
+
+// DefaultSARIFCostThreshold is the monthly cost above which unchanged
+// resources are reported as expensive in the SARIF output.
+var DefaultSARIFCostThreshold = decimal.NewFromInt(100)
+
+var sarifRules = []sarifRule{
+	{
+		ID:               sarifRuleCostChange,
+		Name:             "CostChange",
+		ShortDescription: sarifMessage{Text: "The monthly cost of the resource changes"},
+	},
+	{
+		ID:               sarifRuleExpensiveResource,
+		Name:             "ExpensiveResource",
+		ShortDescription: sarifMessage{Text: "The monthly cost of the resource is above the threshold"},
+	},
+	{
+		ID:               sarifRulePolicyCheck,
+		Name:             "PolicyCheck",
+		ShortDescription: sarifMessage{Text: "A cost policy check failed"},
+	},
+	{
+		ID:               sarifRuleGuardrailCheck,
+		Name:             "GuardrailCheck",
+		ShortDescription: sarifMessage{Text: "A guardrail check failed"},
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name,omitempty"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ToSARIF returns Root r as a SARIF 2.1.0 log, so that cost changes can be
+// shown as code scanning alerts. There is one result for each resource whose
+// cost changes or whose monthly cost is above opts.SARIFCostThreshold, pinned
+// to the block that defines the resource when it is known. Cost policies and
+// failed policy and guardrail checks are also added as results.
+func ToSARIF(r Root, opts Options) ([]byte, error) {
+	threshold := DefaultSARIFCostThreshold
+	if opts.SARIFCostThreshold != nil {
+		threshold = *opts.SARIFCostThreshold
+	}
+
+	rules := append([]sarifRule{}, sarifRules...)
+	seenRules := make(map[string]bool)
+	results := []sarifResult{}
+
+	for _, project := range r.Projects {
+		results = append(results, sarifResourceResults(project, r.Currency, threshold)...)
+
+		if project.Metadata == nil {
+			continue
+		}
+
+		resources := make(map[string]Resource)
+		if project.Breakdown != nil {
+			for _, res := range project.Breakdown.Resources {
+				resources[res.Name] = res
+			}
+		}
+
+		for _, policy := range project.Metadata.Policies {
+			if !seenRules[policy.ID] {
+				seenRules[policy.ID] = true
+				rule := sarifRule{ID: policy.ID, ShortDescription: sarifMessage{Text: policy.Title}}
+				if policy.Description != "" {
+					rule.FullDescription = &sarifMessage{Text: policy.Description}
+				}
+				rules = append(rules, rule)
+			}
+
+			msg := fmt.Sprintf("%s: %s", policy.Address, policy.Title)
+			if policy.Suggested != "" {
+				msg = fmt.Sprintf("%s, consider %s", msg, policy.Suggested)
+			}
+
+			properties := map[string]interface{}{"project": project.Name}
+			if policy.Cost != nil {
+				properties["monthlySavings"] = policy.Cost
+				properties["currency"] = r.Currency
+			}
+
+			results = append(results, sarifResult{
+				RuleID:     policy.ID,
+				Level:      "warning",
+				Message:    sarifMessage{Text: msg},
+				Locations:  sarifLocations(project, resources[policy.Address], policy.Address),
+				Properties: properties,
+			})
+		}
+	}
+
+	for _, f := range opts.PolicyChecks.Failures {
+		results = append(results, sarifResult{
+			RuleID:  sarifRulePolicyCheck,
+			Level:   "error",
+			Message: sarifMessage{Text: f},
+		})
+	}
+
+	for _, f := range opts.PolicyChecks.Warnings {
+		results = append(results, sarifResult{
+			RuleID:  sarifRulePolicyCheck,
+			Level:   "warning",
+			Message: sarifMessage{Text: f},
+		})
+	}
+
+	for _, f := range opts.PolicyChecks.Infos {
+		results = append(results, sarifResult{
+			RuleID:  sarifRulePolicyCheck,
+			Level:   "note",
+			Message: sarifMessage{Text: f},
+		})
+	}
+
+	failures := opts.GuardrailCheck.BlockingFailures
+	for _, f := range opts.GuardrailCheck.CommentableFailures {
+		if !contains(failures, f) {
+			failures = append(failures, f)
+		}
+	}
+
+	for _, f := range failures {
+		level := "warning"
+		if contains(opts.GuardrailCheck.BlockingFailures, f) {
+			level = "error"
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  sarifRuleGuardrailCheck,
+			Level:   level,
+			Message: sarifMessage{Text: f},
+		})
+	}
+
+	out := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "infracost",
+						Version:        version.Version,
+						InformationURI: "https://www.infracost.io",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// sarifResourceResults returns a result for each resource of the project
+// whose cost changes, including removed resources, and for each unchanged
+// resource whose monthly cost is above the threshold.
+func sarifResourceResults(project Project, currency string, threshold decimal.Decimal) []sarifResult {
+	var results []sarifResult
+
+	past := make(map[string]Resource)
+	if project.PastBreakdown != nil {
+		for _, res := range project.PastBreakdown.Resources {
+			past[res.Name] = res
+		}
+	}
+
+	diffs := make(map[string]Resource)
+	if project.Diff != nil {
+		for _, res := range project.Diff.Resources {
+			diffs[res.Name] = res
+		}
+	}
+
+	var current []Resource
+	if project.Breakdown != nil {
+		current = project.Breakdown.Resources
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, res := range current {
+		seen[res.Name] = true
+
+		pastRes, hasPast := past[res.Name]
+		if !hasPast && res.PreviousName() != "" {
+			pastRes, hasPast = past[res.PreviousName()]
+		}
+
+		var pastCost *decimal.Decimal
+		if hasPast {
+			pastCost = pastRes.MonthlyCost
+		}
+
+		if diff, ok := diffs[res.Name]; ok {
+			results = append(results, sarifCostChangeResult(project, currency, res, pastCost, res.MonthlyCost, diff.MonthlyCost))
+			continue
+		}
+
+		if res.MonthlyCost != nil && res.MonthlyCost.GreaterThan(threshold) {
+			results = append(results, sarifResult{
+				RuleID: sarifRuleExpensiveResource,
+				Level:  "note",
+				Message: sarifMessage{Text: fmt.Sprintf("%s costs %s per month, above the %s threshold",
+					res.Name, formatCost(currency, res.MonthlyCost), formatCost(currency, &threshold))},
+				Locations:  sarifLocations(project, res, res.Name),
+				Properties: sarifCostProperties(project, currency, pastCost, res.MonthlyCost, nil),
+			})
+		}
+	}
+
+	// Removed resources are only in the past breakdown, so they're reported
+	// against the block they were defined in before the change.
+	if project.Diff != nil {
+		for _, diff := range project.Diff.Resources {
+			if seen[diff.Name] {
+				continue
+			}
+
+			pastRes := past[diff.Name]
+			results = append(results, sarifCostChangeResult(project, currency, pastRes, pastRes.MonthlyCost, nil, diff.MonthlyCost))
+		}
+	}
+
+	return results
+}
+
+func sarifCostChangeResult(project Project, currency string, res Resource, pastCost, cost, diffCost *decimal.Decimal) sarifResult {
+	level := "note"
+	if diffCost != nil && diffCost.IsPositive() {
+		level = "warning"
+	}
+
+	var msg string
+	switch {
+	case cost == nil && pastCost != nil:
+		msg = fmt.Sprintf("%s is removed, changing the monthly cost by %s", res.Name, formatCostChange(currency, diffCost))
+	case diffCost == nil:
+		msg = fmt.Sprintf("%s changes, the monthly cost depends on usage", res.Name)
+	default:
+		zero := decimal.Zero
+		if pastCost == nil {
+			pastCost = &zero
+		}
+
+		msg = fmt.Sprintf("%s changes the monthly cost by %s%s", res.Name, formatCostChange(currency, diffCost), formatCostChangeDetails(currency, pastCost, cost))
+	}
+
+	return sarifResult{
+		RuleID:     sarifRuleCostChange,
+		Level:      level,
+		Message:    sarifMessage{Text: msg},
+		Locations:  sarifLocations(project, res, res.Name),
+		Properties: sarifCostProperties(project, currency, pastCost, cost, diffCost),
+	}
+}
+
+func sarifCostProperties(project Project, currency string, pastCost, cost, diffCost *decimal.Decimal) map[string]interface{} {
+	properties := map[string]interface{}{
+		"project":  project.Name,
+		"currency": currency,
+	}
+
+	if pastCost != nil {
+		properties["pastMonthlyCost"] = pastCost
+	}
+
+	if cost != nil {
+		properties["monthlyCost"] = cost
+	}
+
+	if diffCost != nil {
+		properties["diffMonthlyCost"] = diffCost
+	}
+
+	return properties
+}
+
+// sarifLocations returns the location of the block that defines the resource,
+// using the filename and line range in the resource metadata. Resources
+// without a filename, e.g. ones from a plan JSON, only have a logical
+// location.
+func sarifLocations(project Project, res Resource, address string) []sarifLocation {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: address, Kind: "resource"}},
+	}
+
+	filename, _ := res.Metadata["filename"].(string)
+	if filename != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sarifURI(project.Metadata, filename)},
+		}
+
+		startLine := metadataInt(res.Metadata["startLine"])
+		if startLine > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{
+				StartLine: startLine,
+				EndLine:   metadataInt(res.Metadata["endLine"]),
+			}
+		}
+	}
+
+	return []sarifLocation{loc}
+}
+
+// sarifURI returns the filename relative to the root of the repo when the
+// filename is inside the project path, since code scanning resolves URIs
+// against the repo root.
+func sarifURI(metadata *schema.ProjectMetadata, filename string) string {
+	if metadata != nil && filepath.IsAbs(filename) && filepath.IsAbs(metadata.Path) {
+		if rel, err := filepath.Rel(metadata.Path, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = filepath.Join(metadata.VCSSubPath, rel)
+		}
+	}
+
+	return filepath.ToSlash(filename)
+}
+
+// metadataInt returns the int value of a number in the resource metadata,
+// which is a float64 when the metadata has been read from a JSON file.
+func metadataInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToSARIF(t *testing.T) {
+	resource := func(name string, cost int64, metadata map[string]interface{}) Resource {
+		return Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost)), Metadata: metadata}
+	}
+
+	webMetadata := map[string]interface{}{"filename": "/repo/infra/main.tf", "startLine": float64(3), "endLine": float64(9)}
+	dbMetadata := map[string]interface{}{"filename": "/repo/infra/db.tf", "startLine": float64(1), "endLine": float64(12)}
+
+	out := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infra",
+				Metadata: &schema.ProjectMetadata{
+					Path:       "/repo/infra",
+					VCSSubPath: "infra",
+					Policies: schema.Policies{
+						{ID: "aws_instance_gen", Title: "Use a newer instance generation", Address: "aws_instance.web", Suggested: "m5.large"},
+					},
+				},
+				PastBreakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 50, webMetadata),
+					resource("aws_db_instance.db", 400, dbMetadata),
+					resource("aws_eip.old", 4, nil),
+				}},
+				Breakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 70, webMetadata),
+					resource("aws_db_instance.db", 400, dbMetadata),
+					resource("aws_s3_bucket.logs", 1, nil),
+				}},
+				Diff: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 20, webMetadata),
+					resource("aws_eip.old", -4, nil),
+				}},
+			},
+		},
+	}
+
+	b, err := ToSARIF(out, Options{
+		GuardrailCheck: GuardrailCheck{BlockingFailures: GuardrailFailures{"Monthly cost increase above $10"}},
+	})
+	require.NoError(t, err)
+
+	var actual sarifLog
+	require.NoError(t, json.Unmarshal(b, &actual))
+	require.Len(t, actual.Runs, 1)
+
+	results := actual.Runs[0].Results
+	require.Len(t, results, 5)
+
+	web := results[0]
+	assert.Equal(t, sarifRuleCostChange, web.RuleID)
+	assert.Equal(t, "warning", web.Level)
+	assert.Equal(t, "aws_instance.web changes the monthly cost by +$20.00 ($50.00 → $70.00)", web.Message.Text)
+	assert.Equal(t, "infra/main.tf", web.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 3, EndLine: 9}, web.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "20", web.Properties["diffMonthlyCost"])
+	assert.Equal(t, "50", web.Properties["pastMonthlyCost"])
+
+	db := results[1]
+	assert.Equal(t, sarifRuleExpensiveResource, db.RuleID)
+	assert.Equal(t, "infra/db.tf", db.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	eip := results[2]
+	assert.Equal(t, sarifRuleCostChange, eip.RuleID)
+	assert.Equal(t, "note", eip.Level)
+	assert.Nil(t, eip.Locations[0].PhysicalLocation)
+	assert.Equal(t, "aws_eip.old", eip.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	policy := results[3]
+	assert.Equal(t, "aws_instance_gen", policy.RuleID)
+	assert.Equal(t, "infra/main.tf", policy.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	guardrail := results[4]
+	assert.Equal(t, sarifRuleGuardrailCheck, guardrail.RuleID)
+	assert.Equal(t, "error", guardrail.Level)
+	assert.Empty(t, guardrail.Locations)
+
+	var ruleIDs []string
+	for _, rule := range actual.Runs[0].Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{sarifRuleCostChange, sarifRuleExpensiveResource, sarifRulePolicyCheck, sarifRuleGuardrailCheck, "aws_instance_gen"}, ruleIDs)
+}
@@ -12,6 +12,21 @@ import (
 )
 
 func slackSummaryBlock(name string, currency string, cost, pastCost, diffCost *decimal.Decimal) []*slack.TextBlockObject {
+	return []*slack.TextBlockObject{
+		{
+			Type: slack.PlainTextType,
+			Text: name,
+		},
+		{
+			Type: slack.PlainTextType,
+			Text: formatCostChangeSummary(currency, cost, pastCost, diffCost),
+		},
+	}
+}
+
+// formatCostChangeSummary returns the cost change with the past and new costs,
+// e.g. +$10.00 ($20.00 → $30.00), for the summaries of chat messages.
+func formatCostChangeSummary(currency string, cost, pastCost, diffCost *decimal.Decimal) string {
 	if cost == nil {
 		cost = decimalPtr(decimal.Zero)
 	}
@@ -29,16 +44,7 @@ func slackSummaryBlock(name string, currency string, cost, pastCost, diffCost *d
 		pastCost = decimalPtr(decimal.Zero)
 	}
 
-	return []*slack.TextBlockObject{
-		{
-			Type: slack.PlainTextType,
-			Text: name,
-		},
-		{
-			Type: slack.PlainTextType,
-			Text: fmt.Sprintf("%s%s", formatCostChange(currency, diffCost), formatCostChangeDetails(currency, pastCost, cost)),
-		},
-	}
+	return fmt.Sprintf("%s%s", formatCostChange(currency, diffCost), formatCostChangeDetails(currency, pastCost, cost))
 }
 
 func slackProjectSummaryBlock(project Project, currency string) []*slack.TextBlockObject {
@@ -67,6 +67,14 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		}
 	}
 
+	if comparison := tableForEnvironments(out); comparison != "" {
+		s += "──────────────────────────────────\n" + comparison
+	}
+
+	if groups := tableForCostGroups(out); groups != "" {
+		s += "──────────────────────────────────\n" + groups
+	}
+
 	if includeProjectTotals {
 		s += "\n"
 	}
@@ -88,6 +96,73 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 	return []byte(s), nil
 }
 
+// tableForEnvironments returns a table that compares the monthly cost of the
+// projects that are evaluated for each environment of a project matrix, with a
+// row for each matrix project and a column for each environment. It returns an
+// empty string if there are no matrix projects.
+func tableForEnvironments(out Root) string {
+	var envs []string
+	var names []string
+	costs := map[string]map[string]string{}
+
+	for _, project := range out.Projects {
+		env := project.Metadata.Environment
+		if env == "" || project.Breakdown == nil {
+			continue
+		}
+
+		if !contains(envs, env) {
+			envs = append(envs, env)
+		}
+
+		name := strings.TrimSuffix(project.Name, "-"+env)
+		if _, ok := costs[name]; !ok {
+			names = append(names, name)
+			costs[name] = map[string]string{}
+		}
+
+		costs[name][env] = FormatCost2DP(out.Currency, project.Breakdown.TotalMonthlyCost)
+	}
+
+	if len(envs) == 0 {
+		return ""
+	}
+
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+
+	headers := table.Row{ui.UnderlineString("Project")}
+	columns := []table.ColumnConfig{{Number: 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft}}
+	for i, env := range envs {
+		headers = append(headers, ui.UnderlineString(env))
+		columns = append(columns, table.ColumnConfig{Number: i + 2, Align: text.AlignRight, AlignHeader: text.AlignRight})
+	}
+
+	t.AppendHeader(headers)
+	t.SetColumnConfigs(columns)
+
+	for _, name := range names {
+		row := table.Row{name}
+		for _, env := range envs {
+			cost, ok := costs[name][env]
+			if !ok {
+				cost = "-"
+			}
+
+			row = append(row, cost)
+		}
+
+		t.AppendRow(row)
+	}
+
+	title := formatTitleWithCurrency("Environment comparison (monthly cost)", out.Currency)
+	return fmt.Sprintf("%s\n\n%s\n", ui.BoldString(title), t.Render())
+}
+
 func tableForBreakdown(currency string, breakdown Breakdown, fields []string, includeTotal bool) string {
 	t := table.NewWriter()
 	t.Style().Options.DrawBorder = false
@@ -355,3 +430,68 @@ func filterZeroValResources(resources []Resource, resourceName string) []Resourc
 	}
 	return filteredResources
 }
+
+// tableForCostGroups returns a table of the monthly cost of each cost group,
+// with a column for each group by key. The previous and diff columns are only
+// shown when the costs are compared to a past breakdown. It returns an empty
+// string if the costs aren't grouped.
+func tableForCostGroups(out Root) string {
+	if out.CostGroups == nil || len(out.CostGroups.Groups) == 0 {
+		return ""
+	}
+
+	hasPast := out.CostGroups.HasPast()
+
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+
+	headers := table.Row{}
+	columns := []table.ColumnConfig{}
+	for i, key := range out.CostGroups.Keys {
+		headers = append(headers, ui.UnderlineString(key))
+		columns = append(columns, table.ColumnConfig{Number: i + 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft})
+	}
+
+	costHeaders := []string{"Monthly cost"}
+	if hasPast {
+		costHeaders = []string{"Previous", "New", "Diff"}
+	}
+
+	for _, h := range costHeaders {
+		headers = append(headers, ui.UnderlineString(h))
+		columns = append(columns, table.ColumnConfig{Number: len(columns) + 1, Align: text.AlignRight, AlignHeader: text.AlignRight})
+	}
+
+	t.AppendHeader(headers)
+	t.SetColumnConfigs(columns)
+
+	for _, g := range out.CostGroups.Groups {
+		row := table.Row{}
+		for i, cell := range out.CostGroups.Cells(g) {
+			if g.Subtotal && i == 0 {
+				cell = ui.BoldString(cell)
+			}
+
+			row = append(row, cell)
+		}
+
+		if hasPast {
+			row = append(row,
+				FormatCost2DP(out.Currency, g.PastMonthlyCost),
+				FormatCost2DP(out.Currency, g.MonthlyCost),
+				formatCostChange(out.Currency, g.DiffMonthlyCost),
+			)
+		} else {
+			row = append(row, FormatCost2DP(out.Currency, g.MonthlyCost))
+		}
+
+		t.AppendRow(row)
+	}
+
+	title := formatTitleWithCurrency("Monthly cost by "+strings.Join(out.CostGroups.Keys, ", "), out.Currency)
+	return fmt.Sprintf("%s\n\n%s\n", ui.BoldString(title), t.Render())
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func TestTableForEnvironments(t *testing.T) {
+	project := func(name, env string, cost int64) Project {
+		return Project{
+			Name:      name,
+			Metadata:  &schema.ProjectMetadata{Environment: env},
+			Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(cost))},
+		}
+	}
+
+	out := Root{
+		Currency: "USD",
+		Projects: []Project{
+			project("app-dev", "dev", 10),
+			project("app-prod", "prod", 100),
+			project("network-prod", "prod", 50),
+			project("dns", "", 1),
+		},
+	}
+
+	actual := ui.StripColor(tableForEnvironments(out))
+	expected := "Environment comparison (monthly cost)\n\n" +
+		" Project     dev     prod \n" +
+		" app      $10.00  $100.00 \n" +
+		" network       -   $50.00 \n"
+	assert.Equal(t, expected, actual)
+
+	assert.Empty(t, tableForEnvironments(Root{Projects: []Project{project("dns", "", 1)}}))
+}
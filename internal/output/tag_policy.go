@@ -0,0 +1,53 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// CheckTagPolicy checks that the resources of each project that have a monthly
+// cost have the tags required by the tag policy. Each resource that is missing
+// a required tag, or has a value that isn't allowed, is a failure.
+func CheckTagPolicy(out Root, policy *config.TagPolicy) PolicyCheck {
+	checks := PolicyCheck{Enabled: true}
+	if policy == nil {
+		return checks
+	}
+
+	for _, project := range out.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, res := range project.Breakdown.Resources {
+			if res.MonthlyCost == nil || res.MonthlyCost.IsZero() {
+				continue
+			}
+
+			for _, tag := range policy.RequiredTags {
+				if !tag.AppliesTo(res.ResourceType()) {
+					continue
+				}
+
+				value, ok := res.Tags[tag.Key]
+				if !ok {
+					checks.Failures = append(checks.Failures, fmt.Sprintf("%s in project %s is missing required tag %s", res.Name, project.Label(), tag.Key))
+					continue
+				}
+
+				if !tag.Allows(value) {
+					checks.Failures = append(checks.Failures, fmt.Sprintf("%s in project %s has tag %s=%s that does not match %s", res.Name, project.Label(), tag.Key, value, tag.AllowedValues))
+				}
+			}
+		}
+	}
+
+	if !checks.HasFailed() {
+		for _, tag := range policy.RequiredTags {
+			checks.Passed = append(checks.Passed, fmt.Sprintf("Resources have required tag %s", tag.Key))
+		}
+	}
+
+	return checks
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestCheckTagPolicy(t *testing.T) {
+	policy := &config.TagPolicy{RequiredTags: []*config.RequiredTag{
+		{Key: "cost-center", AllowedValues: "^CC-[0-9]+$"},
+		{Key: "owner", ResourceTypes: []string{"aws_db_*"}},
+	}}
+
+	cost := decimal.NewFromInt(10)
+	out := Root{Projects: []Project{
+		{
+			Name: "infra",
+			Breakdown: &Breakdown{Resources: []Resource{
+				{Name: "aws_instance.tagged", Tags: map[string]string{"cost-center": "CC-1"}, MonthlyCost: &cost},
+				{Name: `aws_instance.named["a.b"]`, MonthlyCost: &cost},
+				{Name: "aws_db_instance.db", Tags: map[string]string{"cost-center": "finance"}, MonthlyCost: &cost},
+				{Name: "aws_eip.free", MonthlyCost: decimalPtr(decimal.Zero)},
+				{Name: "aws_lambda_function.usage"},
+			}},
+		},
+	}}
+
+	checks := CheckTagPolicy(out, policy)
+	assert.True(t, checks.Enabled)
+	assert.Equal(t, PolicyCheckFailures{
+		`aws_instance.named["a.b"] in project infra is missing required tag cost-center`,
+		"aws_db_instance.db in project infra has tag cost-center=finance that does not match ^CC-[0-9]+$",
+		"aws_db_instance.db in project infra is missing required tag owner",
+	}, checks.Failures)
+	assert.Empty(t, checks.Passed)
+
+	out.Projects[0].Breakdown.Resources = out.Projects[0].Breakdown.Resources[:1]
+	checks = CheckTagPolicy(out, policy)
+	assert.False(t, checks.HasFailed())
+	assert.Equal(t, []string{"Resources have required tag cost-center", "Resources have required tag owner"}, checks.Passed)
+}
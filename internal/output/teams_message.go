@@ -0,0 +1,209 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// TeamsMaxOutputSize is the number of characters of the Infracost output that
+// is included in a Teams message, as Teams limits the size of messages to 28KB.
+const TeamsMaxOutputSize = 20000
+
+// teamsMessage is the payload of a Microsoft Teams incoming webhook that
+// contains an Adaptive Card.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	ContentURL  *string      `json:"contentUrl"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string                `json:"$schema"`
+	Type    string                `json:"type"`
+	Version string                `json:"version"`
+	Body    []adaptiveCardElement `json:"body"`
+	Actions []adaptiveCardAction  `json:"actions,omitempty"`
+	MSTeams map[string]string     `json:"msteams,omitempty"`
+}
+
+// adaptiveCardElement is any of the elements of an Adaptive Card that are used
+// in the Teams message, fields that aren't used by the type are omitted.
+type adaptiveCardElement struct {
+	Type      string                `json:"type"`
+	Text      string                `json:"text,omitempty"`
+	Size      string                `json:"size,omitempty"`
+	Weight    string                `json:"weight,omitempty"`
+	FontType  string                `json:"fontType,omitempty"`
+	Style     string                `json:"style,omitempty"`
+	Wrap      bool                  `json:"wrap,omitempty"`
+	IsSubtle  bool                  `json:"isSubtle,omitempty"`
+	Separator bool                  `json:"separator,omitempty"`
+	Facts     []adaptiveCardFact    `json:"facts,omitempty"`
+	Items     []adaptiveCardElement `json:"items,omitempty"`
+	Inlines   []adaptiveCardElement `json:"inlines,omitempty"`
+}
+
+type adaptiveCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type adaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func teamsProjectFact(project Project, currency string) adaptiveCardFact {
+	var pastCost, cost, diffCost *decimal.Decimal
+
+	if project.PastBreakdown != nil {
+		pastCost = project.PastBreakdown.TotalMonthlyCost
+	}
+
+	if project.Breakdown != nil {
+		cost = project.Breakdown.TotalMonthlyCost
+	}
+
+	if project.Diff != nil {
+		diffCost = project.Diff.TotalMonthlyCost
+	}
+
+	return adaptiveCardFact{
+		Title: truncateMiddle(project.Label(), 42, "..."),
+		Value: formatCostChangeSummary(currency, cost, pastCost, diffCost),
+	}
+}
+
+// ToTeamsMessage returns the cost estimate as a Microsoft Teams message with
+// an Adaptive Card, which can be posted to a Teams incoming webhook or
+// workflow.
+func ToTeamsMessage(out Root, opts Options) ([]byte, error) {
+	diff, err := ToDiff(out, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate diff")
+	}
+
+	var facts []adaptiveCardFact
+	for _, project := range out.Projects {
+		if len(out.Projects) != 1 && (project.Diff == nil || len(project.Diff.Resources) == 0) {
+			continue
+		}
+		facts = append(facts, teamsProjectFact(project, out.Currency))
+	}
+
+	if len(out.Projects) > 1 {
+		facts = append(facts, adaptiveCardFact{
+			Title: "All projects",
+			Value: formatCostChangeSummary(out.Currency, out.TotalMonthlyCost, out.PastTotalMonthlyCost, out.DiffTotalMonthlyCost),
+		})
+	}
+
+	body := []adaptiveCardElement{
+		{
+			Type:   "TextBlock",
+			Text:   fmt.Sprintf("💰 Infracost estimate: **%s**", formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, true)),
+			Size:   "Medium",
+			Weight: "Bolder",
+			Wrap:   true,
+		},
+	}
+
+	if len(facts) > 0 {
+		body = append(body, adaptiveCardElement{
+			Type:      "FactSet",
+			Separator: true,
+			Facts:     facts,
+		})
+	}
+
+	skippedProjectCount := 0
+	for _, p := range out.Projects {
+		if p.Diff == nil || len(p.Diff.Resources) == 0 {
+			skippedProjectCount++
+		}
+	}
+
+	skippedProjectMessage := ""
+	if len(out.Projects) > 1 {
+		if skippedProjectCount == 1 {
+			skippedProjectMessage = "1 project has no cost estimate changes."
+		} else if skippedProjectCount > 0 {
+			skippedProjectMessage = fmt.Sprintf("%d projects have no cost estimate changes.", skippedProjectCount)
+		}
+	}
+
+	if skippedProjectMessage != "" {
+		body = append(body, adaptiveCardElement{
+			Type:     "TextBlock",
+			Text:     skippedProjectMessage,
+			IsSubtle: true,
+			Wrap:     true,
+		})
+	}
+
+	// The output is added as a text run so that the lines of the diff aren't
+	// parsed as markdown lists.
+	diffMsg := truncateMiddle(ui.StripColor(string(diff)), TeamsMaxOutputSize, "\n\n...(truncated due to Teams message length)...\n\n")
+	body = append(body, adaptiveCardElement{
+		Type:  "Container",
+		Style: "emphasis",
+		Items: []adaptiveCardElement{
+			{
+				Type:   "TextBlock",
+				Text:   "Infracost output",
+				Weight: "Bolder",
+			},
+			{
+				Type: "RichTextBlock",
+				Inlines: []adaptiveCardElement{
+					{
+						Type:     "TextRun",
+						Text:     diffMsg,
+						FontType: "Monospace",
+					},
+				},
+			},
+		},
+	})
+
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		MSTeams: map[string]string{"width": "Full"},
+	}
+
+	if out.ShareURL != "" {
+		card.Actions = []adaptiveCardAction{
+			{
+				Type:  "Action.OpenUrl",
+				Title: "View in Infracost Cloud",
+				URL:   out.ShareURL,
+			},
+		}
+	}
+
+	msg := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content:     card,
+			},
+		},
+	}
+
+	return json.Marshal(msg)
+}
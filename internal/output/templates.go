@@ -114,6 +114,15 @@ table.overall-total {
   margin-top: 1rem;
 }
 
+table.cost-groups {
+  margin-top: 1rem;
+  min-width: 946px;
+}
+
+table.cost-groups tr.subtotal {
+  font-weight: bold;
+}
+
 {{end}}
 
 {{define "faviconBase64"}}
@@ -288,6 +297,41 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       </tbody>
     </table>
 
+    {{- if .Root.CostGroups}}
+    {{$hasPast := .Root.CostGroups.HasPast}}
+    <p class="project-name">Monthly cost by {{join ", " .Root.CostGroups.Keys}}</p>
+    <table class="cost-groups">
+      <thead>
+        {{range .Root.CostGroups.Keys}}
+          <th class="name">{{.}}</th>
+        {{end}}
+        {{if $hasPast}}
+          <th class="monthly-cost">Previous</th>
+          <th class="monthly-cost">New</th>
+          <th class="monthly-cost">Diff</th>
+        {{else}}
+          <th class="monthly-cost">{{ "Monthly cost" | formatTitleWithCurrency }}</th>
+        {{end}}
+      </thead>
+      <tbody>
+        {{range .Root.CostGroups.Groups}}
+          <tr{{if .Subtotal}} class="subtotal"{{end}}>
+            {{range ($.Root.CostGroups.Cells .)}}
+              <td class="name">{{.}}</td>
+            {{end}}
+            {{if $hasPast}}
+              <td class="monthly-cost">{{.PastMonthlyCost | formatCost2DP}}</td>
+            {{end}}
+            <td class="monthly-cost">{{.MonthlyCost | formatCost2DP}}</td>
+            {{if $hasPast}}
+              <td class="monthly-cost">{{.DiffMonthlyCost | formatCost2DP}}</td>
+            {{end}}
+          </tr>
+        {{end}}
+      </tbody>
+    </table>
+    {{end}}
+
     <div class="warnings">
       <p>{{.SummaryMessage | stripColor | replaceNewLines}}</p>
     </div>
@@ -350,6 +394,33 @@ var CommentMarkdownWithHTMLTemplate = `
 </table>
 {{- end }}
 
+{{- if .Root.CostGroups }}
+
+<strong>Monthly cost by {{ join ", " .Root.CostGroups.Keys }}</strong>
+<table>
+  <thead>
+{{- range .Root.CostGroups.Keys }}
+    <td>{{ . }}</td>
+{{- end }}
+    <td>Previous</td>
+    <td>New</td>
+    <td>Diff</td>
+  </thead>
+  <tbody>
+  {{- range .Root.CostGroups.Groups }}
+    <tr>
+    {{- range ($.Root.CostGroups.Cells .) }}
+      <td>{{ . }}</td>
+    {{- end }}
+      <td align="right">{{ formatCost .PastMonthlyCost }}</td>
+      <td align="right">{{ formatCost .MonthlyCost }}</td>
+      <td>{{ formatCostChange .PastMonthlyCost .MonthlyCost }}</td>
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+{{- end }}
+
 {{- if not .MarkdownOptions.OmitDetails }}
 
 <details>
@@ -376,6 +447,22 @@ var CommentMarkdownWithHTMLTemplate = `
 			{{- end}}
 		</details>
 	{{- end }}
+	{{- if gt (len .Options.PolicyChecks.Warnings) 0 }}
+		<details>
+			<summary><strong>⚠️ Policy check warnings</strong></summary>
+				{{ range $v, $f := .Options.PolicyChecks.Warnings}}
+> - {{ $f }}
+				{{- end}}
+		</details>
+	{{- end }}
+	{{- if gt (len .Options.PolicyChecks.Infos) 0 }}
+		<details>
+			<summary><strong>ℹ️ Policy check info</strong></summary>
+				{{ range $v, $f := .Options.PolicyChecks.Infos}}
+> - {{ $f }}
+				{{- end}}
+		</details>
+	{{- end }}
 {{- end }}
 {{- if .Options.GuardrailCheck.Comment }}
 	{{- if gt (len .Options.GuardrailCheck.CommentableFailures) 0 }}
@@ -439,6 +526,17 @@ var CommentMarkdownTemplate = `
   {{- end }}
 {{- end }}
 
+{{- if .Root.CostGroups }}
+
+**Monthly cost by {{ join ", " .Root.CostGroups.Keys }}**
+
+|{{ range .Root.CostGroups.Keys }} **{{ . }}** |{{ end }} **Previous** | **New** | **Diff** |
+|{{ range .Root.CostGroups.Keys }} ---------- |{{ end }} -----------: | ------: | -------- |
+{{- range .Root.CostGroups.Groups }}
+|{{ range ($.Root.CostGroups.Cells .) }} {{ . }} |{{ end }} {{ formatCost .PastMonthlyCost }} | {{ formatCost .MonthlyCost }} | {{ formatCostChange .PastMonthlyCost .MonthlyCost }} |
+{{- end }}
+{{- end }}
+
 {{- if not .MarkdownOptions.OmitDetails }}
 
 **Infracost output:**
@@ -464,6 +562,36 @@ var CommentMarkdownTemplate = `
 			{{- end}}
 ` + "```" /* can't escape backticks */ + `
 	{{- end }}
+	{{- if gt (len .Options.PolicyChecks.Warnings) 0 }}
+
+**Policy check warnings:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Options.PolicyChecks.Warnings}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+	{{- end }}
+	{{- if gt (len .Options.PolicyChecks.Infos) 0 }}
+
+**Policy check info:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Options.PolicyChecks.Infos}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+	{{- end }}
+{{- end }}
+{{- if .Options.GuardrailCheck.Comment }}
+	{{- if gt (len .Options.GuardrailCheck.CommentableFailures) 0 }}
+**Guardrail checks failed:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Options.GuardrailCheck.CommentableFailures}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+	{{ else }}
+**Guardrail checks passed**
+	{{- end }}
 {{- end }}
 {{- if .MarkdownOptions.WillUpdate }}
 
@@ -0,0 +1,336 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	xlsxMainNamespace          = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelationshipsNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPackageRelsNamespace   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xlsxContentTypesNamespace  = "http://schemas.openxmlformats.org/package/2006/content-types"
+
+	xlsxMaxSheetNameLength = 31
+)
+
+var xlsxSummaryHeaders = []string{"Project", "Past monthly cost", "Monthly cost", "Diff monthly cost"}
+
+// xlsxCell is a cell of a worksheet, either a string or a number. Cells with
+// neither are left empty.
+type xlsxCell struct {
+	str    string
+	number *decimal.Decimal
+}
+
+type xlsxSheet struct {
+	name string
+	rows [][]xlsxCell
+}
+
+// ToXLSX returns Root r as an Excel workbook. The first sheet is a summary of
+// the monthly costs of each project, followed by a sheet for each project with
+// a row for each cost component, the same as the rows of ToCSV.
+func ToXLSX(out Root, opts Options) ([]byte, error) {
+	sheets := []xlsxSheet{xlsxSummarySheet(out)}
+	names := map[string]bool{strings.ToLower(sheets[0].name): true}
+
+	for _, project := range out.Projects {
+		name := xlsxSheetName(project.Label(), names)
+
+		rows := [][]xlsxCell{xlsxStringCells(spreadsheetHeaders[1:])}
+		for _, row := range spreadsheetRows(project) {
+			rows = append(rows, []xlsxCell{
+				{str: row.Resource},
+				{str: row.ResourceType},
+				{str: row.Tags},
+				{str: row.SubResource},
+				{str: row.CostComponent},
+				{str: row.Unit},
+				{number: row.HourlyQuantity},
+				{number: row.MonthlyQuantity},
+				{number: row.Price},
+				{number: row.HourlyCost},
+				{number: row.MonthlyCost},
+				{number: row.PastMonthlyQuantity},
+				{number: row.PastMonthlyCost},
+				{number: row.DiffMonthlyCost},
+			})
+		}
+
+		sheets = append(sheets, xlsxSheet{name: name, rows: rows})
+	}
+
+	return writeXLSX(sheets)
+}
+
+func xlsxSummarySheet(out Root) xlsxSheet {
+	rows := [][]xlsxCell{xlsxStringCells(xlsxSummaryHeaders)}
+
+	for _, project := range out.Projects {
+		var pastCost, cost, diffCost *decimal.Decimal
+		if project.PastBreakdown != nil {
+			pastCost = project.PastBreakdown.TotalMonthlyCost
+		}
+
+		if project.Breakdown != nil {
+			cost = project.Breakdown.TotalMonthlyCost
+		}
+
+		if project.Diff != nil {
+			diffCost = project.Diff.TotalMonthlyCost
+		}
+
+		rows = append(rows, []xlsxCell{{str: project.Label()}, {number: pastCost}, {number: cost}, {number: diffCost}})
+	}
+
+	rows = append(rows, []xlsxCell{
+		{str: "Total"},
+		{number: out.PastTotalMonthlyCost},
+		{number: out.TotalMonthlyCost},
+		{number: out.DiffTotalMonthlyCost},
+	})
+
+	return xlsxSheet{name: "Summary", rows: rows}
+}
+
+// xlsxSheetName returns a unique sheet name for the project. Excel limits sheet
+// names to 31 characters, doesn't allow some characters in them and compares
+// them case-insensitively.
+func xlsxSheetName(label string, names map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+
+		return r
+	}, label)
+	name = strings.Trim(name, "'")
+
+	if name == "" {
+		name = "Project"
+	}
+
+	candidate := truncateRunes(name, xlsxMaxSheetNameLength)
+	for i := 2; names[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, xlsxMaxSheetNameLength-len(suffix)) + suffix
+	}
+
+	names[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n])
+}
+
+func xlsxStringCells(values []string) []xlsxCell {
+	cells := make([]xlsxCell, len(values))
+	for i, v := range values {
+		cells[i] = xlsxCell{str: v}
+	}
+
+	return cells
+}
+
+// xlsxColumnName returns the letters of the column with the zero-based index,
+// e.g. A for 0 and AA for 26.
+func xlsxColumnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+
+	return name
+}
+
+type xlsxWorksheetXML struct {
+	XMLName   xml.Name     `xml:"worksheet"`
+	Namespace string       `xml:"xmlns,attr"`
+	Rows      []xlsxRowXML `xml:"sheetData>row"`
+}
+
+type xlsxRowXML struct {
+	R     int           `xml:"r,attr"`
+	Cells []xlsxCellXML `xml:"c"`
+}
+
+type xlsxCellXML struct {
+	R          string  `xml:"r,attr"`
+	T          string  `xml:"t,attr,omitempty"`
+	V          string  `xml:"v,omitempty"`
+	InlineText *string `xml:"is>t,omitempty"`
+}
+
+type xlsxWorkbookXML struct {
+	XMLName      xml.Name       `xml:"workbook"`
+	Namespace    string         `xml:"xmlns,attr"`
+	RelNamespace string         `xml:"xmlns:r,attr"`
+	Sheets       []xlsxSheetXML `xml:"sheets>sheet"`
+}
+
+type xlsxSheetXML struct {
+	Name    string `xml:"name,attr"`
+	SheetID int    `xml:"sheetId,attr"`
+	RID     string `xml:"r:id,attr"`
+}
+
+type xlsxRelationshipsXML struct {
+	XMLName       xml.Name              `xml:"Relationships"`
+	Namespace     string                `xml:"xmlns,attr"`
+	Relationships []xlsxRelationshipXML `xml:"Relationship"`
+}
+
+type xlsxRelationshipXML struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+type xlsxContentTypesXML struct {
+	XMLName   xml.Name          `xml:"Types"`
+	Namespace string            `xml:"xmlns,attr"`
+	Defaults  []xlsxDefaultXML  `xml:"Default"`
+	Overrides []xlsxOverrideXML `xml:"Override"`
+}
+
+type xlsxDefaultXML struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+type xlsxOverrideXML struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// writeXLSX writes the sheets as a minimal Office Open XML workbook. Strings
+// are written inline so the workbook doesn't need a shared strings table.
+func writeXLSX(sheets []xlsxSheet) ([]byte, error) {
+	contentTypes := xlsxContentTypesXML{
+		Namespace: xlsxContentTypesNamespace,
+		Defaults: []xlsxDefaultXML{
+			{Extension: "rels", ContentType: "application/vnd.openxmlformats-package.relationships+xml"},
+			{Extension: "xml", ContentType: "application/xml"},
+		},
+		Overrides: []xlsxOverrideXML{
+			{PartName: "/xl/workbook.xml", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"},
+		},
+	}
+
+	workbook := xlsxWorkbookXML{Namespace: xlsxMainNamespace, RelNamespace: xlsxRelationshipsNamespace}
+	workbookRels := xlsxRelationshipsXML{Namespace: xlsxPackageRelsNamespace}
+	worksheets := make([]xlsxWorksheetXML, 0, len(sheets))
+
+	for i, sheet := range sheets {
+		id := i + 1
+		rID := fmt.Sprintf("rId%d", id)
+
+		contentTypes.Overrides = append(contentTypes.Overrides, xlsxOverrideXML{
+			PartName:    fmt.Sprintf("/xl/worksheets/sheet%d.xml", id),
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml",
+		})
+		workbook.Sheets = append(workbook.Sheets, xlsxSheetXML{Name: sheet.name, SheetID: id, RID: rID})
+		workbookRels.Relationships = append(workbookRels.Relationships, xlsxRelationshipXML{
+			ID:     rID,
+			Type:   xlsxRelationshipsNamespace + "/worksheet",
+			Target: fmt.Sprintf("worksheets/sheet%d.xml", id),
+		})
+
+		worksheets = append(worksheets, xlsxWorksheet(sheet))
+	}
+
+	rootRels := xlsxRelationshipsXML{
+		Namespace: xlsxPackageRelsNamespace,
+		Relationships: []xlsxRelationshipXML{
+			{ID: "rId1", Type: xlsxRelationshipsNamespace + "/officeDocument", Target: "xl/workbook.xml"},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	zw := zip.NewWriter(buf)
+
+	write := func(name string, v interface{}) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write([]byte(xml.Header))
+		if err != nil {
+			return err
+		}
+
+		return xml.NewEncoder(w).Encode(v)
+	}
+
+	err := write("[Content_Types].xml", contentTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = write("_rels/.rels", rootRels)
+	if err != nil {
+		return nil, err
+	}
+
+	err = write("xl/workbook.xml", workbook)
+	if err != nil {
+		return nil, err
+	}
+
+	err = write("xl/_rels/workbook.xml.rels", workbookRels)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ws := range worksheets {
+		err = write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), ws)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func xlsxWorksheet(sheet xlsxSheet) xlsxWorksheetXML {
+	ws := xlsxWorksheetXML{Namespace: xlsxMainNamespace}
+
+	for i, row := range sheet.rows {
+		r := xlsxRowXML{R: i + 1}
+
+		for j, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumnName(j), i+1)
+
+			switch {
+			case cell.number != nil:
+				r.Cells = append(r.Cells, xlsxCellXML{R: ref, V: cell.number.String()})
+			case cell.str != "":
+				s := cell.str
+				r.Cells = append(r.Cells, xlsxCellXML{R: ref, T: "inlineStr", InlineText: &s})
+			}
+		}
+
+		ws.Rows = append(ws.Rows, r)
+	}
+
+	return ws
+}
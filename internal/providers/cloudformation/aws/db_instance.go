@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v7/cloudformation/rds"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetDBInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::RDS::DBInstance",
+		Notes: []string{
+			"Instances that are part of an Aurora cluster only include the instance costs, the cluster storage is not yet supported.",
+		},
+		RFunc: NewDBInstance,
+	}
+}
+
+func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*rds.DBInstance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	piEnabled := boolVal(cfr.EnablePerformanceInsights)
+	piLongTerm := piEnabled && intVal(cfr.PerformanceInsightsRetentionPeriod) > 7
+
+	var resource *schema.Resource
+
+	// Aurora instances are billed as cluster instances, with the storage billed by the cluster
+	if strVal(cfr.DBClusterIdentifier) != "" {
+		r := &aws.RDSClusterInstance{
+			Address:                              d.Address,
+			Region:                               region(d),
+			InstanceClass:                        strVal(cfr.DBInstanceClass),
+			Engine:                               strVal(cfr.Engine),
+			PerformanceInsightsEnabled:           piEnabled,
+			PerformanceInsightsLongTermRetention: piLongTerm,
+		}
+		r.PopulateUsage(u)
+		resource = r.BuildResource()
+	} else {
+		// CloudFormation defaults to 1 day of backups, unlike Terraform
+		backupRetentionPeriod := int64(1)
+		if cfr.BackupRetentionPeriod != nil {
+			backupRetentionPeriod = int64(*cfr.BackupRetentionPeriod)
+		}
+
+		r := &aws.DBInstance{
+			Address:                              d.Address,
+			Region:                               region(d),
+			InstanceClass:                        strVal(cfr.DBInstanceClass),
+			Engine:                               strVal(cfr.Engine),
+			MultiAZ:                              boolVal(cfr.MultiAZ),
+			LicenseModel:                         strVal(cfr.LicenseModel),
+			BackupRetentionPeriod:                backupRetentionPeriod,
+			IOPS:                                 float64(intVal(cfr.Iops)),
+			StorageType:                          strVal(cfr.StorageType),
+			PerformanceInsightsEnabled:           piEnabled,
+			PerformanceInsightsLongTermRetention: piLongTerm,
+		}
+
+		if cfr.AllocatedStorage != nil {
+			if f, err := strconv.ParseFloat(*cfr.AllocatedStorage, 64); err == nil {
+				r.AllocatedStorageGB = floatPtr(f)
+			}
+		}
+
+		r.PopulateUsage(u)
+		resource = r.BuildResource()
+	}
+
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -24,8 +24,10 @@ func NewDynamoDBTable(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 		return nil
 	}
 
-	region := "us-east-1" // TODO figure out how to set region
-	billingMode := cfr.BillingMode
+	billingMode := "PROVISIONED"
+	if cfr.BillingMode != nil {
+		billingMode = *cfr.BillingMode
+	}
 	var readCapacity int64
 	if cfr.ProvisionedThroughput != nil {
 		readCapacity = int64(cfr.ProvisionedThroughput.ReadCapacityUnits)
@@ -37,8 +39,8 @@ func NewDynamoDBTable(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 
 	a := &aws.DynamoDBTable{
 		Address:        d.Address,
-		Region:         region,
-		BillingMode:    *billingMode,
+		Region:         region(d),
+		BillingMode:    billingMode,
 		WriteCapacity:  &writeCapacity,
 		ReadCapacity:   &readCapacity,
 		ReplicaRegions: []string{}, // Global Tables are defined using AWS::DynamoDB::GlobalTable
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v7/cloudformation/ec2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEBSVolumeRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::Volume",
+		RFunc: NewEBSVolume,
+	}
+}
+
+func NewEBSVolume(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.Volume)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	var size *int64
+	if cfr.Size != nil {
+		size = intPtr(int64(*cfr.Size))
+	}
+
+	a := &aws.EBSVolume{
+		Address:    d.Address,
+		Region:     region(d),
+		Type:       strVal(cfr.VolumeType),
+		IOPS:       intVal(cfr.Iops),
+		Throughput: intVal(cfr.Throughput),
+		Size:       size,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
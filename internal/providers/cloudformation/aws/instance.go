@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v7/cloudformation/ec2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// rootDeviceNames are the device names used for the root volume by the
+// common AMIs, since the root device name isn't part of the template.
+var rootDeviceNames = map[string]bool{
+	"/dev/xvda": true,
+	"/dev/sda1": true,
+}
+
+func GetInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::Instance",
+		Notes: []string{
+			"Costs associated with marketplace AMIs are not supported.",
+			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file.",
+			"EC2 detailed monitoring assumes the standard 7 metrics and the lowest tier of prices for CloudWatch.",
+			"If a root volume is not specified then an 8Gi gp2 volume is assumed.",
+		},
+		RFunc: NewInstance,
+	}
+}
+
+func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.Instance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := region(d)
+
+	a := &aws.Instance{
+		Address:          d.Address,
+		Region:           region,
+		Tenancy:          strVal(cfr.Tenancy),
+		PurchaseOption:   "on_demand",
+		AMI:              strVal(cfr.ImageId),
+		InstanceType:     strVal(cfr.InstanceType),
+		EBSOptimized:     boolVal(cfr.EbsOptimized),
+		EnableMonitoring: boolVal(cfr.Monitoring),
+		HasHost:          strVal(cfr.HostId) != "",
+	}
+
+	if cfr.CreditSpecification != nil {
+		a.CPUCredits = strVal(cfr.CreditSpecification.CPUCredits)
+	}
+
+	a.RootBlockDevice = &aws.EBSVolume{
+		Address: "root_block_device",
+		Region:  region,
+	}
+
+	for _, mapping := range cfr.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+
+		var size *int64
+		if mapping.Ebs.VolumeSize != nil {
+			size = intPtr(int64(*mapping.Ebs.VolumeSize))
+		}
+
+		if rootDeviceNames[mapping.DeviceName] {
+			a.RootBlockDevice.Type = strVal(mapping.Ebs.VolumeType)
+			a.RootBlockDevice.IOPS = intVal(mapping.Ebs.Iops)
+			a.RootBlockDevice.Size = size
+			continue
+		}
+
+		a.EBSBlockDevices = append(a.EBSBlockDevices, &aws.EBSVolume{
+			Address: fmt.Sprintf("ebs_block_device[%d]", len(a.EBSBlockDevices)),
+			Region:  region,
+			Type:    strVal(mapping.Ebs.VolumeType),
+			IOPS:    intVal(mapping.Ebs.Iops),
+			Size:    size,
+		})
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	if resource != nil {
+		resource.Tags = mapTags(cfr.Tags)
+	}
+
+	return resource
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v7/cloudformation/lambda"
+	"github.com/awslabs/goformation/v7/cloudformation/serverless"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLambdaFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Lambda::Function",
+		RFunc: NewLambdaFunction,
+	}
+}
+
+func GetServerlessFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::Serverless::Function",
+		Notes: []string{
+			"The memory size from the Globals section of the template is not yet supported.",
+		},
+		RFunc: NewServerlessFunction,
+	}
+}
+
+func NewLambdaFunction(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*lambda.Function)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	resource := newLambdaFunction(d, u, cfr.FunctionName, cfr.MemorySize)
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
+
+func NewServerlessFunction(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*serverless.Function)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	resource := newLambdaFunction(d, u, cfr.FunctionName, cfr.MemorySize)
+	resource.Tags = cfr.Tags
+
+	return resource
+}
+
+func newLambdaFunction(d *schema.ResourceData, u *schema.UsageData, name *string, memorySize *int) *schema.Resource {
+	a := &aws.LambdaFunction{
+		Address:    d.Address,
+		Region:     region(d),
+		Name:       strVal(name),
+		MemorySize: 128,
+	}
+
+	if memorySize != nil {
+		a.MemorySize = int64(*memorySize)
+	}
+
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
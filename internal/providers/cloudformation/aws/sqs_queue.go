@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v7/cloudformation/sqs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSQSQueueRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SQS::Queue",
+		RFunc: NewSQSQueue,
+	}
+}
+
+func NewSQSQueue(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sqs.Queue)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.SQSQueue{
+		Address:   d.Address,
+		Region:    region(d),
+		FifoQueue: boolVal(cfr.FifoQueue),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -2,8 +2,12 @@ package aws
 
 import (
 	"github.com/awslabs/goformation/v7/cloudformation/tags"
+
+	"github.com/infracost/infracost/internal/schema"
 )
 
+var DefaultProviderRegion = "us-east-1"
+
 func mapTags(cfTags []tags.Tag) map[string]string {
 	mapped := make(map[string]string)
 	for _, tag := range cfTags {
@@ -11,3 +15,41 @@ func mapTags(cfTags []tags.Tag) map[string]string {
 	}
 	return mapped
 }
+
+// region returns the region the stack will be deployed to, which is set by
+// the parser since it isn't part of the template.
+func region(d *schema.ResourceData) string {
+	if r := d.Get("region").String(); r != "" {
+		return r
+	}
+
+	return DefaultProviderRegion
+}
+
+func strVal(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func intVal(i *int) int64 {
+	if i == nil {
+		return 0
+	}
+
+	return int64(*i)
+}
+
+func boolVal(b *bool) bool {
+	return b != nil && *b
+}
+
+func intPtr(i int64) *int64 {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
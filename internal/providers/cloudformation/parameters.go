@@ -0,0 +1,99 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// cliParameter is a parameter in the format used by the AWS CLI, e.g.
+// aws cloudformation create-stack --parameters file://params.json
+type cliParameter struct {
+	ParameterKey   string `json:"ParameterKey"`
+	ParameterValue string `json:"ParameterValue"`
+}
+
+// loadParameters returns the parameter values for the project, from its
+// parameter files followed by any inline values. Later values override
+// earlier ones.
+func loadParameters(projectCfg *config.Project) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if projectCfg == nil {
+		return params, nil
+	}
+
+	for _, path := range projectCfg.CloudFormationParameterFiles {
+		fileParams, err := loadParameterFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range fileParams {
+			params[k] = v
+		}
+	}
+
+	for k, v := range projectCfg.CloudFormationParameters {
+		params[k] = v
+	}
+
+	return params, nil
+}
+
+// loadParameterFile reads the parameters from a JSON file. The file can be
+// in the AWS CLI format, i.e. a list of ParameterKey and ParameterValue
+// objects, the CodePipeline template configuration format, i.e. an object
+// with a Parameters key, or a plain object of keys and values.
+func loadParameterFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading CloudFormation parameters file %s: %w", path, err)
+	}
+
+	params, err := parseParameters(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing CloudFormation parameters file %s: %w", path, err)
+	}
+
+	return params, nil
+}
+
+func parseParameters(data []byte) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var cliParams []cliParameter
+		if err := json.Unmarshal(data, &cliParams); err != nil {
+			return nil, err
+		}
+
+		for _, p := range cliParams {
+			if p.ParameterKey == "" {
+				return nil, fmt.Errorf("parameter is missing ParameterKey")
+			}
+			params[p.ParameterKey] = p.ParameterValue
+		}
+
+		return params, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	if v, ok := obj["Parameters"].(map[string]interface{}); ok {
+		obj = v
+	}
+
+	for k, v := range obj {
+		// Parameter values are always strings, with list values comma-delimited
+		params[k] = subValueString(v)
+	}
+
+	return params, nil
+}
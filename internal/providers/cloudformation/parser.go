@@ -2,21 +2,46 @@ package cloudformation
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/awslabs/goformation/v7/cloudformation"
+	cfnstack "github.com/awslabs/goformation/v7/cloudformation/cloudformation"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/tidwall/gjson"
 )
 
 type Parser struct {
-	ctx *config.ProjectContext
+	ctx    *config.ProjectContext
+	region string
 }
 
 func NewParser(ctx *config.ProjectContext) *Parser {
-	return &Parser{ctx}
+	return &Parser{
+		ctx:    ctx,
+		region: detectRegion(ctx),
+	}
+}
+
+// detectRegion returns the region the stack will be deployed to, since this
+// isn't part of the template. It uses the AWS region environment variables,
+// from the project config first, and falls back to the default region.
+func detectRegion(ctx *config.ProjectContext) string {
+	for _, k := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if ctx != nil && ctx.ProjectConfig != nil && ctx.ProjectConfig.Env[k] != "" {
+			return ctx.ProjectConfig.Env[k]
+		}
+
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+
+	return aws.DefaultProviderRegion
 }
 
 func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
@@ -59,33 +84,111 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	}
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage map[string]*schema.UsageData) ([]*schema.Resource, []*schema.Resource, error) {
+// parseTemplate returns the resources in the template at path, along with
+// the resources of any nested stacks.
+func (p *Parser) parseTemplate(t *cloudformation.Template, path string, usage map[string]*schema.UsageData) ([]*schema.Resource, []*schema.Resource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
 	var resources []*schema.Resource
 	resources = append(resources, baseResources...)
 
+	stackResources, err := p.parseStack(t, path, "", usage, []string{filepath.Clean(path)})
+	if err != nil {
+		return nil, nil, err
+	}
+	resources = append(resources, stackResources...)
+
+	return resources, resources, nil
+}
+
+// parseStack returns the resources in a stack template. Nested stacks with a
+// local template are loaded into the same project, with the addresses of
+// their resources prefixed by the logical ID of the nested stack, e.g.
+// Network.NatGateway.
+func (p *Parser) parseStack(t *cloudformation.Template, path string, prefix string, usage map[string]*schema.UsageData, ancestors []string) ([]*schema.Resource, error) {
+	var resources []*schema.Resource
+
 	for name, d := range t.Resources {
+		address := prefix + name
+
+		if stack, ok := d.(*cfnstack.Stack); ok {
+			stackResources, err := p.parseNestedStack(stack, address, path, usage, ancestors)
+			if err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, stackResources...)
+			continue
+		}
+
 		tags := map[string]string{} // TODO: Where do I get tags?
 		var usageData *schema.UsageData
 
-		if ud := usage[name]; ud != nil {
+		if ud := usage[address]; ud != nil {
 			usageData = ud
-		} else if strings.HasSuffix(name, "]") {
-			lastIndexOfOpenBracket := strings.LastIndex(name, "[")
+		} else if strings.HasSuffix(address, "]") {
+			lastIndexOfOpenBracket := strings.LastIndex(address, "[")
 
-			if arrayUsageData := usage[fmt.Sprintf("%s[*]", name[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
+			if arrayUsageData := usage[fmt.Sprintf("%s[*]", address[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
 				usageData = arrayUsageData
 			}
 		}
-		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, tags, d)
+		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", address, tags, d)
+		resourceData.RawValues = gjson.Parse(fmt.Sprintf(`{"region":%q}`, p.region))
 
 		if r := p.createResource(resourceData, usageData); r != nil {
 			resources = append(resources, r)
 		}
 	}
 
-	return resources, resources, nil
+	return resources, nil
+}
+
+// parseNestedStack loads the template of a nested stack, passing it the
+// parameters of the stack resource, and returns its resources.
+func (p *Parser) parseNestedStack(stack *cfnstack.Stack, address string, parentPath string, usage map[string]*schema.UsageData, ancestors []string) ([]*schema.Resource, error) {
+	path, ok := localTemplatePath(parentPath, stack.TemplateURL)
+	if !ok {
+		return []*schema.Resource{{
+			Name:         address,
+			ResourceType: stack.AWSCloudFormationType(),
+			IsSkipped:    true,
+			SkipMessage:  "Nested stacks are only supported with local template paths",
+		}}, nil
+	}
+
+	for _, a := range ancestors {
+		if a == path {
+			return nil, fmt.Errorf("nested stack %s references template %s which is already being loaded", address, path)
+		}
+	}
+
+	parameters := make(map[string]interface{}, len(stack.Parameters))
+	for k, v := range stack.Parameters {
+		parameters[k] = v
+	}
+
+	t, err := loadTemplate(path, p.region, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading template %s for nested stack %s: %w", path, address, err)
+	}
+
+	return p.parseStack(t, path, address+".", usage, append(ancestors, path))
+}
+
+// localTemplatePath returns the path of a nested stack template relative to
+// the parent template, or false if the template is remote, e.g. in S3.
+func localTemplatePath(parentPath string, templateURL string) (string, bool) {
+	if templateURL == "" || (strings.Contains(templateURL, "://") && !strings.HasPrefix(templateURL, "file://")) {
+		return "", false
+	}
+
+	path := strings.TrimPrefix(templateURL, "file://")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(parentPath), path)
+	}
+
+	return filepath.Clean(path), true
 }
 
 func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schema.Resource {
@@ -106,5 +209,5 @@ func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schem
 }
 
 func isAwsChina(d *schema.ResourceData) bool {
-	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
+	return (strings.HasPrefix(d.Type, "aws_") || strings.HasPrefix(d.Type, "AWS::")) && strings.HasPrefix(d.Get("region").String(), "cn-")
 }
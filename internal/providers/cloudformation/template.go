@@ -0,0 +1,427 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v7"
+	"github.com/awslabs/goformation/v7/cloudformation"
+	"github.com/awslabs/goformation/v7/intrinsics"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+var subVariableRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// templateResolver resolves the intrinsic functions in a CloudFormation
+// template. The goformation handlers only resolve Ref against parameter
+// defaults and don't resolve Fn::GetAtt at all, so we override them to
+// resolve the pseudo parameters for the configured region, and to keep
+// references to other resources as their logical IDs.
+type templateResolver struct {
+	region string
+}
+
+// loadTemplate reads the CloudFormation template at path and resolves its
+// intrinsic functions and conditions, using the given parameter values or
+// the parameter defaults. Resources with a condition that evaluates to false
+// are removed from the template.
+func loadTemplate(path string, region string, parameters map[string]interface{}) (*cloudformation.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".json") {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+
+	resourceConditions := removeResourceConditions(raw)
+
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &templateResolver{region: region}
+
+	processed, err := intrinsics.ProcessJSON(data, &intrinsics.ProcessorOptions{
+		ParameterOverrides: parameters,
+		EvaluateConditions: true,
+		IntrinsicHandlerOverrides: map[string]intrinsics.IntrinsicHandler{
+			"Ref":        r.ref,
+			"Fn::GetAtt": r.getAtt,
+			"Fn::Sub":    r.sub,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw = map[string]interface{}{}
+	if err := json.Unmarshal(processed, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+
+	excludeConditionalResources(raw, resourceConditions)
+	normalizeStackParameters(raw)
+
+	processed, err = json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return goformation.ParseJSONWithOptions(processed, &intrinsics.ProcessorOptions{NoProcess: true})
+}
+
+// yamlToJSON converts a YAML template to JSON, converting any short form
+// intrinsic functions, e.g. !Ref, to their long form without resolving them.
+// The goformation YAML processor doesn't convert short form functions that are
+// nested inside other short form functions, e.g. !Equals [!Ref Env, prod].
+func yamlToJSON(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("invalid YAML template: %w", err)
+	}
+
+	v, err := yamlNodeValue(&node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML template: %w", err)
+	}
+
+	return json.Marshal(v)
+}
+
+func yamlNodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	}
+
+	if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+		name := strings.TrimPrefix(node.Tag, "!")
+		if name != "Ref" && name != "Condition" {
+			name = "Fn::" + name
+		}
+
+		untagged := *node
+		untagged.Tag = ""
+		untagged.Style &^= yaml.TaggedStyle
+
+		v, err := yamlNodeValue(&untagged)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{name: v}, nil
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := yamlNodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		l := make([]interface{}, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := yamlNodeValue(n)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	}
+
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// removeResourceConditions removes the Condition key from the resources and
+// returns the condition of each resource by its logical ID. The goformation
+// processor replaces the whole resource with the value of the condition when
+// conditions are evaluated, so we exclude the resources ourselves after the
+// conditions have been evaluated.
+func removeResourceConditions(raw map[string]interface{}) map[string]string {
+	conditions := map[string]string{}
+
+	resources, ok := raw["Resources"].(map[string]interface{})
+	if !ok {
+		return conditions
+	}
+
+	for name, v := range resources {
+		resource, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if c, ok := resource["Condition"].(string); ok {
+			conditions[name] = c
+		}
+		delete(resource, "Condition")
+	}
+
+	return conditions
+}
+
+// excludeConditionalResources removes the resources whose condition evaluated
+// to false. Resources are included if their condition couldn't be evaluated,
+// e.g. because it refers to a parameter without a value.
+func excludeConditionalResources(raw map[string]interface{}, resourceConditions map[string]string) {
+	resources, ok := raw["Resources"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	conditions, _ := raw["Conditions"].(map[string]interface{})
+
+	for name, condition := range resourceConditions {
+		v, ok := conditions[condition].(bool)
+		if !ok {
+			log.Debugf("Including CloudFormation resource %s since its condition %s could not be evaluated", name, condition)
+			continue
+		}
+
+		if !v {
+			log.Debugf("Excluding CloudFormation resource %s since its condition %s is false", name, condition)
+			delete(resources, name)
+		}
+	}
+}
+
+// normalizeStackParameters converts the parameters of any nested stacks to
+// strings, since they may have been resolved to numbers or lists.
+func normalizeStackParameters(raw map[string]interface{}) {
+	resources, ok := raw["Resources"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, v := range resources {
+		resource, ok := v.(map[string]interface{})
+		if !ok || resource["Type"] != "AWS::CloudFormation::Stack" {
+			continue
+		}
+
+		props, _ := resource["Properties"].(map[string]interface{})
+		params, ok := props["Parameters"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for k, p := range params {
+			if p == nil {
+				delete(params, k)
+				continue
+			}
+			params[k] = subValueString(p)
+		}
+	}
+}
+
+// ref resolves a Ref to a pseudo parameter, a parameter default or another
+// resource in the template. References to resources are resolved to the
+// logical ID of the resource.
+func (r *templateResolver) ref(name string, input interface{}, template interface{}) interface{} {
+	refName, ok := input.(string)
+	if !ok {
+		return nil
+	}
+
+	switch refName {
+	case "AWS::AccountId":
+		return "123456789012"
+	case "AWS::NoValue":
+		return nil
+	case "AWS::NotificationARNs":
+		return []interface{}{}
+	case "AWS::Partition":
+		if strings.HasPrefix(r.region, "cn-") {
+			return "aws-cn"
+		}
+		if strings.HasPrefix(r.region, "us-gov-") {
+			return "aws-us-gov"
+		}
+		return "aws"
+	case "AWS::Region":
+		return r.region
+	case "AWS::StackId":
+		return fmt.Sprintf("arn:aws:cloudformation:%s:123456789012:stack/infracost/00000000-0000-0000-0000-000000000000", r.region)
+	case "AWS::StackName":
+		return "infracost"
+	case "AWS::URLSuffix":
+		if strings.HasPrefix(r.region, "cn-") {
+			return "amazonaws.com.cn"
+		}
+		return "amazonaws.com"
+	}
+
+	t, _ := template.(map[string]interface{})
+
+	if params, ok := t["Parameters"].(map[string]interface{}); ok {
+		if param, ok := params[refName].(map[string]interface{}); ok {
+			return parameterValue(param)
+		}
+	}
+
+	if resources, ok := t["Resources"].(map[string]interface{}); ok {
+		if _, ok := resources[refName]; ok {
+			return refName
+		}
+	}
+
+	return nil
+}
+
+// parameterValue returns the value of a parameter, converting it to the
+// parameter type so it can be used in numeric properties.
+func parameterValue(param map[string]interface{}) interface{} {
+	def, ok := param["Default"]
+	if !ok {
+		return nil
+	}
+
+	paramType, _ := param["Type"].(string)
+
+	s, ok := def.(string)
+	if !ok {
+		return def
+	}
+
+	if paramType == "Number" {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return f
+		}
+	}
+
+	if paramType == "CommaDelimitedList" || strings.HasPrefix(paramType, "List<") {
+		parts := strings.Split(s, ",")
+		l := make([]interface{}, 0, len(parts))
+		for _, p := range parts {
+			l = append(l, strings.TrimSpace(p))
+		}
+		return l
+	}
+
+	return def
+}
+
+// getAtt resolves a Fn::GetAtt to a placeholder of the form
+// LogicalID.Attribute since the attribute values aren't known until the
+// stack is deployed.
+func (r *templateResolver) getAtt(name string, input interface{}, template interface{}) interface{} {
+	switch v := input.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ".")
+	}
+
+	return nil
+}
+
+// sub resolves a Fn::Sub, substituting any variables with the given values,
+// or by resolving them as a Ref or Fn::GetAtt.
+func (r *templateResolver) sub(name string, input interface{}, template interface{}) interface{} {
+	var s string
+	vars := map[string]interface{}{}
+
+	switch v := input.(type) {
+	case string:
+		s = v
+	case []interface{}:
+		if len(v) != 2 {
+			return nil
+		}
+
+		var ok bool
+		s, ok = v[0].(string)
+		if !ok {
+			return nil
+		}
+
+		if m, ok := v[1].(map[string]interface{}); ok {
+			vars = m
+		}
+	default:
+		return nil
+	}
+
+	return subVariableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		variable := strings.TrimSpace(match[2 : len(match)-1])
+
+		// ${!Literal} is written out as ${Literal}
+		if strings.HasPrefix(variable, "!") {
+			return "${" + variable[1:] + "}"
+		}
+
+		var resolved interface{}
+		if v, ok := vars[variable]; ok {
+			resolved = v
+		} else if strings.Contains(variable, ".") && !strings.HasPrefix(variable, "AWS::") {
+			resolved = r.getAtt("Fn::GetAtt", variable, template)
+		} else {
+			resolved = r.ref("Ref", variable, template)
+		}
+
+		return subValueString(resolved)
+	})
+}
+
+func subValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, p := range val {
+			parts = append(parts, subValueString(p))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	return fmt.Sprintf("%v", v)
+}
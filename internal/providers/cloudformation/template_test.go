@@ -0,0 +1,174 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	template, err := loadTemplate("testdata/template.yml", "eu-west-1", nil)
+	require.NoError(t, err)
+
+	instance, err := template.GetEC2InstanceWithName("WebServer")
+	require.NoError(t, err)
+	assert.Equal(t, "m5.large", *instance.InstanceType)
+	assert.Equal(t, "infracost-prod-web", instance.Tags[0].Value)
+
+	volume, err := template.GetEC2VolumeWithName("DataVolume")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1a", volume.AvailabilityZone)
+
+	db, err := template.GetRDSDBInstanceWithName("Database")
+	require.NoError(t, err)
+	assert.True(t, *db.MultiAZ)
+
+	api, err := template.GetServerlessFunctionWithName("Api")
+	require.NoError(t, err)
+	assert.Equal(t, 512, *api.MemorySize)
+	assert.Equal(t, "Bucket.Arn", api.Environment.Variables["BUCKET_ARN"])
+	assert.Equal(t, "Bucket", api.Environment.Variables["TABLE"])
+
+	worker, err := template.GetLambdaFunctionWithName("Worker")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-worker", *worker.FunctionName)
+	assert.Equal(t, "WorkerRole.Arn", worker.Role)
+}
+
+func TestParseTemplate(t *testing.T) {
+	template, err := loadTemplate("testdata/template.yml", "eu-west-1", nil)
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, nil)
+	p := &Parser{ctx: ctx, region: "eu-west-1"}
+
+	_, resources, err := p.parseTemplate(template, "testdata/template.yml", map[string]*schema.UsageData{})
+	require.NoError(t, err)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	for _, name := range []string{"WebServer", "DataVolume", "Database", "Api", "Worker", "Bucket", "Nat", "LoadBalancer", "Queue"} {
+		r, ok := byName[name]
+		require.True(t, ok, "missing resource %s", name)
+		assert.False(t, r.IsSkipped, "resource %s was skipped: %s", name, r.SkipMessage)
+	}
+
+	assert.True(t, byName["WorkerRole"].NoPrice)
+	assert.True(t, byName["Unsupported"].IsSkipped)
+
+	// The root and additional block devices are added as sub resources
+	assert.Len(t, byName["WebServer"].SubResources, 2)
+	assert.Equal(t, "infracost-prod-web", byName["WebServer"].Tags["Name"])
+
+	// The region is used for the prices
+	for _, c := range byName["DataVolume"].CostComponents {
+		assert.Equal(t, "eu-west-1", *c.ProductFilter.Region)
+	}
+}
+
+func TestLoadTemplateParameters(t *testing.T) {
+	params, err := loadParameters(&config.Project{
+		CloudFormationParameterFiles: []string{"testdata/parameters/cli.json", "testdata/parameters/pipeline.json"},
+		CloudFormationParameters:     map[string]string{"InstanceType": "t3.large"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Environment":    "staging",
+		"InstanceType":   "t3.large",
+		"FunctionMemory": "1024",
+	}, params)
+
+	template, err := loadTemplate("testdata/template.yml", "eu-west-1", params)
+	require.NoError(t, err)
+
+	// IsProd is false so the conditional resource is excluded
+	_, err = template.GetEC2NatGatewayWithName("Nat")
+	assert.Error(t, err)
+
+	instance, err := template.GetEC2InstanceWithName("WebServer")
+	require.NoError(t, err)
+	assert.Equal(t, "t3.micro", *instance.InstanceType)
+	assert.Equal(t, "infracost-staging-web", instance.Tags[0].Value)
+
+	api, err := template.GetServerlessFunctionWithName("Api")
+	require.NoError(t, err)
+	assert.Equal(t, 1024, *api.MemorySize)
+}
+
+func TestParseParameters(t *testing.T) {
+	params, err := parseParameters([]byte(`{"Subnets": ["subnet-1", "subnet-2"], "Count": 2}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Subnets": "subnet-1,subnet-2", "Count": "2"}, params)
+
+	_, err = parseParameters([]byte(`[{"ParameterValue": "dev"}]`))
+	assert.Error(t, err)
+}
+
+func TestParseNestedStacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+		addresses  []string
+	}{
+		{
+			name:      "defaults",
+			addresses: []string{"Network.Volume", "Network.Storage.Queue", "Remote"},
+		},
+		{
+			name:       "parameters",
+			parameters: map[string]interface{}{"Environment": "prod"},
+			addresses:  []string{"Network.Nat", "Network.Volume", "Network.Storage.Queue", "Remote"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := loadTemplate("testdata/nested/root.yml", "eu-west-1", tt.parameters)
+			require.NoError(t, err)
+
+			ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, nil)
+			p := &Parser{ctx: ctx, region: "eu-west-1"}
+
+			_, resources, err := p.parseTemplate(template, "testdata/nested/root.yml", map[string]*schema.UsageData{})
+			require.NoError(t, err)
+
+			byName := map[string]*schema.Resource{}
+			for _, r := range resources {
+				byName[r.Name] = r
+			}
+
+			var addresses []string
+			for name := range byName {
+				addresses = append(addresses, name)
+			}
+			assert.ElementsMatch(t, tt.addresses, addresses)
+
+			// The nested stack parameters are passed to the nested template
+			volume := byName["Network.Volume"]
+			require.NotNil(t, volume)
+			assert.False(t, volume.IsSkipped)
+			assert.Equal(t, "20", volume.CostComponents[0].MonthlyQuantity.String())
+
+			assert.True(t, byName["Remote"].IsSkipped)
+		})
+	}
+}
+
+func TestParseNestedStackCycle(t *testing.T) {
+	template, err := loadTemplate("testdata/nested/cycle.yml", "eu-west-1", nil)
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, nil)
+	p := &Parser{ctx: ctx, region: "eu-west-1"}
+
+	_, _, err = p.parseTemplate(template, "testdata/nested/cycle.yml", map[string]*schema.UsageData{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already being loaded")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/providers/pulumi"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -69,6 +70,21 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 		}
 
 		return h, nil
+	case "terragrunt_dir":
+		h := terraform.NewTerragruntHCLProvider(ctx)
+
+		if err := validateProjectForHCL(ctx, path); err != nil {
+			return h, err
+		}
+
+		return h, nil
+	case "terragrunt_cli":
+		p := terraform.NewDirProvider(ctx, includePastResources)
+		if dirProvider, ok := p.(*terraform.DirProvider); ok {
+			dirProvider.IsTerragrunt = true
+		}
+
+		return p, nil
 	case "terraform_plan_json":
 		return terraform.NewPlanJSONProvider(ctx, includePastResources), nil
 	case "terraform_plan_binary":
@@ -79,6 +95,10 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 		return terraform.NewStateJSONProvider(ctx, includePastResources), nil
 	case "cloudformation":
 		return cloudformation.NewTemplateProvider(ctx, includePastResources), nil
+	case "pulumi_preview_json":
+		return pulumi.NewPreviewJSONProvider(ctx, includePastResources), nil
+	case "pulumi_stack_json":
+		return pulumi.NewStackJSONProvider(ctx, includePastResources), nil
 	}
 
 	return nil, fmt.Errorf("could not detect path type for '%s'", path)
@@ -119,6 +139,14 @@ func DetectProjectType(path string, forceCLI bool) string {
 		return "terraform_state_json"
 	}
 
+	if isPulumiPreviewJSON(path) {
+		return "pulumi_preview_json"
+	}
+
+	if isPulumiStackJSON(path) {
+		return "pulumi_stack_json"
+	}
+
 	if isTerraformPlan(path) {
 		return "terraform_plan_binary"
 	}
@@ -185,6 +213,47 @@ func isTerraformStateJSON(path string) bool {
 	return jsonFormat.FormatVersion != "" && jsonFormat.Values != nil
 }
 
+func isPulumiPreviewJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var jsonFormat struct {
+		Steps         []interface{} `json:"steps"`
+		ChangeSummary interface{}   `json:"changeSummary"`
+	}
+
+	err = json.Unmarshal(b, &jsonFormat)
+	if err != nil {
+		return false
+	}
+
+	return jsonFormat.Steps != nil && jsonFormat.ChangeSummary != nil
+}
+
+func isPulumiStackJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var jsonFormat struct {
+		Version    int `json:"version"`
+		Deployment struct {
+			Manifest  interface{} `json:"manifest"`
+			Resources interface{} `json:"resources"`
+		} `json:"deployment"`
+	}
+
+	err = json.Unmarshal(b, &jsonFormat)
+	if err != nil {
+		return false
+	}
+
+	return jsonFormat.Version > 0 && jsonFormat.Deployment.Manifest != nil
+}
+
 func isTerraformPlan(path string) bool {
 	r, err := zip.OpenReader(path)
 	if err != nil {
@@ -0,0 +1,543 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+const (
+	// unknownValue is the value of the outputs that are unknown during a preview.
+	unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+	// signatureKey is the key of the objects that are secrets, assets or archives,
+	// with the signature of the type as its value.
+	signatureKey    = "4dabf18193072939515e22adb298388d"
+	secretSignature = "1b47061264138c4ac30d75fd1eb44270"
+
+	providerTypePrefix = "pulumi:providers:"
+)
+
+var (
+	// mapAttributes are the properties that are maps in Terraform rather than
+	// nested blocks, so their keys aren't converted.
+	mapAttributes = map[string]struct{}{
+		"annotations":     {},
+		"default_tags":    {},
+		"labels":          {},
+		"metadata":        {},
+		"parameters":      {},
+		"tags":            {},
+		"tags_all":        {},
+		"user_labels":     {},
+		"variables":       {},
+		"resource_labels": {},
+	}
+
+	// deletedOps are the steps whose resource isn't planned after the update.
+	deletedOps = map[string]struct{}{
+		"delete":                 {},
+		"delete-replaced":        {},
+		"discard":                {},
+		"discard-replaced":       {},
+		"remove-pending-replace": {},
+	}
+	// readOps are the steps of resources that are read rather than managed by
+	// the stack, like Terraform data sources.
+	readOps = map[string]struct{}{
+		"read":             {},
+		"read-replacement": {},
+		"read-discard":     {},
+		"refresh":          {},
+	}
+
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// resource is a resource in a Pulumi stack export or preview step.
+type resource struct {
+	URN                  string                 `json:"urn"`
+	Custom               bool                   `json:"custom"`
+	Type                 string                 `json:"type"`
+	Inputs               map[string]interface{} `json:"inputs"`
+	Outputs              map[string]interface{} `json:"outputs"`
+	Provider             string                 `json:"provider"`
+	PropertyDependencies map[string][]string    `json:"propertyDependencies"`
+}
+
+// step is a step of a Pulumi preview, with the state of the resource before
+// and after the step.
+type step struct {
+	Op       string    `json:"op"`
+	URN      string    `json:"urn"`
+	OldState *resource `json:"oldState"`
+	NewState *resource `json:"newState"`
+}
+
+// pulumiJSON is either the output of pulumi preview --json, which has Steps,
+// or of pulumi stack export, which has a Deployment.
+type pulumiJSON struct {
+	Config     map[string]interface{} `json:"config"`
+	Steps      []step                 `json:"steps"`
+	Deployment *struct {
+		Resources []*resource `json:"resources"`
+	} `json:"deployment"`
+}
+
+// planResource is a resource in the planned values or prior state of a
+// Terraform plan JSON.
+type planResource struct {
+	Address      string                 `json:"address"`
+	Mode         string                 `json:"mode"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	ProviderName string                 `json:"provider_name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+type confResource struct {
+	Address           string                 `json:"address"`
+	Mode              string                 `json:"mode"`
+	Type              string                 `json:"type"`
+	Name              string                 `json:"name"`
+	ProviderConfigKey string                 `json:"provider_config_key"`
+	Expressions       map[string]interface{} `json:"expressions,omitempty"`
+}
+
+type providerConf struct {
+	Name        string                 `json:"name"`
+	Alias       string                 `json:"alias,omitempty"`
+	Expressions map[string]interface{} `json:"expressions,omitempty"`
+}
+
+type resourceChange struct {
+	Address string `json:"address"`
+	Change  struct {
+		Actions []string `json:"actions"`
+	} `json:"change"`
+}
+
+type rootModule struct {
+	Resources []planResource `json:"resources"`
+}
+
+type planJSON struct {
+	FormatVersion string `json:"format_version"`
+	PlannedValues struct {
+		RootModule rootModule `json:"root_module"`
+	} `json:"planned_values"`
+	PriorState struct {
+		Values struct {
+			RootModule rootModule `json:"root_module"`
+		} `json:"values"`
+	} `json:"prior_state"`
+	ResourceChanges []resourceChange `json:"resource_changes"`
+	Configuration   struct {
+		ProviderConfig map[string]providerConf `json:"provider_config"`
+		RootModule     struct {
+			Resources []confResource `json:"resources"`
+		} `json:"root_module"`
+	} `json:"configuration"`
+}
+
+// converter converts the resources of a Pulumi stack to a Terraform plan
+// JSON. Resources are identified by their URN in Pulumi, and by an address
+// derived from the URN in the plan JSON.
+type converter struct {
+	addresses    map[string]string
+	names        map[string]struct{}
+	providerKeys map[string]string
+	plan         planJSON
+}
+
+// toPlanJSON converts the output of pulumi preview --json or pulumi stack
+// export to a Terraform plan JSON. The resources after the preview are the
+// planned values and the resources before it are the prior state. A stack
+// export has the same resources for both.
+func toPlanJSON(b []byte) ([]byte, error) {
+	var p pulumiJSON
+	err := json.Unmarshal(b, &p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Pulumi JSON: %w", err)
+	}
+
+	var past, planned []*resource
+	ops := map[string]string{}
+
+	switch {
+	case p.Deployment != nil:
+		past = p.Deployment.Resources
+		planned = p.Deployment.Resources
+	case p.Steps != nil:
+		for _, s := range p.Steps {
+			if _, ok := readOps[s.Op]; ok {
+				continue
+			}
+
+			if s.OldState != nil && s.Op != "create" && s.Op != "create-replacement" {
+				past = append(past, s.OldState)
+			}
+
+			if _, ok := deletedOps[s.Op]; !ok && s.NewState != nil {
+				planned = append(planned, s.NewState)
+			}
+
+			if s.Op != "same" {
+				ops[s.URN] = s.Op
+			}
+		}
+	default:
+		return nil, errors.New("invalid Pulumi JSON: expected the output of pulumi preview --json or pulumi stack export")
+	}
+
+	c := &converter{
+		addresses:    map[string]string{},
+		names:        map[string]struct{}{},
+		providerKeys: map[string]string{},
+	}
+	c.plan.FormatVersion = "1.0"
+	c.plan.Configuration.ProviderConfig = map[string]providerConf{}
+
+	all := append(append([]*resource{}, past...), planned...)
+	c.addProviders(p.Config, all)
+
+	// the addresses are set before converting the resources so that the
+	// references between them are found regardless of their order.
+	for _, r := range all {
+		if t, ok := c.managedType(r); ok {
+			c.address(r.URN, t)
+		}
+	}
+
+	confs := map[string]confResource{}
+	for _, r := range planned {
+		if res, ok := c.planResource(r); ok {
+			c.plan.PlannedValues.RootModule.Resources = append(c.plan.PlannedValues.RootModule.Resources, res)
+			confs[res.Address] = c.confResource(r, res)
+		}
+	}
+
+	for _, r := range past {
+		if res, ok := c.planResource(r); ok {
+			c.plan.PriorState.Values.RootModule.Resources = append(c.plan.PriorState.Values.RootModule.Resources, res)
+			if _, ok := confs[res.Address]; !ok {
+				confs[res.Address] = c.confResource(r, res)
+			}
+		}
+	}
+
+	for _, res := range confs {
+		c.plan.Configuration.RootModule.Resources = append(c.plan.Configuration.RootModule.Resources, res)
+	}
+	sort.Slice(c.plan.Configuration.RootModule.Resources, func(i, j int) bool {
+		return c.plan.Configuration.RootModule.Resources[i].Address < c.plan.Configuration.RootModule.Resources[j].Address
+	})
+
+	urns := make([]string, 0, len(ops))
+	for urn := range ops {
+		urns = append(urns, urn)
+	}
+	sort.Strings(urns)
+
+	for _, urn := range urns {
+		addr, ok := c.addresses[urn]
+		if !ok {
+			continue
+		}
+
+		change := resourceChange{Address: addr}
+		change.Change.Actions = changeActions(ops[urn])
+		c.plan.ResourceChanges = append(c.plan.ResourceChanges, change)
+	}
+
+	return json.Marshal(c.plan)
+}
+
+// addProviders adds a Terraform provider config for each Pulumi provider
+// resource, with the region of the provider. The default providers use the
+// region from the stack config if they don't have one.
+func (c *converter) addProviders(config map[string]interface{}, resources []*resource) {
+	for _, r := range resources {
+		if !strings.HasPrefix(r.Type, providerTypePrefix) {
+			continue
+		}
+
+		pkg, ok := bridgedPackages[strings.TrimPrefix(r.Type, providerTypePrefix)]
+		if !ok {
+			continue
+		}
+
+		conf := providerConf{Name: pkg.prefix}
+		key := pkg.prefix
+		name := urnName(r.URN)
+		if !strings.HasPrefix(name, "default") {
+			conf.Alias = sanitizeName(name)
+			key = fmt.Sprintf("%s.%s", pkg.prefix, conf.Alias)
+		}
+
+		if region, ok := r.Inputs["region"].(string); ok && region != "" {
+			conf.Expressions = map[string]interface{}{"region": map[string]interface{}{"constant_value": region}}
+		}
+
+		// the resources reference their provider by its URN and ID.
+		c.providerKeys[r.URN] = key
+		if _, ok := c.plan.Configuration.ProviderConfig[key]; !ok || conf.Expressions != nil {
+			c.plan.Configuration.ProviderConfig[key] = conf
+		}
+	}
+
+	for name, pkg := range bridgedPackages {
+		region, ok := config[name+":region"].(string)
+		if !ok || region == "" {
+			continue
+		}
+
+		if conf, ok := c.plan.Configuration.ProviderConfig[pkg.prefix]; !ok || conf.Expressions == nil {
+			c.plan.Configuration.ProviderConfig[pkg.prefix] = providerConf{
+				Name:        pkg.prefix,
+				Expressions: map[string]interface{}{"region": map[string]interface{}{"constant_value": region}},
+			}
+		}
+	}
+}
+
+// planResource converts a Pulumi resource to a Terraform plan JSON resource.
+// It returns false if the resource isn't a custom resource of a bridged
+// provider, e.g. a component resource or the stack itself.
+func (c *converter) planResource(r *resource) (planResource, bool) {
+	t, ok := c.managedType(r)
+	if !ok {
+		return planResource{}, false
+	}
+
+	addr := c.address(r.URN, t)
+
+	// the inputs take precedence over the outputs, since the outputs are
+	// unknown for the resources that are created by a preview.
+	values := map[string]interface{}{}
+	for k, v := range r.Outputs {
+		values[k] = v
+	}
+	for k, v := range r.Inputs {
+		values[k] = v
+	}
+
+	return planResource{
+		Address:      addr,
+		Mode:         "managed",
+		Type:         t,
+		Name:         strings.TrimPrefix(addr, t+"."),
+		ProviderName: bridgedPackages[strings.Split(r.Type, ":")[0]].provider,
+		Values:       convertObject(values),
+	}, true
+}
+
+// managedType returns the Terraform resource type of a custom resource of a
+// bridged provider.
+func (c *converter) managedType(r *resource) (string, bool) {
+	if !r.Custom || strings.HasPrefix(r.Type, providerTypePrefix) {
+		return "", false
+	}
+
+	t, ok := resourceType(r.Type)
+	if !ok {
+		logging.Logger.Debugf("Skipping Pulumi resource %s since %s is not from a Terraform-bridged provider", r.URN, r.Type)
+		return "", false
+	}
+
+	return t, true
+}
+
+// confResource returns the configuration of a resource, with the provider
+// config and the references of the properties that depend on other resources.
+func (c *converter) confResource(r *resource, res planResource) confResource {
+	conf := confResource{
+		Address:           res.Address,
+		Mode:              res.Mode,
+		Type:              res.Type,
+		Name:              res.Name,
+		ProviderConfigKey: c.providerKeys[providerURN(r.Provider)],
+	}
+
+	if conf.ProviderConfigKey == "" {
+		conf.ProviderConfigKey = strings.Split(res.Type, "_")[0]
+	}
+
+	for prop, urns := range r.PropertyDependencies {
+		var refs []string
+		for _, urn := range urns {
+			if addr, ok := c.addresses[urn]; ok {
+				refs = append(refs, addr)
+			}
+		}
+
+		if len(refs) == 0 {
+			continue
+		}
+
+		if conf.Expressions == nil {
+			conf.Expressions = map[string]interface{}{}
+		}
+		conf.Expressions[snakeCase(prop)] = map[string]interface{}{"references": refs}
+	}
+
+	return conf
+}
+
+// address returns the address of the resource with the URN. Resources with
+// the same name and type get a numeric suffix, in the order that they're
+// first seen.
+func (c *converter) address(urn, resourceType string) string {
+	if addr, ok := c.addresses[urn]; ok {
+		return addr
+	}
+
+	base := fmt.Sprintf("%s.%s", resourceType, sanitizeName(urnName(urn)))
+	addr := base
+	for i := 2; ; i++ {
+		if _, ok := c.names[addr]; !ok {
+			break
+		}
+
+		addr = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	c.names[addr] = struct{}{}
+	c.addresses[urn] = addr
+
+	return addr
+}
+
+// convertObject converts the properties of a Pulumi object to Terraform
+// attributes. Property names are converted to snake_case and nested objects to
+// a list with a single block, as they are in Terraform. Lists of nested blocks
+// are also added under the singular name, since the bridge pluralizes them.
+func convertObject(obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if strings.HasPrefix(k, "__") {
+			continue
+		}
+
+		name := snakeCase(k)
+		value := convertValue(name, v)
+		out[name] = value
+
+		if list, ok := value.([]interface{}); ok && len(list) > 0 {
+			if _, isBlock := list[0].(map[string]interface{}); isBlock {
+				if s := singular(name); s != name {
+					if _, exists := obj[s]; !exists {
+						out[s] = value
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+func convertValue(name string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if val == unknownValue {
+			return nil
+		}
+
+		return val
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if _, special := obj[signatureKey]; !special {
+					out = append(out, convertObject(obj))
+					continue
+				}
+			}
+
+			out = append(out, convertValue("", item))
+		}
+
+		return out
+	case map[string]interface{}:
+		if sig, ok := val[signatureKey]; ok {
+			return convertSpecial(sig, val)
+		}
+
+		if _, ok := mapAttributes[name]; ok || name == "" {
+			out := make(map[string]interface{}, len(val))
+			for k, item := range val {
+				out[k] = convertValue("", item)
+			}
+
+			return out
+		}
+
+		return []interface{}{convertObject(val)}
+	}
+
+	return v
+}
+
+// convertSpecial returns the value of a secret, if it's in plaintext. Other
+// values with a signature, like assets and archives, are null.
+func convertSpecial(sig interface{}, val map[string]interface{}) interface{} {
+	if sig != secretSignature {
+		return nil
+	}
+
+	if inner, ok := val["value"]; ok {
+		return convertValue("", inner)
+	}
+
+	plaintext, ok := val["plaintext"].(string)
+	if !ok {
+		return nil
+	}
+
+	var inner interface{}
+	if err := json.Unmarshal([]byte(plaintext), &inner); err != nil {
+		return nil
+	}
+
+	return convertValue("", inner)
+}
+
+func changeActions(op string) []string {
+	switch op {
+	case "create", "import":
+		return []string{"create"}
+	case "delete":
+		return []string{"delete"}
+	case "replace", "create-replacement", "delete-replaced":
+		return []string{"delete", "create"}
+	}
+
+	return []string{"update"}
+}
+
+// urnName returns the name of the resource from its URN, which has the form
+// urn:pulumi:<stack>::<project>::<type>::<name>.
+func urnName(urn string) string {
+	parts := strings.SplitN(urn, "::", 4)
+	return parts[len(parts)-1]
+}
+
+// providerURN returns the URN of a provider reference, which has the form
+// <urn>::<id>.
+func providerURN(ref string) string {
+	i := strings.LastIndex(ref, "::")
+	if i == -1 {
+		return ref
+	}
+
+	return ref[:i]
+}
+
+func sanitizeName(name string) string {
+	return invalidNameChars.ReplaceAllString(name, "_")
+}
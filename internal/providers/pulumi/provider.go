@@ -0,0 +1,80 @@
+package pulumi
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Provider loads the resources of a Pulumi stack from the output of pulumi
+// preview --json or pulumi stack export. Resources of Terraform-bridged
+// providers have the same properties as the Terraform resources they wrap, so
+// they're converted to a Terraform plan JSON and priced using the Terraform
+// resource registry.
+type Provider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	projectType          string
+	includePastResources bool
+}
+
+// NewPreviewJSONProvider returns a Provider for the output of pulumi preview --json.
+func NewPreviewJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &Provider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		projectType:          "pulumi_preview_json",
+		includePastResources: includePastResources,
+	}
+}
+
+// NewStackJSONProvider returns a Provider for the output of pulumi stack export.
+func NewStackJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &Provider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		projectType:          "pulumi_stack_json",
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *Provider) Type() string {
+	return p.projectType
+}
+
+func (p *Provider) DisplayType() string {
+	if p.projectType == "pulumi_stack_json" {
+		return "Pulumi stack export JSON file"
+	}
+
+	return "Pulumi preview JSON file"
+}
+
+func (p *Provider) AddMetadata(metadata *schema.ProjectMetadata) {
+	// no op
+}
+
+func (p *Provider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error reading Pulumi JSON file %w", err)
+	}
+
+	j, err := toPlanJSON(b)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error parsing Pulumi JSON file %w", err)
+	}
+
+	project, err := terraform.NewPlanJSONProvider(p.ctx, p.includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return []*schema.Project{project}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	return []*schema.Project{project}, nil
+}
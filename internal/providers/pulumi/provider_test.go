@@ -0,0 +1,116 @@
+package pulumi
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func loadResources(t *testing.T, path string) *schema.Project {
+	t.Helper()
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: path}, nil)
+	provider := NewPreviewJSONProvider(ctx, true)
+	if filepath.Base(path) == "stack.json" {
+		provider = NewStackJSONProvider(ctx, true)
+	}
+
+	projects, err := provider.LoadResources(map[string]*schema.UsageData{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	return projects[0]
+}
+
+func resourceData(partials []*schema.PartialResource) map[string]*schema.ResourceData {
+	m := make(map[string]*schema.ResourceData, len(partials))
+	for _, partial := range partials {
+		m[partial.ResourceData.Address] = partial.ResourceData
+	}
+
+	return m
+}
+
+func TestPreviewJSON(t *testing.T) {
+	project := loadResources(t, filepath.Join("testdata", "preview.json"))
+	assert.Equal(t, "pulumi_preview_json", project.Metadata.Type)
+
+	planned := resourceData(project.PartialResources)
+	assert.ElementsMatch(t, []string{"aws_instance.web", "aws_db_instance.db", "aws_eip.web-ip", "aws_nat_gateway.nat", "ibm_is_instance.vsi"}, keys(planned))
+
+	web := planned["aws_instance.web"]
+	assert.Equal(t, "m5.large", web.Get("instance_type").String())
+	assert.Equal(t, int64(50), web.Get("root_block_device.0.volume_size").Int())
+	assert.Equal(t, "gp3", web.Get("ebs_block_device.0.volume_type").String())
+	assert.Equal(t, "#!/bin/bash", web.Get("user_data").String())
+	assert.Equal(t, "123", web.Get("tags.costCenter").String())
+	assert.Equal(t, "eu-west-1", web.Get("region").String())
+
+	db := planned["aws_db_instance.db"]
+	assert.Equal(t, "db.t3.medium", db.Get("instance_class").String())
+	assert.Equal(t, "eu-west-1", db.Get("region").String())
+
+	nat := planned["aws_nat_gateway.nat"]
+	require.Len(t, nat.References("allocation_id"), 1)
+	assert.Equal(t, "aws_eip.web-ip", nat.References("allocation_id")[0].Address)
+
+	assert.Equal(t, "us-south", planned["ibm_is_instance.vsi"].Get("region").String())
+
+	past := resourceData(project.PartialPastResources)
+	assert.ElementsMatch(t, []string{"aws_instance.web", "aws_ebs_volume.data"}, keys(past))
+	assert.Equal(t, "t3.micro", past["aws_instance.web"].Get("instance_type").String())
+	assert.Equal(t, int64(100), past["aws_ebs_volume.data"].Get("size").Int())
+}
+
+func TestStackJSON(t *testing.T) {
+	project := loadResources(t, filepath.Join("testdata", "stack.json"))
+	assert.Equal(t, "pulumi_stack_json", project.Metadata.Type)
+
+	for _, partials := range [][]*schema.PartialResource{project.PartialResources, project.PartialPastResources} {
+		resources := resourceData(partials)
+		assert.Equal(t, []string{"aws_instance.web"}, keys(resources))
+		assert.Equal(t, "t3.large", resources["aws_instance.web"].Get("instance_type").String())
+		assert.Equal(t, "us-west-2", resources["aws_instance.web"].Get("region").String())
+	}
+}
+
+func TestResourceType(t *testing.T) {
+	tests := []struct {
+		token    string
+		expected string
+		ok       bool
+	}{
+		{"aws:ec2/instance:Instance", "aws_instance", true},
+		{"aws:ebs/volume:Volume", "aws_ebs_volume", true},
+		{"aws:lambda/function:Function", "aws_lambda_function", true},
+		{"aws:rds/instance:Instance", "aws_db_instance", true},
+		{"aws:rds/cluster:Cluster", "aws_rds_cluster", true},
+		{"gcp:compute/instance:Instance", "google_compute_instance", true},
+		{"azure:compute/linuxVirtualMachine:LinuxVirtualMachine", "azurerm_linux_virtual_machine", true},
+		{"ibm:index/isInstance:IsInstance", "ibm_is_instance", true},
+		{"random:index/randomString:RandomString", "", false},
+		{"pulumi:pulumi:Stack", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			actual, ok := resourceType(tt.token)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func keys(m map[string]*schema.ResourceData) []string {
+	k := make([]string, 0, len(m))
+	for addr := range m {
+		k = append(k, addr)
+	}
+
+	return k
+}
@@ -0,0 +1,119 @@
+package pulumi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// bridgedPackages maps the Pulumi packages that bridge a Terraform provider to
+// the prefix of the Terraform resource types and the Terraform provider name.
+var bridgedPackages = map[string]struct {
+	prefix   string
+	provider string
+}{
+	"aws":   {prefix: "aws", provider: "registry.terraform.io/hashicorp/aws"},
+	"azure": {prefix: "azurerm", provider: "registry.terraform.io/hashicorp/azurerm"},
+	"gcp":   {prefix: "google", provider: "registry.terraform.io/hashicorp/google"},
+	"ibm":   {prefix: "ibm", provider: "registry.terraform.io/ibm-cloud/ibm"},
+}
+
+// typeOverrides are the Terraform resource types of the type tokens that
+// resourceType can't derive, because the bridge renamed the resource.
+var typeOverrides = map[string]string{
+	"aws:alb/loadBalancer:LoadBalancer":                         "aws_alb",
+	"aws:apigateway/restApi:RestApi":                            "aws_api_gateway_rest_api",
+	"aws:apigateway/stage:Stage":                                "aws_api_gateway_stage",
+	"aws:ec2clientvpn/endpoint:Endpoint":                        "aws_ec2_client_vpn_endpoint",
+	"aws:ec2clientvpn/networkAssociation:NetworkAssociation":    "aws_ec2_client_vpn_network_association",
+	"aws:ec2transitgateway/peeringAttachment:PeeringAttachment": "aws_ec2_transit_gateway_peering_attachment",
+	"aws:ec2transitgateway/transitGateway:TransitGateway":       "aws_ec2_transit_gateway",
+	"aws:ec2transitgateway/vpcAttachment:VpcAttachment":         "aws_ec2_transit_gateway_vpc_attachment",
+	"aws:elb/loadBalancer:LoadBalancer":                         "aws_elb",
+	"aws:lb/loadBalancer:LoadBalancer":                          "aws_lb",
+	"aws:rds/instance:Instance":                                 "aws_db_instance",
+	"aws:s3/bucketV2:BucketV2":                                  "aws_s3_bucket",
+}
+
+// resourceType returns the Terraform resource type of a bridged Pulumi type
+// token, e.g. aws_instance for aws:ec2/instance:Instance and ibm_is_instance
+// for ibm:index/isInstance:IsInstance. It returns false if the token isn't
+// from a bridged package.
+//
+// The bridge names most resources after the Terraform type with the prefix
+// removed, sometimes also removing the Pulumi module from the name. So the type
+// with the module is used if it's in the resource registry, and otherwise the
+// type without it.
+func resourceType(token string) (string, bool) {
+	if t, ok := typeOverrides[token]; ok {
+		return t, true
+	}
+
+	parts := strings.Split(token, ":")
+	if len(parts) != 3 {
+		return "", false
+	}
+
+	pkg, ok := bridgedPackages[parts[0]]
+	if !ok {
+		return "", false
+	}
+
+	module, name, ok := strings.Cut(parts[1], "/")
+	if !ok {
+		return "", false
+	}
+
+	withoutModule := pkg.prefix + "_" + snakeCase(name)
+	if module == "index" {
+		return withoutModule, true
+	}
+
+	withModule := pkg.prefix + "_" + strings.ToLower(module) + "_" + snakeCase(name)
+	registry := *terraform.GetResourceRegistryMap()
+	if _, ok := registry[withModule]; ok {
+		return withModule, true
+	}
+
+	if _, ok := registry[withoutModule]; ok {
+		return withoutModule, true
+	}
+
+	return withModule, true
+}
+
+// snakeCase converts the camelCase names of Pulumi types and properties to the
+// snake_case names of Terraform, e.g. instanceType to instance_type.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// singular returns the singular of the pluralized property names that the
+// bridge uses for Terraform nested blocks, e.g. ebs_block_devices for
+// ebs_block_device.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+
+	return s
+}
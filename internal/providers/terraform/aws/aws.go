@@ -96,8 +96,13 @@ func GetResourceRegion(resourceType string, v gjson.Result) string {
 	return p[3]
 }
 
+// ParseTags returns the tags of the resource. tags_all is set in plans and
+// includes the default tags of the provider.
 func ParseTags(resourceType string, v gjson.Result) map[string]string {
 	tags := make(map[string]string)
+	for k, v := range v.Get("tags_all").Map() {
+		tags[k] = v.String()
+	}
 	for k, v := range v.Get("tags").Map() {
 		tags[k] = v.String()
 	}
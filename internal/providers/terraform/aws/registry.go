@@ -464,6 +464,7 @@ var FreeResources = []string{
 
 	// Hashicorp
 	"null_resource",
+	"terraform_data",
 	"local_file",
 	"template_dir",
 	"random_id",
@@ -25,7 +25,12 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var minTerraformVer = "v0.12"
+var (
+	minTerraformVer = "v0.12"
+	minOpenTofuVer  = "v1.6"
+
+	openTofuBinary = "tofu"
+)
 
 type DirProvider struct {
 	ctx                  *config.ProjectContext
@@ -89,6 +94,17 @@ func (p *DirProvider) checks() error {
 	p.ctx.SetContextValue("terraformBinary", binary)
 
 	_, err := exec.LookPath(binary)
+	if err != nil && p.ctx.ProjectConfig.TerraformBinary == "" {
+		// Use the OpenTofu binary if there's no Terraform binary and a custom binary
+		// hasn't been set.
+		if _, tofuErr := exec.LookPath(openTofuBinary); tofuErr == nil {
+			binary = openTofuBinary
+			p.TerraformBinary = binary
+			p.ctx.SetContextValue("terraformBinary", binary)
+			err = nil
+		}
+	}
+
 	if err != nil {
 		msg := fmt.Sprintf("Terraform binary '%s' could not be found. You have two options:\n", binary)
 		msg += "1. Set a custom Terraform binary using the environment variable INFRACOST_TERRAFORM_BINARY.\n\n"
@@ -191,6 +207,7 @@ func (p *DirProvider) LoadResources(usage map[string]*schema.UsageData) ([]*sche
 			project.PartialPastResources = pastpartialResources
 		}
 		project.PartialResources = partialResources
+		project.Metadata.ForgottenResources = forgottenAddresses(j)
 
 		projects = append(projects, project)
 	}
@@ -510,16 +527,28 @@ func checkTerraformVersion(v string, fullV string) error {
 		return fmt.Errorf("Terraform %s is not supported. Please use Terraform version >= %s. Update it or set the environment variable INFRACOST_TERRAFORM_BINARY.", v, minTerraformVer) //nolint
 	}
 
-	// Allow any non-terraform and non-terragrunt binaries
+	if strings.HasPrefix(fullV, "OpenTofu ") && semver.Compare(v, minOpenTofuVer) < 0 {
+		return fmt.Errorf("OpenTofu %s is not supported. Please use OpenTofu version >= %s. Update it or set the environment variable INFRACOST_TERRAFORM_BINARY.", v, minOpenTofuVer) //nolint
+	}
+
+	// Allow any non-terraform, non-tofu and non-terragrunt binaries
 	return nil
 }
 
+// isOpenTofuBinary returns if the binary is the OpenTofu binary, which has the
+// same commands as Terraform.
+func isOpenTofuBinary(binary string) bool {
+	return strings.TrimSuffix(filepath.Base(binary), ".exe") == openTofuBinary
+}
+
 func (p *DirProvider) buildTerraformErr(err error, isInit bool) error {
 	stderr := extractStderr(err)
 
 	binName := "Terraform"
 	if p.IsTerragrunt {
 		binName = "Terragrunt"
+	} else if isOpenTofuBinary(p.TerraformBinary) {
+		binName = "OpenTofu"
 	}
 
 	msg := ""
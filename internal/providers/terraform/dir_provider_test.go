@@ -0,0 +1,41 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTerraformVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		fullVersion   string
+		expectedError string
+	}{
+		{name: "supported terraform", fullVersion: "Terraform v1.5.7"},
+		{name: "unsupported terraform", fullVersion: "Terraform v0.11.14", expectedError: "Terraform v0.11.14 is not supported. Please use Terraform version >= v0.12."},
+		{name: "supported opentofu", fullVersion: "OpenTofu v1.8.2"},
+		{name: "unsupported opentofu", fullVersion: "OpenTofu v1.5.0", expectedError: "OpenTofu v1.5.0 is not supported. Please use OpenTofu version >= v1.6."},
+		{name: "other binary", fullVersion: "terragrunt version v0.11.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTerraformVersion(shortTerraformVersion(tt.fullVersion), tt.fullVersion)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestIsOpenTofuBinary(t *testing.T) {
+	assert.True(t, isOpenTofuBinary("tofu"))
+	assert.True(t, isOpenTofuBinary("/usr/local/bin/tofu"))
+	assert.True(t, isOpenTofuBinary("tofu.exe"))
+	assert.False(t, isOpenTofuBinary("terraform"))
+	assert.False(t, isOpenTofuBinary("terragrunt"))
+}
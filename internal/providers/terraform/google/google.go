@@ -30,8 +30,13 @@ func GetResourceRegion(resourceType string, v gjson.Result) string {
 	return ""
 }
 
+// ParseTags returns the labels of the resource. terraform_labels is set in
+// plans and includes the default labels of the provider.
 func ParseTags(resourceType string, v gjson.Result) map[string]string {
 	tags := make(map[string]string)
+	for k, v := range v.Get("terraform_labels").Map() {
+		tags[k] = v.String()
+	}
 	for k, v := range v.Get("labels").Map() {
 		tags[k] = v.String()
 	}
@@ -0,0 +1,99 @@
+package terraform
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// addHCLDiagnostics adds the diagnostics of the evaluated module to the project
+// metadata and prints them, unless the output format is JSON. Only the affected
+// resources that are priced are listed for each diagnostic.
+func (p *HCLProvider) addHCLDiagnostics(metadata *schema.ProjectMetadata, module *hcl.Module, partials []*schema.PartialResource) {
+	if !p.ctx.RunContext.Config.HCLDiagnostics {
+		return
+	}
+
+	diags := hclDiagnostics(module, partials)
+	metadata.HCLDiagnostics = append(metadata.HCLDiagnostics, diags...)
+
+	if p.ctx.RunContext.Config.Format != "json" {
+		printHCLDiagnostics(p.ctx.RunContext.ErrWriter, module.RootPath, diags)
+	}
+}
+
+// hclDiagnostics converts the hcl.Diagnostics of the module and its child
+// modules to schema.HCLDiagnostic. Filenames are relative to the root path of
+// the module.
+func hclDiagnostics(module *hcl.Module, partials []*schema.PartialResource) []schema.HCLDiagnostic {
+	priced := make(map[string]struct{})
+	for _, partial := range partials {
+		if partial.Resource != nil && (partial.Resource.IsSkipped || partial.Resource.NoPrice) {
+			continue
+		}
+
+		if partial.Resource == nil && partial.CoreResource == nil {
+			continue
+		}
+
+		priced[partial.ResourceData.Address] = struct{}{}
+	}
+
+	all := module.AllDiagnostics()
+	diags := make([]schema.HCLDiagnostic, 0, len(all))
+	for _, d := range all {
+		filename := d.Range.Filename
+		if rel, err := filepath.Rel(module.RootPath, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+
+		var resources []string
+		for _, addr := range d.Resources {
+			if _, ok := priced[addr]; ok {
+				resources = append(resources, addr)
+			}
+		}
+
+		diags = append(diags, schema.HCLDiagnostic{
+			Kind:      string(d.Kind),
+			Address:   d.Address,
+			Message:   d.Message,
+			Filename:  filepath.ToSlash(filename),
+			StartLine: d.Range.Start.Line,
+			EndLine:   d.Range.End.Line,
+			Resources: resources,
+		})
+	}
+
+	return diags
+}
+
+func printHCLDiagnostics(w io.Writer, path string, diags []schema.HCLDiagnostic) {
+	if len(diags) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(ui.BoldStringf("HCL diagnostics for %s\n", ui.DisplayPath(path)))
+
+	for _, d := range diags {
+		lines := fmt.Sprintf("%s:%d", d.Filename, d.StartLine)
+		if d.EndLine > d.StartLine {
+			lines = fmt.Sprintf("%s-%d", lines, d.EndLine)
+		}
+
+		b.WriteString(fmt.Sprintf("  %s %s %s\n", lines, ui.WarningString(d.Kind), d.Address))
+		b.WriteString(fmt.Sprintf("    %s\n", d.Message))
+
+		if len(d.Resources) > 0 {
+			b.WriteString(ui.FaintStringf("    Affects priced resources: %s\n", strings.Join(d.Resources, ", ")))
+		}
+	}
+
+	fmt.Fprint(w, b.String())
+}
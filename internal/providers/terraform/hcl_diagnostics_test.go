@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestHCLDiagnostics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	path := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(path, "main.tf"), []byte(`
+variable "instance_type" {}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = var.instance_type
+}
+
+resource "aws_eip" "web" {
+  instance = aws_instance.web.id
+  domain   = var.instance_type
+}
+`), 0600))
+
+	parsers, err := hcl.LoadParsers(path, modules.NewModuleLoader(path, nil, entry, &sync.KeyMutex{}), nil, entry, hcl.OptionWithDiagnostics())
+	require.NoError(t, err)
+	module, err := parsers[0].ParseDirectory()
+	require.NoError(t, err)
+
+	partials := []*schema.PartialResource{
+		{ResourceData: &schema.ResourceData{Address: "aws_instance.web"}, Resource: &schema.Resource{Name: "aws_instance.web"}},
+		{ResourceData: &schema.ResourceData{Address: "aws_eip.web"}, Resource: &schema.Resource{Name: "aws_eip.web", NoPrice: true}},
+	}
+
+	diags := hclDiagnostics(module, partials)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "missing_var", diags[0].Kind)
+	assert.Equal(t, "var.instance_type", diags[0].Address)
+	assert.Equal(t, "main.tf", diags[0].Filename)
+	assert.Equal(t, 2, diags[0].StartLine)
+	assert.Equal(t, []string{"aws_instance.web"}, diags[0].Resources)
+}
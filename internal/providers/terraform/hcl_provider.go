@@ -32,6 +32,7 @@ import (
 type HCLProvider struct {
 	scanner        *scan.TerraformPlanScanner
 	parsers        []*hcl.Parser
+	matrix         *config.ProjectMatrix
 	planJSONParser *Parser
 	logger         *log.Entry
 
@@ -88,9 +89,76 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		config = &HCLProviderConfig{}
 	}
 
+	options, credsSource, err := hclParserOptions(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := ctx.Logger().WithFields(log.Fields{"provider": "terraform_dir"})
+	runCtx := ctx.RunContext
+	locatorConfig := &hcl.ProjectLocatorConfig{ExcludedSubDirs: ctx.ProjectConfig.ExcludePaths, ChangedObjects: runCtx.VCSMetadata.Commit.ChangedObjects, UseAllPaths: ctx.ProjectConfig.IncludeAllPaths}
+
+	loader, err := newModuleLoader(ctx, credsSource, logger)
+	if err != nil {
+		return nil, err
+	}
+	parsers, err := hcl.LoadParsers(
+		ctx.ProjectConfig.Path,
+		loader,
+		locatorConfig,
+		logger,
+		options...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var scanner *scan.TerraformPlanScanner
+	if runCtx.Config.PolicyAPIEndpoint != "" {
+		scanner = scan.NewTerraformPlanScanner(runCtx, ctx.Logger(), prices.GetPrices)
+	}
+
+	return &HCLProvider{
+		scanner:        scanner,
+		parsers:        parsers,
+		matrix:         ctx.ProjectConfig.Matrix,
+		planJSONParser: NewParser(ctx, false),
+		ctx:            ctx,
+		config:         *config,
+		logger:         logger,
+	}, err
+}
+
+// newModuleLoader returns the modules.ModuleLoader for the project, which loads
+// registry and remote modules from the module mirror if the project has one.
+func newModuleLoader(ctx *config.ProjectContext, credsSource *modules.CredentialsSource, logger *log.Entry) (*modules.ModuleLoader, error) {
+	repoPath := ctx.RunContext.Config.RepoPath()
+	loader := modules.NewModuleLoader(repoPath, credsSource, logger, ctx.RunContext.ModuleMutex)
+
+	if ctx.ProjectConfig.ModuleMirror == "" {
+		return loader, nil
+	}
+
+	mirrorPath := ctx.ProjectConfig.ModuleMirror
+	if !filepath.IsAbs(mirrorPath) {
+		mirrorPath = filepath.Join(repoPath, mirrorPath)
+	}
+
+	mirror, err := modules.LoadMirror(mirrorPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load module mirror %s: %w", mirrorPath, err)
+	}
+	loader.SetMirror(mirror)
+
+	return loader, nil
+}
+
+// hclParserOptions returns the hcl.Parser options for the input vars and
+// files of the project, followed by the given options, along with the
+// credentials used to download modules.
+func hclParserOptions(ctx *config.ProjectContext, opts ...hcl.Option) ([]hcl.Option, *modules.CredentialsSource, error) {
 	v, err := varsFromPlanFlags(ctx.ProjectConfig.TerraformPlanFlags)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse vars from plan flags %w", err)
+		return nil, nil, fmt.Errorf("could not parse vars from plan flags %w", err)
 	}
 
 	options := []hcl.Option{hcl.OptionWithTFEnvVars(ctx.ProjectConfig.Env)}
@@ -111,6 +179,23 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		options = append(options, withInputVars)
 	}
 
+	if ctx.RunContext.Config.HCLDiagnostics {
+		options = append(options, hcl.OptionWithDiagnostics())
+	}
+
+	if ctx.ProjectConfig.DataMocks != "" {
+		options = append(options, hcl.OptionWithDataMocksPath(ctx.ProjectConfig.DataMocks))
+	}
+
+	if len(ctx.ProjectConfig.RemoteStates) > 0 {
+		states, err := remoteStates(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		options = append(options, hcl.OptionWithRemoteStates(states))
+	}
+
 	options = append(options, opts...)
 
 	credsSource, err := modules.NewTerraformCredentialsSource(modules.BaseCredentialSet{
@@ -130,35 +215,7 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		hcl.OptionWithTerraformWorkspace(localWorkspace),
 	)
 
-	logger := ctx.Logger().WithFields(log.Fields{"provider": "terraform_dir"})
-	runCtx := ctx.RunContext
-	locatorConfig := &hcl.ProjectLocatorConfig{ExcludedSubDirs: ctx.ProjectConfig.ExcludePaths, ChangedObjects: runCtx.VCSMetadata.Commit.ChangedObjects, UseAllPaths: ctx.ProjectConfig.IncludeAllPaths}
-
-	path := ctx.RunContext.Config.RepoPath()
-	loader := modules.NewModuleLoader(path, credsSource, logger, ctx.RunContext.ModuleMutex)
-	parsers, err := hcl.LoadParsers(
-		ctx.ProjectConfig.Path,
-		loader,
-		locatorConfig,
-		logger,
-		options...,
-	)
-	if err != nil {
-		return nil, err
-	}
-	var scanner *scan.TerraformPlanScanner
-	if runCtx.Config.PolicyAPIEndpoint != "" {
-		scanner = scan.NewTerraformPlanScanner(runCtx, ctx.Logger(), prices.GetPrices)
-	}
-
-	return &HCLProvider{
-		scanner:        scanner,
-		parsers:        parsers,
-		planJSONParser: NewParser(ctx, false),
-		ctx:            ctx,
-		config:         *config,
-		logger:         logger,
-	}, err
+	return options, credsSource, nil
 }
 
 func (p *HCLProvider) Type() string        { return "terraform_dir" }
@@ -187,6 +244,12 @@ func (p *HCLProvider) LoadResources(usage map[string]*schema.UsageData) ([]*sche
 		return nil, err
 	}
 
+	// the outputs can only be used by other projects if they belong to a single
+	// root module.
+	if len(jsons) == 1 {
+		setProjectOutputs(p.ctx, jsons[0].Module)
+	}
+
 	var projects = make([]*schema.Project, len(jsons))
 	for i, j := range jsons {
 		project, err := p.parseResources(j, usage)
@@ -220,29 +283,67 @@ func (p *HCLProvider) parseResources(parsed HCLProject, usage map[string]*schema
 
 	project.PartialPastResources = partialPastResources
 	project.PartialResources = partialResources
+	project.Metadata.ForgottenResources = forgottenAddresses(parsed.JSON)
+
+	if addrs := unmockedPricedDataSources(parsed.Module.UnmockedDataSources, partialResources); len(addrs) > 0 {
+		p.addWarning(project.Metadata, hcl.NewUnmockedDataSourcesWarning(addrs))
+	}
+
+	p.addHCLDiagnostics(project.Metadata, parsed.Module, partialResources)
 
 	return project, nil
 }
 
-func (p *HCLProvider) newProject(parsed HCLProject) *schema.Project {
-	metadata := config.DetectProjectMetadata(parsed.Module.RootPath)
-	metadata.Type = p.Type()
-	p.AddMetadata(metadata)
+// unmockedPricedDataSources returns the data sources without data mocks that
+// are referenced by resources that are priced.
+func unmockedPricedDataSources(unmocked map[string][]string, partials []*schema.PartialResource) []string {
+	if len(unmocked) == 0 {
+		return nil
+	}
 
-	if len(parsed.Module.Warnings) > 0 {
-		warnings := make([]schema.Warning, len(parsed.Module.Warnings))
+	seen := map[string]struct{}{}
+	var addrs []string
+	for _, partial := range partials {
+		if partial.Resource != nil && (partial.Resource.IsSkipped || partial.Resource.NoPrice) {
+			continue
+		}
+
+		if partial.Resource == nil && partial.CoreResource == nil {
+			continue
+		}
 
-		for i, warning := range parsed.Module.Warnings {
-			warnings[i] = schema.Warning{
-				Code:    int(warning.Code),
-				Message: warning.Title,
-				Data:    warning.Data,
+		for _, addr := range unmocked[partial.ResourceData.Address] {
+			if _, ok := seen[addr]; ok {
+				continue
 			}
 
-			ui.PrintWarning(p.ctx.RunContext.ErrWriter, warning.FriendlyMessage)
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
 		}
+	}
 
-		metadata.Warnings = warnings
+	sort.Strings(addrs)
+	return addrs
+}
+
+// addWarning adds the hcl.Warning to the project metadata and prints it.
+func (p *HCLProvider) addWarning(metadata *schema.ProjectMetadata, warning hcl.Warning) {
+	metadata.Warnings = append(metadata.Warnings, schema.Warning{
+		Code:    int(warning.Code),
+		Message: warning.Title,
+		Data:    warning.Data,
+	})
+
+	ui.PrintWarning(p.ctx.RunContext.ErrWriter, warning.FriendlyMessage)
+}
+
+func (p *HCLProvider) newProject(parsed HCLProject) *schema.Project {
+	metadata := config.DetectProjectMetadata(parsed.Module.RootPath)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+
+	for _, warning := range parsed.Module.Warnings {
+		p.addWarning(metadata, warning)
 	}
 
 	name := p.ctx.ProjectConfig.Name
@@ -250,6 +351,14 @@ func (p *HCLProvider) newProject(parsed HCLProject) *schema.Project {
 		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
 	}
 
+	if variant := parsed.Module.Variant; variant != nil {
+		name = fmt.Sprintf("%s-%s", name, variant.Name)
+		metadata.Environment = variant.Name
+		if variant.Workspace != "" {
+			metadata.TerraformWorkspace = variant.Workspace
+		}
+	}
+
 	return schema.NewProject(name, metadata)
 }
 
@@ -260,12 +369,13 @@ type HCLProject struct {
 
 // LoadPlanJSONs parses the found directories and return the blocks in Terraform plan JSON format.
 func (p *HCLProvider) LoadPlanJSONs() ([]HCLProject, error) {
-	var jsons = make([]HCLProject, len(p.parsers))
 	modules, err := p.Modules()
 	if err != nil {
 		return nil, err
 	}
 
+	var jsons = make([]HCLProject, len(modules))
+
 	for i, module := range modules {
 		b, err := p.modulesToPlanJSON(module)
 		if err != nil {
@@ -332,13 +442,13 @@ func (p *HCLProvider) Modules() ([]*hcl.Module, error) {
 					fmt.Fprintf(os.Stderr, "Detected Terraform project at %s\n", ui.DisplayPath(parser.Path()))
 				}
 
-				module, err := parser.ParseDirectory()
+				parsed, err := p.parse(parser)
 				if err != nil {
 					return err
 				}
 
 				lock.Lock()
-				mods = append(mods, module)
+				mods = append(mods, parsed...)
 				lock.Unlock()
 			}
 
@@ -355,7 +465,8 @@ func (p *HCLProvider) Modules() ([]*hcl.Module, error) {
 		p.cache = mods
 	}
 
-	sort.Slice(mods, func(i, j int) bool {
+	// the modules of each matrix combination keep the order of the matrix.
+	sort.SliceStable(mods, func(i, j int) bool {
 		if mods[i].Name != "" && mods[j].Name != "" {
 			return mods[i].Name < mods[j].Name
 		}
@@ -366,6 +477,42 @@ func (p *HCLProvider) Modules() ([]*hcl.Module, error) {
 	return mods, nil
 }
 
+// parse parses the directory of the parser into a root module, or into a root
+// module for each combination of the project matrix.
+func (p *HCLProvider) parse(parser *hcl.Parser) ([]*hcl.Module, error) {
+	variants, err := matrixVariants(p.matrix, parser.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(variants) > 0 {
+		return parser.ParseVariants(variants)
+	}
+
+	module, err := parser.ParseDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	return []*hcl.Module{module}, nil
+}
+
+// LoadModules downloads the modules of each found Terraform project without
+// evaluating them and returns the module manifest of each project.
+func (p *HCLProvider) LoadModules() ([]*modules.Manifest, error) {
+	manifests := make([]*modules.Manifest, 0, len(p.parsers))
+	for _, parser := range p.parsers {
+		manifest, err := parser.LoadModules()
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, nil
+}
+
 // InvalidateCache removes the module cache from the prior hcl parse.
 func (p *HCLProvider) InvalidateCache() *HCLProvider {
 	p.cache = nil
@@ -403,6 +550,7 @@ func (p *HCLProvider) modulesToPlanJSON(rootModule *hcl.Module) ([]byte, error)
 	mo := p.marshalModule(rootModule)
 	p.schema.Configuration.RootModule = mo.ModuleConfig
 	p.schema.PlannedValues.RootModule = mo.PlanModule
+	p.marshalConfigDrivenActions(rootModule)
 
 	b, err := json.MarshalIndent(p.schema, "", "  ")
 	if err != nil {
@@ -411,6 +559,37 @@ func (p *HCLProvider) modulesToPlanJSON(rootModule *hcl.Module) ([]byte, error)
 	return b, nil
 }
 
+// marshalConfigDrivenActions adds the changes from moved, import and removed
+// blocks to the resource changes, in the same way as Terraform does in its
+// plan JSON.
+func (p *HCLProvider) marshalConfigDrivenActions(rootModule *hcl.Module) {
+	moves := rootModule.Moves()
+	imports := rootModule.Imports()
+
+	for i, change := range p.schema.ResourceChanges {
+		for _, mv := range moves {
+			if prev, ok := mv.PreviousAddress(change.Address); ok {
+				p.schema.ResourceChanges[i].PreviousAddress = prev
+				break
+			}
+		}
+
+		if id, ok := imports[change.Address]; ok {
+			p.schema.ResourceChanges[i].Change.Importing = &ResourceImporting{ID: id}
+		}
+	}
+
+	for _, addr := range rootModule.ForgottenAddresses() {
+		p.schema.ResourceChanges = append(p.schema.ResourceChanges, ResourceChangesJSON{
+			Address: addr,
+			Mode:    "managed",
+			Change: ResourceChange{
+				Actions: []string{"forget"},
+			},
+		})
+	}
+}
+
 func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 	moduleConfig := ModuleConfig{
 		ModuleCalls: map[string]ModuleCall{},
@@ -464,6 +643,7 @@ func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 }
 
 func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
+	blockRange := block.Range()
 	planned := ResourceJSON{
 		Address:       block.FullName(),
 		Mode:          "managed",
@@ -472,8 +652,10 @@ func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
 		Index:         block.Index(),
 		SchemaVersion: 0,
 		InfracostMetadata: map[string]interface{}{
-			"filename": block.Filename,
-			"calls":    block.CallDetails(),
+			"filename":  block.Filename,
+			"startLine": blockRange.Start.Line,
+			"endLine":   blockRange.End.Line,
+			"calls":     block.CallDetails(),
 		},
 	}
 
@@ -548,18 +730,59 @@ func (p *HCLProvider) marshalProviderBlock(block *hcl.Block) string {
 
 	region := block.GetAttribute("region").AsString()
 
-	p.schema.Configuration.ProviderConfig[name] = ProviderConfig{
-		Name: name,
-		Expressions: map[string]interface{}{
-			"region": map[string]interface{}{
-				"constant_value": region,
-			},
+	expressions := map[string]interface{}{
+		"region": map[string]interface{}{
+			"constant_value": region,
 		},
 	}
 
+	// Default tags are added to the tags of every resource of the provider, so
+	// they are set in the same format as Terraform plan JSON.
+	if tags := attributeStringMap(block.GetChildBlock("default_tags").GetAttribute("tags")); len(tags) > 0 {
+		expressions["default_tags"] = []interface{}{
+			map[string]interface{}{
+				"tags": map[string]interface{}{
+					"constant_value": tags,
+				},
+			},
+		}
+	}
+
+	if labels := attributeStringMap(block.GetAttribute("default_labels")); len(labels) > 0 {
+		expressions["default_labels"] = map[string]interface{}{
+			"constant_value": labels,
+		}
+	}
+
+	p.schema.Configuration.ProviderConfig[name] = ProviderConfig{
+		Name:        name,
+		Expressions: expressions,
+	}
+
 	return name
 }
 
+// attributeStringMap returns the known string values of a map attribute.
+func attributeStringMap(attr *hcl.Attribute) map[string]string {
+	if attr == nil {
+		return nil
+	}
+
+	v := attr.Value()
+	if !v.IsKnown() || v.IsNull() || !(v.Type().IsMapType() || v.Type().IsObjectType()) {
+		return nil
+	}
+
+	m := make(map[string]string)
+	for k, val := range v.AsValueMap() {
+		if val.IsKnown() && !val.IsNull() && val.Type() == cty.String {
+			m[k] = val.AsString()
+		}
+	}
+
+	return m
+}
+
 func (p *HCLProvider) countReferences(block *hcl.Block) *countExpression {
 	for _, attribute := range block.GetAttributes() {
 		name := attribute.Name()
@@ -704,19 +927,25 @@ type ResourceJSON struct {
 }
 
 type ResourceChangesJSON struct {
-	Address       string         `json:"address"`
-	ModuleAddress *string        `json:"module_address,omitempty"`
-	Mode          string         `json:"mode"`
-	Type          string         `json:"type"`
-	Name          string         `json:"name"`
-	Index         *int64         `json:"index,omitempty"`
-	Change        ResourceChange `json:"change"`
+	Address         string         `json:"address"`
+	PreviousAddress string         `json:"previous_address,omitempty"`
+	ModuleAddress   *string        `json:"module_address,omitempty"`
+	Mode            string         `json:"mode"`
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Index           *int64         `json:"index,omitempty"`
+	Change          ResourceChange `json:"change"`
 }
 
 type ResourceChange struct {
-	Actions []string               `json:"actions"`
-	Before  interface{}            `json:"before"`
-	After   map[string]interface{} `json:"after"`
+	Actions   []string               `json:"actions"`
+	Before    interface{}            `json:"before"`
+	After     map[string]interface{} `json:"after"`
+	Importing *ResourceImporting     `json:"importing,omitempty"`
+}
+
+type ResourceImporting struct {
+	ID string `json:"id"`
 }
 
 type PlanSchema struct {
@@ -19,6 +19,7 @@ import (
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/sync"
 )
 
@@ -78,9 +79,10 @@ func addAttrs(attrs map[string]string, body *hclsyntax.Body) {
 
 func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 	tests := []struct {
-		name     string
-		attrs    map[string]map[string]string
-		warnings []hcl.WarningCode
+		name      string
+		attrs     map[string]map[string]string
+		warnings  []hcl.WarningCode
+		dataMocks string
 	}{
 		{
 			name: "structures module expressions correctly with count",
@@ -176,6 +178,32 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 			},
 			warnings: []hcl.WarningCode{hcl.WarningMissingVars},
 		},
+		{
+			name: "renders data mocks",
+			attrs: map[string]map[string]string{
+				"aws_instance.web": {
+					"arn": "web-arn",
+				},
+			},
+			dataMocks: "data_mocks.yml",
+		},
+		{
+			name: "renders moved import and removed blocks",
+			attrs: map[string]map[string]string{
+				"aws_eip.renamed": {
+					"id":  "eip-renamed",
+					"arn": "eip-renamed-arn",
+				},
+				"aws_eip.adopted": {
+					"id":  "eip-adopted",
+					"arn": "eip-adopted-arn",
+				},
+				"terraform_data.replacement": {
+					"id":  "data-replacement",
+					"arn": "data-replacement-arn",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -191,6 +219,7 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 				modules.NewModuleLoader(testPath, nil, entry, &sync.KeyMutex{}),
 				nil,
 				entry,
+				hcl.OptionWithDataMocksPath(tt.dataMocks),
 				hcl.OptionWithBlockBuilder(
 					hcl.BlockBuilder{
 						MockFunc: func(a *hcl.Attribute) cty.Value {
@@ -232,3 +261,24 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmockedPricedDataSources(t *testing.T) {
+	unmocked := map[string][]string{
+		"aws_instance.web":        {"data.aws_ami.ubuntu"},
+		"aws_instance.api":        {"data.aws_ami.ubuntu", "data.aws_subnet.private"},
+		"aws_iam_role.free":       {"data.aws_iam_policy_document.assume"},
+		"aws_instance.skipped":    {"data.aws_ami.skipped"},
+		"module.db.aws_db.legacy": {"module.db.data.aws_kms_key.db"},
+	}
+
+	partials := []*schema.PartialResource{
+		{ResourceData: &schema.ResourceData{Address: "aws_instance.web"}, Resource: &schema.Resource{Name: "aws_instance.web"}},
+		{ResourceData: &schema.ResourceData{Address: "aws_instance.api"}, Resource: &schema.Resource{Name: "aws_instance.api"}},
+		{ResourceData: &schema.ResourceData{Address: "aws_iam_role.free"}, Resource: &schema.Resource{Name: "aws_iam_role.free", NoPrice: true}},
+		{ResourceData: &schema.ResourceData{Address: "aws_instance.skipped"}, Resource: &schema.Resource{Name: "aws_instance.skipped", IsSkipped: true}},
+		{ResourceData: &schema.ResourceData{Address: "module.db.aws_db.legacy"}},
+	}
+
+	assert.Equal(t, []string{"data.aws_ami.ubuntu", "data.aws_subnet.private"}, unmockedPricedDataSources(unmocked, partials))
+	assert.Empty(t, unmockedPricedDataSources(nil, partials))
+}
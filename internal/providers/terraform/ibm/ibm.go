@@ -3,6 +3,7 @@ package ibm
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/tidwall/gjson"
@@ -33,11 +34,24 @@ func GetResourceRegion(resourceType string, v gjson.Result) string {
 	return ""
 }
 
+// ParseTags returns the labels of the resource merged with its tags and access
+// tags. IBM tags are lists of key:value strings, tags without a colon are
+// returned with an empty value.
 func ParseTags(resourceType string, v gjson.Result) map[string]string {
 	tags := make(map[string]string)
 	for k, v := range v.Get("labels").Map() {
 		tags[k] = v.String()
 	}
+
+	for _, attr := range []string{"tags", "access_tags"} {
+		for _, t := range v.Get(attr).Array() {
+			key, value, _ := strings.Cut(t.String(), ":")
+			if key = strings.TrimSpace(key); key != "" {
+				tags[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+
 	return tags
 }
 
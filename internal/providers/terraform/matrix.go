@@ -0,0 +1,118 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+)
+
+// matrixVariants returns a hcl.Variant for each combination of the workspaces
+// and var file sets of the matrix, for the root module at the given path. The
+// name of each Variant joins the workspace and var file set, if the matrix has
+// them.
+func matrixVariants(matrix *config.ProjectMatrix, path string) ([]hcl.Variant, error) {
+	if matrix == nil {
+		return nil, nil
+	}
+
+	varFiles := append([]config.MatrixVarFiles{}, matrix.VarFiles...)
+	if matrix.AutodetectVarFiles {
+		detected, err := detectVarFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make(map[string]struct{}, len(varFiles))
+		for _, set := range varFiles {
+			names[set.Name] = struct{}{}
+		}
+
+		for _, set := range detected {
+			if _, ok := names[set.Name]; !ok {
+				varFiles = append(varFiles, set)
+			}
+		}
+	}
+
+	// a single empty workspace or var file set keeps the other dimension when
+	// the matrix doesn't have one.
+	workspaces := matrix.Workspaces
+	if len(workspaces) == 0 {
+		workspaces = []string{""}
+	}
+
+	if len(varFiles) == 0 {
+		varFiles = []config.MatrixVarFiles{{}}
+	}
+
+	variants := make([]hcl.Variant, 0, len(workspaces)*len(varFiles))
+	for _, workspace := range workspaces {
+		for _, set := range varFiles {
+			var parts []string
+			if workspace != "" {
+				parts = append(parts, workspace)
+			}
+
+			if set.Name != "" {
+				parts = append(parts, set.Name)
+			}
+
+			name := strings.Join(parts, "-")
+			if name == "" {
+				return nil, fmt.Errorf("no workspaces or var files found for the matrix of %s", path)
+			}
+
+			variants = append(variants, hcl.Variant{
+				Name:      name,
+				Workspace: workspace,
+				VarFiles:  set.Files,
+			})
+		}
+	}
+
+	return variants, nil
+}
+
+// detectVarFiles returns a var file set for each *.tfvars and *.tfvars.json
+// file in the path, named after the file. terraform.tfvars and *.auto.tfvars
+// files are skipped as the hcl.Parser always loads them.
+func detectVarFiles(path string) ([]config.MatrixVarFiles, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not detect var files in %s: %w", path, err)
+	}
+
+	var sets []config.MatrixVarFiles
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		var name string
+		filename := entry.Name()
+		switch {
+		case strings.HasSuffix(filename, ".tfvars"):
+			name = strings.TrimSuffix(filename, ".tfvars")
+		case strings.HasSuffix(filename, ".tfvars.json"):
+			name = strings.TrimSuffix(filename, ".tfvars.json")
+		default:
+			continue
+		}
+
+		if name == "terraform" || strings.HasSuffix(name, ".auto") {
+			continue
+		}
+
+		sets = append(sets, config.MatrixVarFiles{Name: name, Files: []string{filename}})
+	}
+
+	sort.Slice(sets, func(i, j int) bool {
+		return sets[i].Name < sets[j].Name
+	})
+
+	return sets, nil
+}
@@ -0,0 +1,117 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+)
+
+func TestMatrixVariants(t *testing.T) {
+	path := t.TempDir()
+	for _, name := range []string{"main.tf", "terraform.tfvars", "common.auto.tfvars", "dev.tfvars", "prod.tfvars.json", "us.tfvars"} {
+		require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte{}, 0600))
+	}
+
+	tests := []struct {
+		name     string
+		matrix   *config.ProjectMatrix
+		expected []hcl.Variant
+	}{
+		{
+			name:   "no matrix",
+			matrix: nil,
+		},
+		{
+			name:   "workspaces",
+			matrix: &config.ProjectMatrix{Workspaces: []string{"dev", "prod"}},
+			expected: []hcl.Variant{
+				{Name: "dev", Workspace: "dev"},
+				{Name: "prod", Workspace: "prod"},
+			},
+		},
+		{
+			name: "workspaces and var files",
+			matrix: &config.ProjectMatrix{
+				Workspaces: []string{"dev", "prod"},
+				VarFiles:   []config.MatrixVarFiles{{Name: "us", Files: []string{"env/us.tfvars"}}, {Name: "eu", Files: []string{"env/eu.tfvars"}}},
+			},
+			expected: []hcl.Variant{
+				{Name: "dev-us", Workspace: "dev", VarFiles: []string{"env/us.tfvars"}},
+				{Name: "dev-eu", Workspace: "dev", VarFiles: []string{"env/eu.tfvars"}},
+				{Name: "prod-us", Workspace: "prod", VarFiles: []string{"env/us.tfvars"}},
+				{Name: "prod-eu", Workspace: "prod", VarFiles: []string{"env/eu.tfvars"}},
+			},
+		},
+		{
+			name: "autodetect var files",
+			matrix: &config.ProjectMatrix{
+				VarFiles:           []config.MatrixVarFiles{{Name: "us", Files: []string{"us.tfvars", "extra.tfvars"}}},
+				AutodetectVarFiles: true,
+			},
+			expected: []hcl.Variant{
+				{Name: "us", VarFiles: []string{"us.tfvars", "extra.tfvars"}},
+				{Name: "dev", VarFiles: []string{"dev.tfvars"}},
+				{Name: "prod", VarFiles: []string{"prod.tfvars.json"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := matrixVariants(tt.matrix, path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, variants)
+		})
+	}
+
+	_, err := matrixVariants(&config.ProjectMatrix{AutodetectVarFiles: true}, t.TempDir())
+	assert.ErrorContains(t, err, "no workspaces or var files found")
+}
+
+func TestHCLProviderMatrix(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(path, "main.tf"), []byte(`
+variable "instance_type" {}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = terraform.workspace == "prod" ? "m5.large" : var.instance_type
+}
+`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "small.tfvars"), []byte(`instance_type = "t3.micro"`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "large.tfvars"), []byte(`instance_type = "t3.large"`), 0600))
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: path,
+		Name: "app",
+		Matrix: &config.ProjectMatrix{
+			Workspaces:         []string{"dev", "prod"},
+			AutodetectVarFiles: true,
+		},
+	}, nil)
+
+	provider, err := NewHCLProvider(ctx, nil)
+	require.NoError(t, err)
+	projects, err := provider.LoadResources(nil)
+	require.NoError(t, err)
+
+	var names, envs, workspaces, instanceTypes []string
+	for _, project := range projects {
+		names = append(names, project.Name)
+		envs = append(envs, project.Metadata.Environment)
+		workspaces = append(workspaces, project.Metadata.TerraformWorkspace)
+		require.Len(t, project.PartialResources, 1)
+		instanceTypes = append(instanceTypes, project.PartialResources[0].ResourceData.Get("instance_type").String())
+	}
+
+	assert.Equal(t, []string{"app-dev-large", "app-dev-small", "app-prod-large", "app-prod-small"}, names)
+	assert.Equal(t, []string{"dev-large", "dev-small", "prod-large", "prod-small"}, envs)
+	assert.Equal(t, []string{"dev", "dev", "prod", "prod"}, workspaces)
+	assert.Equal(t, []string{"t3.large", "t3.micro", "m5.large", "m5.large"}, instanceTypes)
+}
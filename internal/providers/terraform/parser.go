@@ -106,6 +106,9 @@ func (p *Parser) parseJSONResources(parsePrior bool, baseResources []*schema.Par
 	}
 
 	resData := p.parseResourceData(isState, providerConf, vals, conf, vars)
+	if !parsePrior {
+		parseResourceChangeMetadata(resData, parsed.Get("resource_changes"))
+	}
 
 	p.parseReferences(resData, conf)
 	p.loadInfracostProviderUsageData(usage, resData)
@@ -147,6 +150,16 @@ func (p *Parser) populateUsageData(resData map[string]*schema.ResourceData, usag
 				d.UsageData = arrayUsageData
 			}
 		}
+
+		// Evaluate any usage expressions that reference the resource attributes
+		if d.UsageData != nil && len(d.UsageData.Expressions) > 0 {
+			ud, err := d.UsageData.ForResource(d)
+			if err != nil {
+				logging.Logger.Warnf("Error evaluating usage expressions for %s: %s", d.Address, err)
+				continue
+			}
+			d.UsageData = ud
+		}
 	}
 }
 
@@ -178,6 +191,55 @@ func (p *Parser) parseJSON(j []byte, usage map[string]*schema.UsageData) ([]*sch
 	return pastResources, resources, nil
 }
 
+// parseResourceChangeMetadata adds the previous address of moved resources
+// and whether the resource is being imported to the resource metadata, so
+// they can be used when calculating and showing the diff.
+func parseResourceChangeMetadata(resData map[string]*schema.ResourceData, resourceChanges gjson.Result) {
+	for _, change := range resourceChanges.Array() {
+		d, ok := resData[change.Get("address").String()]
+		if !ok {
+			continue
+		}
+
+		prev := change.Get("previous_address").String()
+		imported := change.Get("change.importing").Exists()
+		if prev == "" && !imported {
+			continue
+		}
+
+		if d.Metadata == nil {
+			d.Metadata = make(map[string]gjson.Result)
+		}
+
+		if prev != "" && prev != d.Address {
+			d.Metadata["previousAddress"] = gjson.Parse(strconv.Quote(prev))
+		}
+
+		if imported {
+			d.Metadata["imported"] = gjson.Parse("true")
+		}
+	}
+}
+
+// forgottenAddresses returns the addresses of the resources that are
+// removed from the state without being destroyed, i.e. the resource changes
+// that have the forget action.
+func forgottenAddresses(j []byte) []string {
+	j, _ = StripSetupTerraformWrapper(j)
+
+	var addrs []string
+	for _, change := range gjson.GetBytes(j, "resource_changes").Array() {
+		for _, action := range change.Get("change.actions").Array() {
+			if action.String() == "forget" {
+				addrs = append(addrs, change.Get("address").String())
+				break
+			}
+		}
+	}
+
+	return addrs
+}
+
 // StripSetupTerraformWrapper removes any output added from the setup-terraform
 // GitHub action terraform wrapper, so we can parse the output of this as
 // valid JSON. It returns the stripped out JSON and a boolean that is true
@@ -284,9 +346,17 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 		v = schema.AddRawValue(v, "region", region)
 
 		tags := parseTags(t, v)
+		for k, val := range providerDefaultTags(providerConf, t, resConf) {
+			if _, ok := tags[k]; !ok {
+				tags[k] = val
+			}
+		}
 
 		data := schema.NewResourceData(t, provider, addr, tags, v)
 		data.Metadata = r.Get("infracost_metadata").Map()
+		if p.ctx.RunContext.Config.IncludeAttributes {
+			data.Metadata["attributes"] = r.Get("values")
+		}
 		resources[addr] = data
 	}
 
@@ -336,6 +406,34 @@ func parseTags(resourceType string, v gjson.Result) map[string]string {
 	}
 }
 
+// providerDefaultTags returns the default tags or labels of the provider of
+// the resource, which the provider adds to the tags of every resource.
+func providerDefaultTags(providerConf gjson.Result, resourceType string, resConf gjson.Result) map[string]string {
+	providerPrefix := getProviderPrefix(resourceType)
+
+	var path string
+	switch providerPrefix {
+	case "aws":
+		path = "expressions.default_tags.0.tags.constant_value"
+	case "google":
+		path = "expressions.default_labels.constant_value"
+	default:
+		return nil
+	}
+
+	v := providerConf.Get(fmt.Sprintf("%s.%s", gjsonEscape(parseProviderKey(resConf)), path))
+	if !v.Exists() {
+		v = providerConf.Get(fmt.Sprintf("%s.%s", gjsonEscape(providerPrefix), path))
+	}
+
+	tags := make(map[string]string)
+	for k, val := range v.Map() {
+		tags[k] = val.String()
+	}
+
+	return tags
+}
+
 func overrideRegion(addr string, resourceType string, config *config.Config) string {
 	region := ""
 	providerPrefix := getProviderPrefix(resourceType)
@@ -675,6 +675,78 @@ func TestParseResourceData(t *testing.T) {
 	}
 }
 
+func TestParseResourceDataTags(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {
+			"name": "aws",
+			"expressions": {
+				"default_tags": [{"tags": {"constant_value": {"team": "platform", "env": "dev"}}}]
+			}
+		},
+		"google": {
+			"name": "google",
+			"expressions": {
+				"default_labels": {"constant_value": {"team": "data"}}
+			}
+		}
+	}`)
+
+	planVals := gjson.Parse(`{
+		"resources": [
+			{
+				"address": "aws_instance.web",
+				"type": "aws_instance",
+				"provider_name": "registry.terraform.io/hashicorp/aws",
+				"values": {"tags": {"env": "prod"}}
+			},
+			{
+				"address": "google_compute_instance.vm",
+				"type": "google_compute_instance",
+				"provider_name": "registry.terraform.io/hashicorp/google",
+				"values": {"labels": {"app": "api"}}
+			},
+			{
+				"address": "ibm_is_instance.vsi",
+				"type": "ibm_is_instance",
+				"provider_name": "registry.terraform.io/ibm-cloud/ibm",
+				"values": {"tags": ["cost-center:CC-1", "owner : alice"], "access_tags": ["project:demo", "standalone"]}
+			}
+		]
+	}`)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}), true)
+	actual := p.parseResourceData(false, providerConf, planVals, gjson.Result{}, gjson.Result{})
+
+	assert.Equal(t, map[string]string{"team": "platform", "env": "prod"}, actual["aws_instance.web"].Tags)
+	assert.Equal(t, map[string]string{"team": "data", "app": "api"}, actual["google_compute_instance.vm"].Tags)
+	assert.Equal(t, map[string]string{"cost-center": "CC-1", "owner": "alice", "project": "demo", "standalone": ""}, actual["ibm_is_instance.vsi"].Tags)
+}
+
+func TestParseResourceDataAttributes(t *testing.T) {
+	planVals := gjson.Parse(`{
+		"resources": [
+			{
+				"address": "aws_instance.web",
+				"type": "aws_instance",
+				"provider_name": "registry.terraform.io/hashicorp/aws",
+				"values": {"instance_type": "m5.large", "ebs_optimized": true}
+			}
+		]
+	}`)
+
+	runCtx := config.EmptyRunContext()
+	p := NewParser(config.NewProjectContext(runCtx, &config.Project{}, log.Fields{}), true)
+	actual := p.parseResourceData(false, gjson.Result{}, planVals, gjson.Result{}, gjson.Result{})
+	assert.NotContains(t, actual["aws_instance.web"].Metadata, "attributes")
+
+	runCtx.Config.IncludeAttributes = true
+	actual = p.parseResourceData(false, gjson.Result{}, planVals, gjson.Result{}, gjson.Result{})
+	attributes := actual["aws_instance.web"].Metadata["attributes"]
+	assert.Equal(t, "m5.large", attributes.Get("instance_type").String())
+	assert.True(t, attributes.Get("ebs_optimized").Bool())
+	assert.False(t, attributes.Get("region").Exists())
+}
+
 func TestParseReferences_plan(t *testing.T) {
 	vol1 := schema.NewResourceData(
 		"aws_ebs_volume",
@@ -1072,3 +1144,35 @@ func TestGetModuleNames(t *testing.T) {
 		assert.Equal(t, test.expected, actual)
 	}
 }
+
+func TestParseResourceChangeMetadata(t *testing.T) {
+	resData := map[string]*schema.ResourceData{
+		"aws_instance.moved":    schema.NewResourceData("aws_instance", "aws", "aws_instance.moved", nil, gjson.Result{}),
+		"aws_instance.imported": schema.NewResourceData("aws_instance", "aws", "aws_instance.imported", nil, gjson.Result{}),
+		"aws_instance.created":  schema.NewResourceData("aws_instance", "aws", "aws_instance.created", nil, gjson.Result{}),
+	}
+
+	resourceChanges := gjson.Parse(`[
+		{"address": "aws_instance.moved", "previous_address": "aws_instance.original", "change": {"actions": ["no-op"]}},
+		{"address": "aws_instance.imported", "change": {"actions": ["no-op"], "importing": {"id": "i-12345678"}}},
+		{"address": "aws_instance.created", "change": {"actions": ["create"]}}
+	]`)
+
+	parseResourceChangeMetadata(resData, resourceChanges)
+
+	assert.Equal(t, "aws_instance.original", resData["aws_instance.moved"].Metadata["previousAddress"].String())
+	assert.True(t, resData["aws_instance.imported"].Metadata["imported"].Bool())
+	assert.Empty(t, resData["aws_instance.created"].Metadata)
+}
+
+func TestForgottenAddresses(t *testing.T) {
+	j := []byte(`{
+		"resource_changes": [
+			{"address": "aws_instance.kept", "change": {"actions": ["no-op"]}},
+			{"address": "aws_instance.forgotten", "change": {"actions": ["forget"]}},
+			{"address": "aws_instance.destroyed", "change": {"actions": ["delete"]}}
+		]
+	}`)
+
+	assert.Equal(t, []string{"aws_instance.forgotten"}, forgottenAddresses(j))
+}
@@ -89,6 +89,7 @@ func (p *PlanJSONProvider) LoadResourcesFromSrc(usage map[string]*schema.UsageDa
 
 	project.PartialPastResources = partialPastResources
 	project.PartialResources = partialResources
+	project.Metadata.ForgottenResources = forgottenAddresses(j)
 
 	if p.scanner != nil {
 		err := p.scanner.ScanPlan(project, j)
@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+)
+
+// remoteStates returns the hcl.RemoteStates for the remote states of the project
+// config. Outputs are read from local state files, relative to the project path,
+// or from the outputs of projects that have been evaluated earlier in the run.
+// Remote states of projects that haven't been evaluated are skipped, so their
+// data sources are left unresolved.
+func remoteStates(ctx *config.ProjectContext) (hcl.RemoteStates, error) {
+	var states hcl.RemoteStates
+
+	for _, state := range ctx.ProjectConfig.RemoteStates {
+		var outputs cty.Value
+		switch {
+		case state.StateFile != "":
+			path := state.StateFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(ctx.ProjectConfig.Path, path)
+			}
+
+			var err error
+			outputs, err = LoadStateOutputs(path)
+			if err != nil {
+				return nil, fmt.Errorf("could not load remote state %s: %w", remoteStateName(state), err)
+			}
+		case state.Project != "":
+			j, ok := ctx.RunContext.ProjectOutputs(state.Project)
+			if !ok {
+				ctx.Logger().Warnf("skipping remote state %s as project %s has no outputs", remoteStateName(state), state.Project)
+				continue
+			}
+
+			var v ctyjson.SimpleJSONValue
+			err := v.UnmarshalJSON(j)
+			if err != nil {
+				return nil, fmt.Errorf("could not load outputs of project %s: %w", state.Project, err)
+			}
+			outputs = v.Value
+		default:
+			continue
+		}
+
+		states = append(states, hcl.RemoteState{
+			Address: state.Address,
+			Backend: state.Backend,
+			Config:  state.Config,
+			Outputs: outputs,
+		})
+	}
+
+	return states, nil
+}
+
+func remoteStateName(state config.RemoteState) string {
+	if state.Address != "" {
+		return state.Address
+	}
+
+	return fmt.Sprintf("with backend %s", state.Backend)
+}
+
+// setProjectOutputs stores the outputs of the root module on the run context,
+// so projects later in the run can use them as remote state outputs. Outputs
+// are only stored for named projects and outputs that aren't known are left
+// out.
+func setProjectOutputs(ctx *config.ProjectContext, module *hcl.Module) {
+	name := ctx.ProjectConfig.Name
+	if name == "" {
+		return
+	}
+
+	outputs := make(map[string]cty.Value)
+	for k, v := range module.Blocks.Outputs(false).AsValueMap() {
+		if v == cty.NilVal || !v.IsWhollyKnown() {
+			continue
+		}
+
+		outputs[k] = v
+	}
+
+	j, err := ctyjson.SimpleJSONValue{Value: cty.ObjectVal(outputs)}.MarshalJSON()
+	if err != nil {
+		ctx.Logger().WithError(err).Debugf("could not encode outputs of project %s", name)
+		return
+	}
+
+	ctx.RunContext.SetProjectOutputs(name, j)
+}
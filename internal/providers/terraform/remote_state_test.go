@@ -0,0 +1,101 @@
+package terraform
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestRemoteStates(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	parse := func(path string, options ...hcl.Option) *hcl.Module {
+		parsers, err := hcl.LoadParsers(path, modules.NewModuleLoader(path, nil, entry, &sync.KeyMutex{}), nil, entry, options...)
+		require.NoError(t, err)
+		module, err := parsers[0].ParseDirectory()
+		require.NoError(t, err)
+		return module
+	}
+
+	runCtx := config.EmptyRunContext()
+	networkPath := filepath.Join("testdata", "remote_states", "network")
+	networkCtx := config.NewProjectContext(runCtx, &config.Project{Path: networkPath, Name: "network"}, nil)
+	setProjectOutputs(networkCtx, parse(networkPath))
+
+	appPath := filepath.Join("testdata", "remote_states", "app")
+	appCtx := config.NewProjectContext(runCtx, &config.Project{
+		Path: appPath,
+		RemoteStates: []config.RemoteState{
+			{Address: "data.terraform_remote_state.network", Project: "network"},
+			{Backend: "s3", Config: map[string]string{"key": "shared/terraform.tfstate"}, StateFile: "shared.tfstate"},
+			{Address: "data.terraform_remote_state.missing", Project: "missing"},
+		},
+	}, nil)
+
+	states, err := remoteStates(appCtx)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+
+	app := parse(appPath, hcl.OptionWithRemoteStates(states))
+
+	instances := app.Blocks.OfType("resource")
+	require.Len(t, instances, 3)
+
+	for i, b := range instances {
+		assert.Equal(t, "m5.large", b.GetAttribute("instance_type").Value().AsString())
+		assert.Equal(t, []string{"subnet-a", "subnet-b", "subnet-c"}[i], b.GetAttribute("subnet_id").Value().AsString())
+	}
+}
+
+func TestParseStateOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected cty.Value
+	}{
+		{
+			name: "state JSON",
+			json: `{"format_version": "1.0", "values": {"outputs": {"count": {"value": 2, "type": "number"}, "names": {"value": ["a", "b"], "type": ["list", "string"]}}}}`,
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"count": cty.NumberIntVal(2),
+				"names": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+		},
+		{
+			name: "raw state",
+			json: `{"version": 4, "outputs": {"name": {"value": "web", "type": "string"}, "untyped": {"value": {"a": 1}}}}`,
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"name":    cty.StringVal("web"),
+				"untyped": cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(1)}),
+			}),
+		},
+		{
+			name:     "no outputs",
+			json:     `{"version": 4, "resources": []}`,
+			expected: cty.EmptyObjectVal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseStateOutputs([]byte(tt.json))
+			require.NoError(t, err)
+			assert.True(t, tt.expected.RawEquals(actual), "expected %#v, got %#v", tt.expected, actual)
+		})
+	}
+
+	_, err := parseStateOutputs([]byte(`{"outputs": `))
+	assert.Error(t, err)
+}
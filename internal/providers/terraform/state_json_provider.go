@@ -8,6 +8,9 @@ import (
 	"github.com/infracost/infracost/internal/ui"
 
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 type StateJSONProvider struct {
@@ -71,3 +74,68 @@ func (p *StateJSONProvider) LoadResources(usage map[string]*schema.UsageData) ([
 	spinner.Success()
 	return []*schema.Project{project}, nil
 }
+
+// LoadStateOutputs reads the outputs from the Terraform state file at path as a
+// cty object of output names to values.
+func LoadStateOutputs(path string) (cty.Value, error) {
+	j, err := os.ReadFile(path)
+	if err != nil {
+		return cty.NilVal, errors.Wrap(err, "Error reading Terraform state JSON file")
+	}
+
+	outputs, err := parseStateOutputs(j)
+	if err != nil {
+		return cty.NilVal, errors.Wrapf(err, "Error parsing outputs of Terraform state JSON file %s", path)
+	}
+
+	return outputs, nil
+}
+
+// parseStateOutputs returns the outputs of either a state JSON file, which is the
+// output of terraform show -json, or a raw terraform.tfstate file. The output type
+// is used if the state has one, otherwise it is implied from the value.
+func parseStateOutputs(j []byte) (cty.Value, error) {
+	if !gjson.ValidBytes(j) {
+		return cty.NilVal, errors.New("invalid JSON")
+	}
+
+	outputsJSON := gjson.GetBytes(j, "values.outputs")
+	if !outputsJSON.Exists() {
+		outputsJSON = gjson.GetBytes(j, "outputs")
+	}
+
+	outputs := make(map[string]cty.Value)
+	var err error
+	outputsJSON.ForEach(func(name, output gjson.Result) bool {
+		value := output.Get("value")
+		if !value.Exists() {
+			return true
+		}
+
+		var ty cty.Type
+		if t := output.Get("type"); t.Exists() {
+			ty, err = ctyjson.UnmarshalType([]byte(t.Raw))
+		} else {
+			ty, err = ctyjson.ImpliedType([]byte(value.Raw))
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "invalid type for output %s", name.String())
+			return false
+		}
+
+		var v cty.Value
+		v, err = ctyjson.Unmarshal([]byte(value.Raw), ty)
+		if err != nil {
+			err = errors.Wrapf(err, "invalid value for output %s", name.String())
+			return false
+		}
+
+		outputs[name.String()] = v
+		return true
+	})
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return cty.ObjectVal(outputs), nil
+}
@@ -0,0 +1,324 @@
+package terraform
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// terragruntSkipDirs are the directories that are never searched for Terragrunt units.
+var terragruntSkipDirs = map[string]bool{
+	".terragrunt-cache": true,
+	".terraform":        true,
+	".git":              true,
+	config.InfracostDir: true,
+}
+
+// TerragruntHCLProvider evaluates Terragrunt units natively, without the
+// terragrunt binary. Each unit's terragrunt.hcl is evaluated to find its
+// Terraform source and inputs, and the source is then parsed with the
+// hcl.Parser using the inputs as variables. Each unit is a separate project.
+type TerragruntHCLProvider struct {
+	ctx    *config.ProjectContext
+	Path   string
+	logger *log.Entry
+}
+
+func NewTerragruntHCLProvider(ctx *config.ProjectContext) schema.Provider {
+	return &TerragruntHCLProvider{
+		ctx:    ctx,
+		Path:   ctx.ProjectConfig.Path,
+		logger: ctx.Logger().WithFields(log.Fields{"provider": "terragrunt_dir"}),
+	}
+}
+
+func (p *TerragruntHCLProvider) Type() string        { return "terragrunt_dir" }
+func (p *TerragruntHCLProvider) DisplayType() string { return "Terragrunt directory" }
+func (p *TerragruntHCLProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	basePath := p.ctx.ProjectConfig.Path
+	if p.ctx.RunContext.Config.ConfigFilePath != "" {
+		basePath = filepath.Dir(p.ctx.RunContext.Config.ConfigFilePath)
+	}
+
+	modulePath, err := filepath.Rel(basePath, metadata.Path)
+	if err == nil && modulePath != "" && modulePath != "." {
+		metadata.TerraformModulePath = modulePath
+	}
+
+	metadata.TerraformWorkspace = p.ctx.ProjectConfig.TerraformWorkspace
+}
+
+// LoadResources evaluates each Terragrunt unit under the path and returns a
+// project for each one.
+func (p *TerragruntHCLProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	units, err := p.findUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(units) == 0 {
+		return nil, fmt.Errorf("No Terragrunt units found at %s", p.Path)
+	}
+
+	options, credsSource, err := hclParserOptions(p.ctx, hcl.OptionWithSpinner(p.ctx.RunContext.NewSpinner))
+	if err != nil {
+		return nil, err
+	}
+
+	loader, err := newModuleLoader(p.ctx, credsSource, p.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*hcl.TerragruntConfig, 0, len(units))
+	included := map[string]bool{}
+	for _, unit := range units {
+		cfg, err := hcl.LoadTerragruntConfig(unit, p.logger.WithFields(log.Fields{"terragrunt_unit": unit}))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range cfg.Includes {
+			included[path] = true
+		}
+
+		configs = append(configs, cfg)
+	}
+
+	var projects []*schema.Project
+	for _, cfg := range configs {
+		// Configs that are included by other units, e.g. a root terragrunt.hcl, aren't units themselves
+		if abs, err := filepath.Abs(cfg.Path); err == nil && included[abs] {
+			p.logger.Debugf("Skipping %s since it is included by other Terragrunt units", cfg.Path)
+			continue
+		}
+
+		if len(configs) > 1 {
+			fmt.Fprintf(os.Stderr, "Detected Terragrunt unit at %s\n", ui.DisplayPath(cfg.Dir))
+		}
+
+		project, err := p.loadUnit(cfg, loader, options, usage)
+		if err != nil {
+			return nil, err
+		}
+
+		if project != nil {
+			projects = append(projects, project)
+		}
+	}
+
+	return projects, nil
+}
+
+// loadUnit parses the Terraform source of a unit using its inputs. It
+// returns nil if the unit is skipped or doesn't have any Terraform files.
+func (p *TerragruntHCLProvider) loadUnit(cfg *hcl.TerragruntConfig, loader *modules.ModuleLoader, options []hcl.Option, usage map[string]*schema.UsageData) (*schema.Project, error) {
+	logger := p.logger.WithFields(log.Fields{"terragrunt_unit": cfg.Path})
+
+	if cfg.Skip {
+		logger.Debugf("Skipping Terragrunt unit %s since it has skip = true", cfg.Dir)
+		return nil, nil
+	}
+
+	dir, err := p.sourceDir(cfg, loader)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading Terraform source for Terragrunt unit %s: %w", cfg.Dir, err)
+	}
+
+	if !hasTerraformFiles(dir) {
+		logger.Debugf("Skipping Terragrunt unit %s since %s has no Terraform files", cfg.Dir, dir)
+		return nil, nil
+	}
+
+	// The inputs are set first so that any --terraform-var flags take precedence
+	unitOptions := append([]hcl.Option{hcl.OptionWithRawCtyInput(cty.ObjectVal(cfg.Inputs))}, options...)
+
+	h := &HCLProvider{
+		parsers:        []*hcl.Parser{hcl.NewParser(hcl.RootPath{Path: dir}, loader, logger, unitOptions...)},
+		planJSONParser: NewParser(p.ctx, false),
+		ctx:            p.ctx,
+		config:         HCLProviderConfig{SuppressLogging: true},
+		logger:         logger,
+	}
+
+	jsons, err := h.LoadPlanJSONs()
+	if err != nil {
+		return nil, err
+	}
+
+	project := p.newProject(cfg)
+
+	for _, j := range jsons {
+		partialPastResources, partialResources, err := h.planJSONParser.parseJSON(j.JSON, usage)
+		if err != nil {
+			return project, fmt.Errorf("Error parsing Terraform plan JSON file %w", err)
+		}
+
+		project.PartialPastResources = append(project.PartialPastResources, partialPastResources...)
+		project.PartialResources = append(project.PartialResources, partialResources...)
+		project.Metadata.ForgottenResources = append(project.Metadata.ForgottenResources, forgottenAddresses(j.JSON)...)
+
+		if addrs := unmockedPricedDataSources(j.Module.UnmockedDataSources, partialResources); len(addrs) > 0 {
+			h.addWarning(project.Metadata, hcl.NewUnmockedDataSourcesWarning(addrs))
+		}
+
+		h.addHCLDiagnostics(project.Metadata, j.Module, partialResources)
+	}
+
+	return project, nil
+}
+
+func (p *TerragruntHCLProvider) newProject(cfg *hcl.TerragruntConfig) *schema.Project {
+	metadata := config.DetectProjectMetadata(cfg.Dir)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	return schema.NewProject(name, metadata)
+}
+
+// sourceDir returns the directory of the Terraform files for the unit,
+// downloading the source if it is remote.
+func (p *TerragruntHCLProvider) sourceDir(cfg *hcl.TerragruntConfig, loader *modules.ModuleLoader) (string, error) {
+	if cfg.Source == "" {
+		return cfg.Dir, nil
+	}
+
+	if !hcl.IsRemoteTerragruntSource(cfg.Source) {
+		return cfg.Source, nil
+	}
+
+	source, version, err := normalizeTerragruntSource(cfg.Source)
+	if err != nil {
+		return "", err
+	}
+
+	return loader.LoadSource(source, version)
+}
+
+// normalizeTerragruntSource converts a Terragrunt tfr:// registry source to
+// the Terraform registry source and version, e.g.
+// tfr:///terraform-aws-modules/vpc/aws?version=3.3.0.
+func normalizeTerragruntSource(source string) (string, string, error) {
+	if !strings.HasPrefix(source, "tfr://") {
+		return source, "", nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid registry source %s: %w", source, err)
+	}
+
+	version := u.Query().Get("version")
+	modulePath := strings.TrimPrefix(u.Path, "/")
+
+	if u.Host != "" {
+		modulePath = u.Host + "/" + modulePath
+	}
+
+	return modulePath, version, nil
+}
+
+// findUnits returns the paths of the Terragrunt config files under the
+// project path, excluding any excluded paths.
+func (p *TerragruntHCLProvider) findUnits() ([]string, error) {
+	configName := hcl.DefaultTerragruntConfigName
+	if v := os.Getenv("TERRAGRUNT_CONFIG"); v != "" {
+		configName = v
+	}
+
+	if filepath.IsAbs(configName) {
+		return []string{configName}, nil
+	}
+
+	isExcluded := p.excludedMatcher()
+
+	var units []string
+	err := filepath.WalkDir(p.Path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != p.Path && (terragruntSkipDirs[d.Name()] || isExcluded(path)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Name() == configName || d.Name() == configName+".json" {
+			units = append(units, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(units)
+
+	return units, nil
+}
+
+// excludedMatcher returns a func that checks if a directory is excluded by
+// the exclude paths, which are either directory names or globs relative to
+// the project path, the same as for Terraform projects.
+func (p *TerragruntHCLProvider) excludedMatcher() func(string) bool {
+	names := map[string]bool{}
+	globs := map[string]bool{}
+
+	for _, dir := range p.ctx.ProjectConfig.ExcludePaths {
+		if dir == filepath.Base(dir) {
+			names[dir] = true
+		}
+
+		absoluteDir := dir
+		if !filepath.IsAbs(dir) {
+			absoluteDir = filepath.Join(p.Path, dir)
+		}
+
+		matches, err := filepath.Glob(absoluteDir)
+		if err == nil {
+			for _, m := range matches {
+				globs[m] = true
+			}
+		}
+	}
+
+	return func(dir string) bool {
+		return globs[dir] || names[filepath.Base(dir)]
+	}
+}
+
+func hasTerraformFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() && modules.IsConfigFile(e.Name()) {
+			return true
+		}
+	}
+
+	return false
+}
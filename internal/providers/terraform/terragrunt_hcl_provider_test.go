@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTerragruntHCLProvider_LoadResources(t *testing.T) {
+	testPath := "testdata/terragrunt_hcl_provider_test/live"
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.RootPath = t.TempDir()
+	ctx := config.NewProjectContext(runCtx, &config.Project{Path: testPath}, log.Fields{})
+
+	p := NewTerragruntHCLProvider(ctx)
+	projects, err := p.LoadResources(map[string]*schema.UsageData{})
+	require.NoError(t, err)
+
+	// The root config doesn't have any Terraform files of its own so it isn't a project
+	require.Len(t, projects, 2)
+
+	expected := map[string]struct {
+		instanceType string
+		name         string
+	}{
+		filepath.Join(testPath, "dev", "app"):  {"t3.micro", "app-dev"},
+		filepath.Join(testPath, "prod", "app"): {"m5.large", "app-prod"},
+	}
+
+	for _, project := range projects {
+		exp, ok := expected[project.Metadata.Path]
+		require.True(t, ok, "unexpected project %s", project.Metadata.Path)
+		assert.Equal(t, "terragrunt_dir", project.Metadata.Type)
+
+		require.Len(t, project.PartialResources, 1)
+		d := project.PartialResources[0].ResourceData
+		assert.Equal(t, "aws_instance.app", d.Address)
+		assert.Equal(t, exp.instanceType, d.Get("instance_type").String())
+		assert.Equal(t, exp.name, d.Get("tags.Name").String())
+		assert.Equal(t, "us-east-1", d.Get("region").String())
+	}
+}
+
+func TestNormalizeTerragruntSource(t *testing.T) {
+	source, version, err := normalizeTerragruntSource("tfr:///terraform-aws-modules/vpc/aws?version=3.3.0")
+	require.NoError(t, err)
+	assert.Equal(t, "terraform-aws-modules/vpc/aws", source)
+	assert.Equal(t, "3.3.0", version)
+
+	source, version, err = normalizeTerragruntSource("tfr://registry.example.com/org/vpc/aws?version=1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/org/vpc/aws", source)
+	assert.Equal(t, "1.0.0", version)
+
+	source, version, err = normalizeTerragruntSource("git::https://github.com/org/modules.git//app?ref=v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "git::https://github.com/org/modules.git//app?ref=v1.0.0", source)
+	assert.Equal(t, "", version)
+}
@@ -0,0 +1,22 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_requesting_account_id  = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+  owners      = ["099720109477"]
+}
+
+data "aws_ec2_instance_type" "web" {
+  instance_type = "t3.micro"
+}
+
+resource "aws_instance" "web" {
+  ami           = data.aws_ami.ubuntu.id
+  instance_type = data.aws_ec2_instance_type.web.instance_type
+  ebs_optimized = data.aws_ec2_instance_type.web.ebs_optimized_support == "default"
+}
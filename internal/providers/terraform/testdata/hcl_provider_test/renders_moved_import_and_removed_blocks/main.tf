@@ -0,0 +1,56 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_requesting_account_id  = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_eip" "renamed" {
+  network_interface = "test"
+}
+
+moved {
+  from = aws_eip.original
+  to   = aws_eip.renamed
+}
+
+resource "aws_eip" "adopted" {
+  network_interface = "test"
+}
+
+import {
+  to = aws_eip.adopted
+  id = "eipalloc-12345678"
+}
+
+removed {
+  from = aws_eip.forgotten
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+removed {
+  from = aws_eip.destroyed
+
+  lifecycle {
+    destroy = true
+  }
+}
+
+resource "terraform_data" "replacement" {
+  input = aws_eip.renamed.network_interface
+}
+
+check "eip_exists" {
+  data "aws_eip" "check" {
+    id = aws_eip.renamed.id
+  }
+
+  assert {
+    condition     = data.aws_eip.check.id != ""
+    error_message = "EIP doesn't exist"
+  }
+}
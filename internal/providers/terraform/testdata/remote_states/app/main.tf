@@ -0,0 +1,25 @@
+data "terraform_remote_state" "network" {
+  backend = "s3"
+
+  config = {
+    bucket = "infracost-state"
+    key    = "network/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "shared" {
+  backend = "s3"
+
+  config = {
+    bucket = "infracost-state"
+    key    = "shared/terraform.tfstate"
+  }
+}
+
+resource "aws_instance" "web" {
+  count = data.terraform_remote_state.network.outputs.instance_count
+
+  ami           = "ami-0123456789abcdef0"
+  instance_type = data.terraform_remote_state.shared.outputs.instance_type
+  subnet_id     = data.terraform_remote_state.network.outputs.subnet_ids[count.index]
+}
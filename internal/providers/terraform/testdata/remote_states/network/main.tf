@@ -0,0 +1,11 @@
+variable "instance_count" {
+  default = 3
+}
+
+output "instance_count" {
+  value = var.instance_count
+}
+
+output "subnet_ids" {
+  value = ["subnet-a", "subnet-b", "subnet-c"]
+}
@@ -0,0 +1,7 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+inputs = {
+  instance_type = "t3.micro"
+}
@@ -0,0 +1,12 @@
+locals {
+  env = basename(dirname(get_terragrunt_dir()))
+}
+
+terraform {
+  source = "${get_parent_terragrunt_dir()}/../modules//app"
+}
+
+inputs = {
+  env    = local.env
+  region = "us-east-1"
+}
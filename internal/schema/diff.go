@@ -29,6 +29,8 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	// calculate the diff for them. This way a complete diff for
 	// all resources is calculated.
 
+	past = applyMoves(past, current)
+
 	pastRMap := make(map[string]*Resource)
 	fillResourcesMap(pastRMap, "", past)
 	currentRMap := make(map[string]*Resource)
@@ -58,6 +60,40 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	return diff
 }
 
+// applyMoves renames any past resources that have been moved, e.g. by a
+// Terraform moved block, to their current name. This means a renamed resource
+// is diffed against itself rather than showing as removed and added.
+func applyMoves(past []*Resource, current []*Resource) []*Resource {
+	moved := make(map[string]string)
+	currentNames := make(map[string]bool, len(current))
+	for _, resource := range current {
+		currentNames[resource.Name] = true
+
+		if prev := resource.PreviousName(); prev != "" {
+			moved[prev] = resource.Name
+		}
+	}
+
+	if len(moved) == 0 {
+		return past
+	}
+
+	renamed := make([]*Resource, 0, len(past))
+	for _, resource := range past {
+		to, ok := moved[resource.Name]
+		if !ok || currentNames[resource.Name] {
+			renamed = append(renamed, resource)
+			continue
+		}
+
+		r := *resource
+		r.Name = to
+		renamed = append(renamed, &r)
+	}
+
+	return renamed
+}
+
 // diffResourcesByKey calculates the diff between two resources given their resourcesMap and
 // their key.
 func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string]*Resource) (bool, *Resource) {
@@ -1,10 +1,12 @@
 package schema
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestCalculateDiff(t *testing.T) {
@@ -133,6 +135,47 @@ func TestCalculateDiff(t *testing.T) {
 	assert.Equal(t, expectedDiff, diff)
 }
 
+func TestCalculateDiffMovedResources(t *testing.T) {
+	newResource := func(name string, cost int64) *Resource {
+		return &Resource{
+			Name:        name,
+			HourlyCost:  decimalPtr(decimal.NewFromInt(cost)),
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost * 730)),
+			CostComponents: []*CostComponent{
+				{
+					Name:        "Instance usage",
+					HourlyCost:  decimalPtr(decimal.NewFromInt(cost)),
+					MonthlyCost: decimalPtr(decimal.NewFromInt(cost * 730)),
+				},
+			},
+		}
+	}
+
+	moved := func(r *Resource, from string) *Resource {
+		r.Metadata = map[string]gjson.Result{"previousAddress": gjson.Parse(strconv.Quote(from))}
+		return r
+	}
+
+	pastResources := []*Resource{
+		newResource("aws_instance.old", 1),
+		newResource("module.old.aws_instance.web[0]", 2),
+		newResource("aws_instance.resized_old", 1),
+	}
+
+	currentResources := []*Resource{
+		moved(newResource("aws_instance.new", 1), "aws_instance.old"),
+		moved(newResource("module.new.aws_instance.web[0]", 2), "module.old.aws_instance.web[0]"),
+		moved(newResource("aws_instance.resized_new", 3), "aws_instance.resized_old"),
+	}
+
+	diff := CalculateDiff(pastResources, currentResources)
+
+	assert.Len(t, diff, 1, "only the resized resource should be in the diff")
+	assert.Equal(t, "aws_instance.resized_new", diff[0].Name)
+	assert.Equal(t, "2", diff[0].HourlyCost.String())
+	assert.Equal(t, "aws_instance.resized_old", pastResources[2].Name, "past resources should not be modified")
+}
+
 func TestDiffCostComponentsByResource(t *testing.T) {
 	pastRS := &Resource{
 		Name: "rs",
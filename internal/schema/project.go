@@ -29,6 +29,25 @@ type ProjectMetadata struct {
 	VCSCodeChanged      *bool     `json:"vcsCodeChanged,omitempty"`
 	Warnings            []Warning `json:"warnings,omitempty"`
 	Policies            Policies  `json:"policies,omitempty"`
+	ForgottenResources  []string  `json:"forgottenResources,omitempty"`
+	// Environment is the combination of workspace and var files that the project was evaluated
+	// with, when the project config has a matrix.
+	Environment string `json:"environment,omitempty"`
+	// HCLDiagnostics are the values that were still unresolved or unknown after evaluating a Terraform
+	// directory. These are only set when running with --hcl-diagnostics.
+	HCLDiagnostics []HCLDiagnostic `json:"hclDiagnostics,omitempty"`
+}
+
+// HCLDiagnostic is a value that was still unresolved or unknown after evaluating a Terraform
+// directory, which can cause the costs of the resources that use it to be wrong.
+type HCLDiagnostic struct {
+	Kind      string   `json:"kind"`
+	Address   string   `json:"address"`
+	Message   string   `json:"message"`
+	Filename  string   `json:"filename"`
+	StartLine int      `json:"startLine"`
+	EndLine   int      `json:"endLine"`
+	Resources []string `json:"resources,omitempty"`
 }
 
 func (m *ProjectMetadata) WorkspaceLabel() string {
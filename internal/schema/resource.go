@@ -76,6 +76,13 @@ func (r *Resource) CalculateCosts() {
 	}
 }
 
+// PreviousName returns the name the resource had before it was moved, e.g.
+// by a Terraform moved block. It returns an empty string if the resource
+// hasn't been moved.
+func (r *Resource) PreviousName() string {
+	return r.Metadata["previousAddress"].String()
+}
+
 func (r *Resource) FlattenedSubResources() []*Resource {
 	resources := make([]*Resource, 0, len(r.SubResources))
 
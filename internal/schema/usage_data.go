@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/imdario/mergo"
@@ -15,6 +16,18 @@ import (
 type UsageData struct {
 	Address    string
 	Attributes map[string]gjson.Result
+	// Expressions are usage values that reference the attributes of the resource
+	// the usage applies to. These are evaluated by ForResource.
+	Expressions map[string]UsageExpression
+}
+
+// UsageExpression is a usage value that can only be evaluated once the
+// resource it applies to is known.
+type UsageExpression interface {
+	// References returns the other usage keys of the resource that the expression depends on.
+	References() []string
+	// Evaluate returns the value of the expression for the given resource and its resolved usage values.
+	Evaluate(d *ResourceData, usage map[string]gjson.Result) (gjson.Result, error)
 }
 
 func NewUsageData(address string, attributes map[string]gjson.Result) *UsageData {
@@ -42,17 +55,108 @@ func (u *UsageData) Merge(other *UsageData) *UsageData {
 		newU.Attributes[k] = v
 	}
 
+	for k, v := range u.Expressions {
+		newU.setExpression(k, v)
+	}
+
 	if other != nil {
 		for k, v := range other.Attributes {
-			if _, ok := newU.Attributes[k]; !ok {
+			if !newU.has(k) {
 				newU.Attributes[k] = v
 			}
 		}
+
+		for k, v := range other.Expressions {
+			if !newU.has(k) {
+				newU.setExpression(k, v)
+			}
+		}
 	}
 
 	return newU
 }
 
+func (u *UsageData) has(key string) bool {
+	if _, ok := u.Attributes[key]; ok {
+		return true
+	}
+
+	_, ok := u.Expressions[key]
+	return ok
+}
+
+func (u *UsageData) setExpression(key string, e UsageExpression) {
+	if u.Expressions == nil {
+		u.Expressions = make(map[string]UsageExpression)
+	}
+
+	u.Expressions[key] = e
+}
+
+// ForResource returns a copy of the usage data with any Expressions evaluated
+// against the given resource. Expressions are evaluated in the order of their
+// references, so an expression can use the result of another one.
+func (u *UsageData) ForResource(d *ResourceData) (*UsageData, error) {
+	if u == nil || len(u.Expressions) == 0 {
+		return u, nil
+	}
+
+	resolved := &UsageData{
+		Address:    u.Address,
+		Attributes: make(map[string]gjson.Result, len(u.Attributes)+len(u.Expressions)),
+	}
+
+	for k, v := range u.Attributes {
+		resolved.Attributes[k] = v
+	}
+
+	pending := make(map[string]UsageExpression, len(u.Expressions))
+	for k, v := range u.Expressions {
+		pending[k] = v
+	}
+
+	for len(pending) > 0 {
+		keys := make([]string, 0, len(pending))
+		for k := range pending {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		progressed := false
+
+		for _, k := range keys {
+			e := pending[k]
+
+			ready := true
+			for _, ref := range e.References() {
+				if _, ok := pending[ref]; ok {
+					ready = false
+					break
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			v, err := e.Evaluate(d, resolved.Attributes)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved.Attributes[k] = v
+			delete(pending, k)
+			progressed = true
+		}
+
+		if !progressed {
+			return nil, fmt.Errorf("cycle detected in usage expressions for %s: %s", d.Address, strings.Join(keys, ", "))
+		}
+	}
+
+	return resolved, nil
+}
+
 func (u *UsageData) Get(key string) gjson.Result {
 	if u.Attributes[key].Type != gjson.Null {
 		return u.Attributes[key]
@@ -188,7 +292,14 @@ func ParseAttributes(i interface{}) map[string]gjson.Result {
 }
 
 func MergeAttributes(dst *UsageData, src *UsageData) {
+	for key, e := range src.Expressions {
+		delete(dst.Attributes, key)
+		dst.setExpression(key, e)
+	}
+
 	for key, srcAttr := range src.Attributes {
+		delete(dst.Expressions, key)
+
 		if _, has := dst.Attributes[key]; has {
 			switch srcAttr.Type {
 			case gjson.Null:
@@ -0,0 +1,578 @@
+package usage
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Usage values can be written as expressions instead of static numbers, e.g.
+//
+//	variables:
+//	  retrieval_ratio: 0.1
+//	resource_usage:
+//	  aws_s3_bucket.my_bucket:
+//	    monthly_average_capacity: "self.size * 1024"
+//	    monthly_data_retrieval: "var.retrieval_ratio * monthly_average_capacity"
+//
+// Expressions use HCL syntax. They can reference other usage keys of the same
+// resource (or sub-resource) by name, the variables declared in the top-level
+// variables section with var.<name>, and the attributes of the resource the
+// usage applies to with self.<attribute>. Expressions that reference self can
+// only be evaluated once the resource is known, so they are passed on to the
+// providers as schema.UsageExpression values.
+
+const (
+	exprVarRoot  = "var"
+	exprSelfRoot = "self"
+)
+
+var expressionFunctions = map[string]function.Function{
+	"abs":      stdlib.AbsoluteFunc,
+	"ceil":     stdlib.CeilFunc,
+	"coalesce": stdlib.CoalesceFunc,
+	"floor":    stdlib.FloorFunc,
+	"length":   stdlib.LengthFunc,
+	"log":      stdlib.LogFunc,
+	"max":      stdlib.MaxFunc,
+	"min":      stdlib.MinFunc,
+	"pow":      stdlib.PowFunc,
+}
+
+// usageExpression is a parsed usage value expression.
+type usageExpression struct {
+	// path is the location of the expression in the usage file, used in errors.
+	path   string
+	source string
+	expr   hclsyntax.Expression
+	// siblings are the usage keys at the same level referenced by the expression.
+	siblings []string
+	// self is true when the expression references the attributes of the resource.
+	self bool
+	vars cty.Value
+}
+
+// parseUsageExpression checks if the given string value is an expression and
+// parses it if so. Plain strings such as "us-east-1" or "t3.medium" happen to
+// be valid HCL expressions as well, so a string is only treated as an
+// expression if every reference in it is known, i.e. is var, self or one of
+// the given sibling usage keys.
+func parseUsageExpression(path string, source string, siblings map[string]bool) (*usageExpression, bool) {
+	expr, diags := hclsyntax.ParseExpression([]byte(source), path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	switch t := expr.(type) {
+	case *hclsyntax.LiteralValueExpr, *hclsyntax.TemplateExpr:
+		return nil, false
+	case *hclsyntax.ScopeTraversalExpr:
+		// A bare name is most likely a plain string that happens to match a
+		// usage key, e.g. storage_class: standard, so only var and self
+		// references are treated as expressions on their own.
+		if root := t.Traversal.RootName(); root != exprVarRoot && root != exprSelfRoot {
+			return nil, false
+		}
+	}
+
+	e := &usageExpression{
+		path:   path,
+		source: source,
+		expr:   expr,
+	}
+
+	seen := map[string]bool{}
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		switch {
+		case root == exprVarRoot:
+		case root == exprSelfRoot:
+			e.self = true
+		case siblings[root]:
+			if !seen[root] {
+				seen[root] = true
+				e.siblings = append(e.siblings, root)
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	sort.Strings(e.siblings)
+
+	return e, true
+}
+
+// References implements schema.UsageExpression.
+func (e *usageExpression) References() []string {
+	return e.siblings
+}
+
+// Evaluate implements schema.UsageExpression.
+func (e *usageExpression) Evaluate(d *schema.ResourceData, usage map[string]gjson.Result) (gjson.Result, error) {
+	self := cty.EmptyObjectVal
+	if d != nil {
+		var err error
+		self, err = gjsonToCty(d.RawValues)
+		if err != nil {
+			return gjson.Result{}, errors.Wrapf(err, "%s: could not read attributes of %s", e.path, d.Address)
+		}
+	}
+
+	siblings := make(map[string]cty.Value, len(e.siblings))
+	for _, key := range e.siblings {
+		if !usage[key].Exists() {
+			return gjson.Result{}, fmt.Errorf("%s: usage value %s is not set", e.path, key)
+		}
+
+		v, err := gjsonToCty(usage[key])
+		if err != nil {
+			return gjson.Result{}, errors.Wrapf(err, "%s: could not read usage value %s", e.path, key)
+		}
+		siblings[key] = v
+	}
+
+	v, err := e.evaluate(siblings, self)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	b, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return gjson.Result{}, errors.Wrapf(err, "%s: invalid result", e.path)
+	}
+
+	return gjson.ParseBytes(b), nil
+}
+
+func (e *usageExpression) evaluate(siblings map[string]cty.Value, self cty.Value) (cty.Value, error) {
+	vars := make(map[string]cty.Value, len(siblings)+2)
+	for k, v := range siblings {
+		vars[k] = v
+	}
+	vars[exprVarRoot] = e.vars
+	vars[exprSelfRoot] = self
+
+	v, diags := e.expr.Value(&hcl.EvalContext{
+		Variables: vars,
+		Functions: expressionFunctions,
+	})
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("%s: could not evaluate %q: %s", e.path, e.source, diagsSummary(diags))
+	}
+
+	if !v.IsWhollyKnown() || v.IsNull() {
+		return cty.NilVal, fmt.Errorf("%s: expression %q evaluated to an empty value", e.path, e.source)
+	}
+
+	switch v.Type() {
+	case cty.Number:
+		if v.AsBigFloat().IsInf() {
+			return cty.NilVal, fmt.Errorf("%s: expression %q evaluated to infinity", e.path, e.source)
+		}
+		return v, nil
+	case cty.String, cty.Bool:
+		return v, nil
+	}
+
+	return cty.NilVal, fmt.Errorf("%s: expression %q must evaluate to a number, string or bool, got %s", e.path, e.source, v.Type().FriendlyName())
+}
+
+// evaluateVariables evaluates the top-level variables section of the usage
+// file. Variables can reference each other with var.<name> but cannot
+// reference self or usage keys.
+func evaluateVariables(raw yamlv3.Node) (cty.Value, error) {
+	if raw.Kind == 0 || len(raw.Content) == 0 {
+		return cty.EmptyObjectVal, nil
+	}
+
+	if raw.Kind != yamlv3.MappingNode {
+		return cty.NilVal, fmt.Errorf("variables (line %d): expected a map of variable names to values", raw.Line)
+	}
+
+	items := make(map[string]*schema.UsageItem, len(raw.Content)/2)
+	exprs := make(map[string]*usageExpression)
+	deps := make(map[string][]string)
+	values := make(map[string]cty.Value)
+
+	for i := 0; i+1 < len(raw.Content); i += 2 {
+		item, err := usageItemFromYAML(raw.Content[i], raw.Content[i+1])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if item.ValueType == schema.SubResourceUsage {
+			return cty.NilVal, fmt.Errorf("variables.%s (line %d): variables must be a number, string or expression", item.Key, raw.Content[i].Line)
+		}
+		items[item.Key] = item
+	}
+
+	for key, item := range items {
+		path := "variables." + key
+
+		s, ok := item.Value.(string)
+		if !ok {
+			v, err := goToCty(item.Value)
+			if err != nil {
+				return cty.NilVal, errors.Wrap(err, path)
+			}
+			values[key] = v
+			continue
+		}
+
+		e, ok := parseUsageExpression(path, s, nil)
+		if !ok {
+			values[key] = cty.StringVal(s)
+			continue
+		}
+
+		if e.self {
+			return cty.NilVal, fmt.Errorf("%s: variables cannot reference self", path)
+		}
+
+		exprs[key] = e
+		deps[key] = nil
+		for _, traversal := range e.expr.Variables() {
+			name, err := varTraversalName(traversal)
+			if err != nil {
+				return cty.NilVal, errors.Wrap(err, path)
+			}
+			if _, ok := items[name]; !ok {
+				return cty.NilVal, fmt.Errorf("%s: reference to undeclared variable var.%s", path, name)
+			}
+			if isExpressionItem(items[name]) {
+				deps[key] = append(deps[key], name)
+			}
+		}
+	}
+
+	order, err := evaluationOrder("variables", deps)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	for _, key := range order {
+		e, ok := exprs[key]
+		if !ok {
+			continue
+		}
+
+		e.vars = cty.ObjectVal(values)
+		v, err := e.evaluate(nil, cty.EmptyObjectVal)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		values[key] = v
+	}
+
+	return cty.ObjectVal(values), nil
+}
+
+func isExpressionItem(item *schema.UsageItem) bool {
+	s, ok := item.Value.(string)
+	if !ok {
+		return false
+	}
+	_, ok = parseUsageExpression("", s, nil)
+	return ok
+}
+
+func varTraversalName(traversal hcl.Traversal) (string, error) {
+	if len(traversal) < 2 {
+		return "", errors.New("var must be followed by a variable name, e.g. var.my_variable")
+	}
+
+	switch t := traversal[1].(type) {
+	case hcl.TraverseAttr:
+		return t.Name, nil
+	case hcl.TraverseIndex:
+		if t.Key.Type() == cty.String {
+			return t.Key.AsString(), nil
+		}
+	}
+
+	return "", errors.New("var must be followed by a variable name, e.g. var.my_variable")
+}
+
+// evaluateResourceUsage evaluates any expressions in the given resource usage,
+// using the value of any time series for the given month. It returns the
+// resulting usage values, ready for schema.ParseAttributes, and the expressions
+// that reference self and so have to be evaluated by the provider once the
+// resource is known.
+func evaluateResourceUsage(resourceUsage *ResourceUsage, vars cty.Value, month int) (map[string]interface{}, map[string]schema.UsageExpression, error) {
+	m, deferred, err := evaluateUsageItems(resourceUsage.Name, resourceUsage.Items, vars, month, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exprs map[string]schema.UsageExpression
+	if len(deferred) > 0 {
+		exprs = make(map[string]schema.UsageExpression, len(deferred))
+		for k, e := range deferred {
+			exprs[k] = e
+		}
+	}
+
+	return m, exprs, nil
+}
+
+func evaluateUsageItems(path string, items []*schema.UsageItem, vars cty.Value, month int, allowDeferred bool) (map[string]interface{}, map[string]*usageExpression, error) {
+	m := make(map[string]interface{}, len(items))
+	siblings := make(map[string]bool, len(items))
+	for _, item := range items {
+		siblings[item.Key] = true
+	}
+
+	exprs := make(map[string]*usageExpression)
+	deps := make(map[string][]string)
+
+	for _, item := range items {
+		itemPath := path + "." + item.Key
+
+		if isTimeSeriesItem(item) {
+			ts, err := parseTimeSeries(itemPath, item.Value.(*ResourceUsage).Items)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[item.Key] = ts.valueAt(month)
+			continue
+		}
+
+		if item.ValueType == schema.SubResourceUsage {
+			if item.Value == nil {
+				m[item.Key] = map[string]interface{}{}
+				continue
+			}
+
+			sub, _, err := evaluateUsageItems(itemPath, item.Value.(*ResourceUsage).Items, vars, month, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[item.Key] = sub
+			continue
+		}
+
+		s, ok := item.Value.(string)
+		if !ok {
+			m[item.Key] = item.Value
+			continue
+		}
+
+		e, ok := parseUsageExpression(itemPath, s, siblings)
+		if !ok {
+			m[item.Key] = item.Value
+			continue
+		}
+
+		if e.self && !allowDeferred {
+			return nil, nil, fmt.Errorf("%s: self can only be referenced by top-level usage keys", itemPath)
+		}
+
+		for _, traversal := range e.expr.Variables() {
+			if traversal.RootName() != exprVarRoot {
+				continue
+			}
+			name, err := varTraversalName(traversal)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, itemPath)
+			}
+			if !vars.Type().HasAttribute(name) {
+				return nil, nil, fmt.Errorf("%s: reference to undeclared variable var.%s", itemPath, name)
+			}
+		}
+
+		e.vars = vars
+		exprs[item.Key] = e
+		deps[item.Key] = nil
+		for _, sibling := range e.siblings {
+			if _, ok := m[sibling]; ok {
+				continue
+			}
+			deps[item.Key] = append(deps[item.Key], sibling)
+		}
+	}
+
+	order, err := evaluationOrder(path, deps)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deferred := make(map[string]*usageExpression)
+
+	for _, key := range order {
+		e, ok := exprs[key]
+		if !ok {
+			continue
+		}
+
+		isDeferred := e.self
+		siblingVals := make(map[string]cty.Value, len(e.siblings))
+		for _, sibling := range e.siblings {
+			if _, ok := deferred[sibling]; ok {
+				isDeferred = true
+				break
+			}
+
+			v, err := goToCty(m[sibling])
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "%s: could not read usage value %s", e.path, sibling)
+			}
+			siblingVals[sibling] = v
+		}
+
+		if isDeferred {
+			deferred[key] = e
+			continue
+		}
+
+		v, err := e.evaluate(siblingVals, cty.EmptyObjectVal)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m[key] = ctyToGo(v)
+	}
+
+	return m, deferred, nil
+}
+
+// evaluationOrder returns the keys of the given dependency graph in the order
+// they need to be evaluated. It returns an error describing the cycle if the
+// keys reference each other.
+func evaluationOrder(path string, deps map[string][]string) ([]string, error) {
+	keys := make([]string, 0, len(deps))
+	for k := range deps {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := make(map[string]int, len(deps))
+	order := make([]string, 0, len(deps))
+	var stack []string
+
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, k := range stack {
+				if k == key {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, stack[start:]...), key)
+			return fmt.Errorf("%s: cycle detected in usage expressions: %s", path, strings.Join(cycle, " -> "))
+		}
+
+		state[key] = visiting
+		stack = append(stack, key)
+
+		for _, dep := range deps[key] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+		order = append(order, key)
+
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func diagsSummary(diags hcl.Diagnostics) string {
+	msgs := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg = fmt.Sprintf("%s: %s", msg, diag.Detail)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func goToCty(v interface{}) (cty.Value, error) {
+	switch t := v.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case int:
+		return cty.NumberIntVal(int64(t)), nil
+	case int64:
+		return cty.NumberIntVal(t), nil
+	case float64:
+		return cty.NumberFloatVal(t), nil
+	case string:
+		return cty.StringVal(t), nil
+	case bool:
+		return cty.BoolVal(t), nil
+	}
+
+	return cty.NilVal, fmt.Errorf("unsupported value type %T", v)
+}
+
+func ctyToGo(v cty.Value) interface{} {
+	switch v.Type() {
+	case cty.String:
+		return v.AsString()
+	case cty.Bool:
+		return v.True()
+	}
+
+	bf := v.AsBigFloat()
+	if bf.IsInt() {
+		if i, acc := bf.Int64(); acc == big.Exact {
+			return i
+		}
+	}
+
+	f, _ := bf.Float64()
+	return f
+}
+
+func gjsonToCty(r gjson.Result) (cty.Value, error) {
+	if !r.Exists() || r.Type == gjson.Null {
+		return cty.EmptyObjectVal, nil
+	}
+
+	b := []byte(r.Raw)
+	t, err := ctyjson.ImpliedType(b)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(b, t)
+}
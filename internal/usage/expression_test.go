@@ -0,0 +1,133 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestUsageExpressions(t *testing.T) {
+	usageFile, err := LoadUsageFileFromString(`
+version: 0.1
+variables:
+  retrieval_ratio: 0.1
+  requests_per_user: 30
+  users: "var.base_users * 2"
+  base_users: 500
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: "var.users * var.requests_per_user"
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    standard:
+      storage_gb: "1000 * var.retrieval_ratio"
+    monthly_average_capacity: 2000
+    monthly_data_retrieval: "var.retrieval_ratio * monthly_average_capacity"
+    storage_class: us-east-1
+    instance_type: t3.medium
+  aws_ebs_volume.my_volume:
+    storage_gb: "self.size"
+    monthly_snapshot_gb: "ceil(storage_gb * 0.5)"
+`)
+	require.NoError(t, err)
+
+	m := usageFile.ToUsageDataMap()
+
+	lambda := m["aws_lambda_function"]
+	assert.Equal(t, int64(30000), lambda.Get("monthly_requests").Int())
+
+	bucket := m["aws_s3_bucket.my_bucket"]
+	assert.Equal(t, 200.0, bucket.Get("monthly_data_retrieval").Float())
+	assert.Equal(t, 100.0, bucket.Get("standard").Get("storage_gb").Float())
+	assert.Equal(t, "us-east-1", bucket.Get("storage_class").String())
+	assert.Equal(t, "t3.medium", bucket.Get("instance_type").String())
+	assert.Empty(t, bucket.Expressions)
+
+	volume := m["aws_ebs_volume.my_volume"]
+	assert.False(t, volume.Get("storage_gb").Exists())
+	assert.Len(t, volume.Expressions, 2)
+
+	d := schema.NewResourceData("aws_ebs_volume", "aws", "aws_ebs_volume.my_volume", nil, gjson.Parse(`{"size": 15}`))
+	resolved, err := volume.ForResource(d)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), resolved.Get("storage_gb").Int())
+	assert.Equal(t, int64(8), resolved.Get("monthly_snapshot_gb").Int())
+
+	// The original usage data should not be modified so it can be shared between resources
+	assert.Len(t, volume.Expressions, 2)
+}
+
+func TestUsageExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{
+			name: "cycle",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    a: "b * 2"
+    b: "c + 1"
+    c: "a / 2"
+`,
+			err: "aws_s3_bucket.my_bucket: cycle detected in usage expressions: a -> b -> c -> a",
+		},
+		{
+			name: "variable cycle",
+			yaml: `
+version: 0.1
+variables:
+  x: "var.y + 1"
+  y: "var.x + 1"
+`,
+			err: "variables: cycle detected in usage expressions: x -> y -> x",
+		},
+		{
+			name: "undeclared variable",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    monthly_data_retrieval: "var.missing * 2"
+`,
+			err: "aws_s3_bucket.my_bucket.monthly_data_retrieval: reference to undeclared variable var.missing",
+		},
+		{
+			name: "nested self",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    standard:
+      storage_gb: "self.size"
+`,
+			err: "aws_s3_bucket.my_bucket.standard.storage_gb: self can only be referenced by top-level usage keys",
+		},
+		{
+			name: "invalid result",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    a: 1
+    b: "a / 0"
+`,
+			err: `aws_s3_bucket.my_bucket.b: expression "a / 0" evaluated to infinity`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadUsageFileFromString(tt.yaml)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -44,6 +44,13 @@ func (r *ResourceUsage) MergeResourceUsage(src *ResourceUsage) {
 			r.Items = append(r.Items, destItem)
 		}
 
+		if isTimeSeriesItem(srcItem) {
+			if destItem.Value == nil {
+				destItem.Value = srcItem.Value
+			}
+			continue
+		}
+
 		if srcItem.ValueType == schema.SubResourceUsage {
 			if srcItem.DefaultValue != nil {
 				srcDefaultValue := srcItem.DefaultValue.(*ResourceUsage)
@@ -196,6 +203,23 @@ func ResourceUsagesToYAML(resourceUsages []*ResourceUsage) (yamlv3.Node, bool) {
 				rootNodeIsCommented = false
 			}
 
+			if isTimeSeriesItem(item) {
+				// Time series are rendered as they were written, whatever the value type
+				itemKeyNode := &yamlv3.Node{
+					Kind:  yamlv3.ScalarNode,
+					Tag:   "!!str",
+					Value: item.Key,
+				}
+
+				subResourceValNode, _ := ResourceUsagesToYAML([]*ResourceUsage{item.Value.(*ResourceUsage)})
+				itemValNode := subResourceValNode.Content[1]
+				itemValNode.LineComment = item.Description
+
+				resourceValNode.Content = append(resourceValNode.Content, itemKeyNode, itemValNode)
+
+				continue
+			}
+
 			if item.ValueType == schema.SubResourceUsage {
 				// If the value is a subresource, we need to add in any missing default sub-items
 				// so they get rendered as comments
@@ -231,7 +255,13 @@ func ResourceUsagesToYAML(resourceUsages []*ResourceUsage) (yamlv3.Node, bool) {
 			var tag string
 			var value string
 
-			switch item.ValueType {
+			valueType := item.ValueType
+			// Usage expressions are strings whatever the value type they evaluate to
+			if _, ok := rawValue.(string); ok && (valueType == schema.Int64 || valueType == schema.Float64) {
+				valueType = schema.String
+			}
+
+			switch valueType {
 			case schema.Float64:
 				tag = "!!float"
 
@@ -260,6 +290,18 @@ func ResourceUsagesToYAML(resourceUsages []*ResourceUsage) (yamlv3.Node, bool) {
 			case schema.String:
 				tag = "!!str"
 				value = fmt.Sprintf("%s", rawValue)
+
+				// Lists of numbers, e.g. a time series, are parsed as string values
+				if values, ok := rawValue.([]interface{}); ok {
+					tag = "!!seq"
+					value = ""
+					kind = yamlv3.SequenceNode
+					for _, v := range values {
+						n := &yamlv3.Node{}
+						_ = n.Encode(v)
+						content = append(content, n)
+					}
+				}
 			case schema.StringArray:
 				tag = "!!seq"
 				kind = yamlv3.SequenceNode
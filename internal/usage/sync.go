@@ -307,6 +307,13 @@ func replaceResourceUsages(dest *ResourceUsage, src *ResourceUsage, opts Replace
 			destItem.Description = srcItem.Description
 		}
 
+		// Time series values are kept as they are, rather than being merged with the
+		// defaults, since they replace a single usage value.
+		if isTimeSeriesItem(srcItem) {
+			destItem.Value = srcItem.Value
+			continue
+		}
+
 		if srcItem.ValueType == schema.SubResourceUsage {
 			if srcItem.DefaultValue != nil {
 				srcDefaultValue := srcItem.DefaultValue.(*ResourceUsage)
@@ -348,6 +355,11 @@ func mergeResourceUsageWithUsageData(resourceUsage *ResourceUsage, usageData *sc
 	}
 
 	for _, item := range resourceUsage.Items {
+		// Don't override usage expressions or time series with their estimated values
+		if isExpressionItem(item) || isTimeSeriesItem(item) {
+			continue
+		}
+
 		var val interface{}
 
 		switch item.ValueType {
@@ -0,0 +1,212 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	timeSeriesStart     = "start"
+	timeSeriesGrowth    = "growth"
+	timeSeriesIncrement = "increment"
+	timeSeriesSeries    = "series"
+)
+
+var timeSeriesKeys = map[string]bool{
+	timeSeriesStart:     true,
+	timeSeriesGrowth:    true,
+	timeSeriesIncrement: true,
+	timeSeriesSeries:    true,
+}
+
+// timeSeries is a usage value that changes from month to month, e.g.
+//
+//	monthly_requests:
+//	  start: 1000000
+//	  growth: 5%
+//
+// The value for a month is either taken from an explicit series, where the
+// last value is used for any months after the end of the series, or is
+// calculated from the start value with compound growth and a linear increment
+// applied each month. Month 0 is the first month and is the value used for a
+// normal, single month, estimate.
+type timeSeries struct {
+	start     float64
+	growth    float64
+	increment float64
+	series    []float64
+}
+
+// isTimeSeriesKeys returns true if the given keys describe a time series
+// rather than a nested usage block.
+func isTimeSeriesKeys(keys []string) bool {
+	var hasValue bool
+	for _, k := range keys {
+		if !timeSeriesKeys[k] {
+			return false
+		}
+
+		if k == timeSeriesStart || k == timeSeriesSeries {
+			hasValue = true
+		}
+	}
+
+	return hasValue
+}
+
+// isTimeSeriesNode returns true if the YAML node is a mapping that describes
+// a time series.
+func isTimeSeriesNode(node *yamlv3.Node) bool {
+	if node.Kind != yamlv3.MappingNode {
+		return false
+	}
+
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return isTimeSeriesKeys(keys)
+}
+
+// isTimeSeriesItem returns true if the usage item value is a nested usage
+// block that describes a time series. The value type of the item might not be
+// SubResourceUsage if it has been synced with the resource usage schema.
+func isTimeSeriesItem(item *schema.UsageItem) bool {
+	ru, ok := item.Value.(*ResourceUsage)
+	if !ok {
+		return false
+	}
+
+	keys := make([]string, 0, len(ru.Items))
+	for _, i := range ru.Items {
+		keys = append(keys, i.Key)
+	}
+
+	return isTimeSeriesKeys(keys)
+}
+
+// parseTimeSeries parses the time series from the items of a nested usage
+// block. The caller should check isTimeSeriesItem first.
+func parseTimeSeries(path string, items []*schema.UsageItem) (*timeSeries, error) {
+	ts := &timeSeries{}
+
+	var hasStart, hasSeries bool
+
+	for _, item := range items {
+		itemPath := path + "." + item.Key
+
+		switch item.Key {
+		case timeSeriesStart:
+			v, err := timeSeriesNumber(itemPath, item.Value)
+			if err != nil {
+				return nil, err
+			}
+			ts.start = v
+			hasStart = true
+		case timeSeriesGrowth:
+			v, err := timeSeriesGrowthRate(itemPath, item.Value)
+			if err != nil {
+				return nil, err
+			}
+			ts.growth = v
+		case timeSeriesIncrement:
+			v, err := timeSeriesNumber(itemPath, item.Value)
+			if err != nil {
+				return nil, err
+			}
+			ts.increment = v
+		case timeSeriesSeries:
+			values, ok := item.Value.([]interface{})
+			if !ok || len(values) == 0 {
+				return nil, fmt.Errorf("%s: must be a non-empty list of numbers", itemPath)
+			}
+
+			for i, raw := range values {
+				v, err := timeSeriesNumber(fmt.Sprintf("%s[%d]", itemPath, i), raw)
+				if err != nil {
+					return nil, err
+				}
+				ts.series = append(ts.series, v)
+			}
+			hasSeries = true
+		}
+	}
+
+	if hasStart && hasSeries {
+		return nil, fmt.Errorf("%s: start and series cannot both be set", path)
+	}
+
+	if hasSeries && (ts.growth != 0 || ts.increment != 0) {
+		return nil, fmt.Errorf("%s: growth and increment cannot be used with series", path)
+	}
+
+	return ts, nil
+}
+
+// valueAt returns the value of the time series for the given month.
+func (t *timeSeries) valueAt(month int) interface{} {
+	if month < 0 {
+		month = 0
+	}
+
+	var v float64
+	if len(t.series) > 0 {
+		v = t.series[len(t.series)-1]
+		if month < len(t.series) {
+			v = t.series[month]
+		}
+	} else {
+		v = t.start*math.Pow(1+t.growth, float64(month)) + t.increment*float64(month)
+	}
+
+	if v < 0 {
+		v = 0
+	}
+
+	if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+		return int64(v)
+	}
+
+	return v
+}
+
+func timeSeriesNumber(path string, value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("%s: must be a number", path)
+}
+
+// timeSeriesGrowthRate parses a monthly growth rate which can either be a
+// fraction, e.g. 0.05, or a percentage string, e.g. "5%".
+func timeSeriesGrowthRate(path string, value interface{}) (float64, error) {
+	s, ok := value.(string)
+	if !ok {
+		return timeSeriesNumber(path, value)
+	}
+
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasSuffix(trimmed, "%") {
+		return 0, fmt.Errorf("%s: must be a number or a percentage, e.g. 5%%", path)
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(trimmed, "%")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: must be a number or a percentage, e.g. 5%%", path)
+	}
+
+	return v / 100, nil
+}
@@ -0,0 +1,154 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTimeSeriesUsage(t *testing.T) {
+	usageFile, err := LoadUsageFileFromString(`
+version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests:
+      start: 1000
+      growth: 10%
+resource_usage:
+  aws_s3_bucket.my_bucket:
+    standard:
+      storage_gb:
+        start: 100
+        increment: 50
+      monthly_tier_1_requests: 10
+    monthly_data_retrieval: "standard_gb * 2"
+    standard_gb:
+      series: [10, 20.5, 40]
+`)
+	require.NoError(t, err)
+	assert.True(t, usageFile.HasTimeSeries())
+
+	tests := []struct {
+		month     int
+		requests  int64
+		storageGB int64
+		series    float64
+	}{
+		{0, 1000, 100, 10},
+		{1, 1100, 150, 20.5},
+		{2, 1210, 200, 40},
+		{5, 1610, 350, 40},
+	}
+
+	for _, tt := range tests {
+		m := usageFile.ToUsageDataMapForMonth(tt.month)
+
+		assert.Equal(t, tt.requests, m["aws_lambda_function"].Get("monthly_requests").Int(), "month %d", tt.month)
+
+		bucket := m["aws_s3_bucket.my_bucket"]
+		assert.Equal(t, tt.storageGB, bucket.Get("standard").Get("storage_gb").Int(), "month %d", tt.month)
+		assert.Equal(t, int64(10), bucket.Get("standard").Get("monthly_tier_1_requests").Int(), "month %d", tt.month)
+		assert.Equal(t, tt.series, bucket.Get("standard_gb").Float(), "month %d", tt.month)
+		assert.Equal(t, tt.series*2, bucket.Get("monthly_data_retrieval").Float(), "month %d", tt.month)
+	}
+
+	// The first month is used when no month is given
+	assert.Equal(t, int64(1000), usageFile.ToUsageDataMap()["aws_lambda_function"].Get("monthly_requests").Int())
+}
+
+func TestTimeSeriesUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{
+			name: "start and series",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests:
+      start: 1000
+      series: [1, 2]
+`,
+			err: "aws_lambda_function.api.monthly_requests: start and series cannot both be set",
+		},
+		{
+			name: "invalid growth",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests:
+      start: 1000
+      growth: fast
+`,
+			err: "aws_lambda_function.api.monthly_requests.growth: must be a number or a percentage, e.g. 5%",
+		},
+		{
+			name: "invalid series",
+			yaml: `
+version: 0.1
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests:
+      series: [1, lots]
+`,
+			err: "aws_lambda_function.api.monthly_requests.series[1]: must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadUsageFileFromString(tt.yaml)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestTimeSeriesUsageSync(t *testing.T) {
+	usageFile, err := LoadUsageFileFromString(`
+version: 0.1
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests:
+      start: 1000
+      growth: 5%
+    request_duration_ms: "var.duration"
+variables:
+  duration: 250
+`)
+	require.NoError(t, err)
+
+	// Sync the usage file with a schema where the values are plain numbers
+	resourceUsage := &ResourceUsage{
+		Name: "aws_lambda_function.api",
+		Items: []*schema.UsageItem{
+			{Key: "monthly_requests", ValueType: schema.Int64, DefaultValue: 0},
+			{Key: "request_duration_ms", ValueType: schema.Int64, DefaultValue: 0},
+		},
+	}
+	replaceResourceUsages(resourceUsage, usageFile.ResourceUsages[0], ReplaceResourceUsagesOpts{})
+	usageFile.ResourceUsages = []*ResourceUsage{resourceUsage}
+
+	path := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, usageFile.WriteToPath(path))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `    monthly_requests:
+      start: 1000
+      growth: 5%
+    request_duration_ms: var.duration`)
+
+	synced, err := LoadUsageFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), synced.ToUsageDataMapForMonth(1)["aws_lambda_function.api"].Get("monthly_requests").Int())
+}
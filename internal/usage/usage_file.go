@@ -10,6 +10,7 @@ import (
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
 	"golang.org/x/mod/semver"
 	yamlv3 "gopkg.in/yaml.v3"
 )
@@ -19,6 +20,10 @@ const maxUsageFileVersion = "0.1"
 
 type UsageFile struct { // nolint:revive
 	Version string `yaml:"version"`
+	// RawVariables holds the project-wide variables that usage expressions can reference with var.<name>
+	RawVariables yamlv3.Node `yaml:"variables,omitempty"`
+	// The evaluated variables
+	variables cty.Value
 	// We represent resource type usage in using a YAML node so we have control over the comments
 	RawResourceTypeUsage yamlv3.Node `yaml:"resource_type_default_usage"`
 	// The raw usage is then parsed into this struct
@@ -98,6 +103,11 @@ func LoadUsageFileFromString(s string) (*UsageFile, error) {
 		return usageFile, errors.Wrap(err, "Error loading YAML file")
 	}
 
+	err = usageFile.checkExpressions()
+	if err != nil {
+		return usageFile, errors.Wrap(err, "Error evaluating usage expressions")
+	}
+
 	return usageFile, nil
 }
 
@@ -137,6 +147,19 @@ See https://infracost.io/usage-file/ for docs`,
 			Kind:  yamlv3.ScalarNode,
 			Value: u.Version,
 		},
+	)
+
+	if len(u.RawVariables.Content) > 0 {
+		root.Content = append(root.Content,
+			&yamlv3.Node{
+				Kind:  yamlv3.ScalarNode,
+				Value: "variables",
+			},
+			&u.RawVariables,
+		)
+	}
+
+	root.Content = append(root.Content,
 		resourceTypeUsagesKeyNode,
 		&u.RawResourceTypeUsage,
 		resourceUsagesKeyNode,
@@ -180,20 +203,111 @@ See https://infracost.io/usage-file/ for docs`,
 	return os.WriteFile(path, b, 0600)
 }
 
+// ToUsageDataMap returns the usage data for each resource and resource type
+// in the usage file. Any usage expressions are evaluated, apart from the ones
+// that reference the resource attributes, which are set as the UsageData
+// Expressions so they can be evaluated once the resource is known. Time series
+// usage values use their value for the first month.
 func (u *UsageFile) ToUsageDataMap() map[string]*schema.UsageData {
+	return u.ToUsageDataMapForMonth(0)
+}
+
+// ToUsageDataMapForMonth returns the usage data like ToUsageDataMap, but with
+// any time series usage values set to their value for the given month, where
+// month 0 is the first month.
+func (u *UsageFile) ToUsageDataMapForMonth(month int) map[string]*schema.UsageData {
 	m := make(map[string]*schema.UsageData)
 
 	for _, resourceUsage := range u.ResourceTypeUsages {
-		m[resourceUsage.Name] = schema.NewUsageData(resourceUsage.Name, schema.ParseAttributes(resourceUsage.Map()))
+		m[resourceUsage.Name] = u.usageData(resourceUsage, month)
 	}
 
 	for _, resourceUsage := range u.ResourceUsages {
-		m[resourceUsage.Name] = schema.NewUsageData(resourceUsage.Name, schema.ParseAttributes(resourceUsage.Map()))
+		m[resourceUsage.Name] = u.usageData(resourceUsage, month)
 	}
 
 	return m
 }
 
+// HasTimeSeries returns true if any of the usage values in the usage file
+// change from month to month.
+func (u *UsageFile) HasTimeSeries() bool {
+	for _, resourceUsages := range [][]*ResourceUsage{u.ResourceTypeUsages, u.ResourceUsages} {
+		for _, resourceUsage := range resourceUsages {
+			if hasTimeSeriesItems(resourceUsage.Items) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasTimeSeriesItems(items []*schema.UsageItem) bool {
+	for _, item := range items {
+		if isTimeSeriesItem(item) {
+			return true
+		}
+
+		if ru, ok := item.Value.(*ResourceUsage); ok && hasTimeSeriesItems(ru.Items) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *UsageFile) usageData(resourceUsage *ResourceUsage, month int) *schema.UsageData {
+	values, exprs, err := evaluateResourceUsage(resourceUsage, u.variablesValue(), month)
+	if err != nil {
+		// The expressions are checked when the usage file is loaded so this should only
+		// happen if the resource usages have been modified since.
+		log.Errorf("Error evaluating usage expressions: %v", err)
+		return schema.NewUsageData(resourceUsage.Name, schema.ParseAttributes(resourceUsage.Map()))
+	}
+
+	usageData := schema.NewUsageData(resourceUsage.Name, schema.ParseAttributes(values))
+	for k := range exprs {
+		delete(usageData.Attributes, k)
+	}
+	usageData.Expressions = exprs
+
+	return usageData
+}
+
+func (u *UsageFile) variablesValue() cty.Value {
+	if u.variables.IsNull() {
+		return cty.EmptyObjectVal
+	}
+
+	return u.variables
+}
+
+// checkExpressions evaluates the variables, usage expressions and time series
+// in the usage file so that any errors, such as references to undeclared
+// variables or cycles, are reported when the file is loaded.
+func (u *UsageFile) checkExpressions() error {
+	var err error
+	u.variables, err = evaluateVariables(u.RawVariables)
+	if err != nil {
+		return err
+	}
+
+	for _, resourceUsage := range u.ResourceTypeUsages {
+		if _, _, err := evaluateResourceUsage(resourceUsage, u.variables, 0); err != nil {
+			return err
+		}
+	}
+
+	for _, resourceUsage := range u.ResourceUsages {
+		if _, _, err := evaluateResourceUsage(resourceUsage, u.variables, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (u *UsageFile) checkVersion() bool {
 	v := u.Version
 	if !strings.HasPrefix(u.Version, "v") {
@@ -269,9 +383,9 @@ func findInvalidKeys(item *schema.UsageItem, refMap map[string]interface{}) []st
 
 	if refVal, ok := refMap[item.Key]; !ok {
 		invalidKeys = append(invalidKeys, item.Key)
-	} else if item.ValueType == schema.SubResourceUsage && item.Value != nil {
+	} else if refSubMap, ok := refVal.(map[string]interface{}); ok && item.ValueType == schema.SubResourceUsage && item.Value != nil {
 		for _, subItem := range item.Value.(*ResourceUsage).Items {
-			invalidKeys = append(invalidKeys, findInvalidKeys(subItem, refVal.(map[string]interface{}))...)
+			invalidKeys = append(invalidKeys, findInvalidKeys(subItem, refSubMap)...)
 		}
 	}
 
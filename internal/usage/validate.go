@@ -0,0 +1,430 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ValidationSeverity is the severity of a ValidationIssue.
+type ValidationSeverity string
+
+const (
+	SeverityError   ValidationSeverity = "error"
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// Codes for the different types of ValidationIssue.
+const (
+	IssueInvalidType         = "invalid_type"
+	IssueNegativeValue       = "negative_value"
+	IssueImplausibleValue    = "implausible_value"
+	IssueUnknownKey          = "unknown_key"
+	IssueUnknownAddress      = "unknown_address"
+	IssueStaleIndex          = "stale_index"
+	IssueUnmatchedWildcard   = "unmatched_wildcard"
+	IssueUnknownResourceType = "unknown_resource_type"
+)
+
+// maxMonthlyHours is the number of hours in the longest month.
+const maxMonthlyHours = 744
+
+// ValidationIssue is a problem found in the usage file by Validate. Line and
+// Column point to the YAML node the issue relates to.
+type ValidationIssue struct {
+	Severity ValidationSeverity `json:"severity"`
+	Code     string             `json:"code"`
+	Address  string             `json:"address"`
+	Key      string             `json:"key,omitempty"`
+	Message  string             `json:"message"`
+	Line     int                `json:"line"`
+	Column   int                `json:"column"`
+}
+
+// ValidationResult contains all the issues found in a usage file.
+type ValidationResult struct {
+	Filename string            `json:"filename"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// HasErrors returns true if any of the issues are errors. If strict is
+// true warnings are counted as errors.
+func (r ValidationResult) HasErrors(strict bool) bool {
+	for _, issue := range r.Issues {
+		if strict || issue.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+type usageValidator struct {
+	resources []*schema.Resource
+	refFile   *ReferenceFile
+	issues    []ValidationIssue
+}
+
+// Validate checks the usage file against the given resources. It reports
+// usage values that do not match the type in the resource UsageSchema,
+// negative or implausible values, keys that the resources do not use, and
+// resource addresses, wildcards and resource types that do not match any of
+// the given resources.
+func (u *UsageFile) Validate(filename string, resources []*schema.Resource) (ValidationResult, error) {
+	refFile, err := LoadReferenceFile()
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	v := &usageValidator{
+		resources: resources,
+		refFile:   refFile,
+	}
+
+	v.validateResourceTypeUsages(&u.RawResourceTypeUsage)
+	v.validateResourceUsages(&u.RawResourceUsage)
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].Line != v.issues[j].Line {
+			return v.issues[i].Line < v.issues[j].Line
+		}
+		return v.issues[i].Column < v.issues[j].Column
+	})
+
+	return ValidationResult{
+		Filename: filename,
+		Issues:   v.issues,
+	}, nil
+}
+
+func (v *usageValidator) add(severity ValidationSeverity, code string, node *yamlv3.Node, address, key, msg string) {
+	v.issues = append(v.issues, ValidationIssue{
+		Severity: severity,
+		Code:     code,
+		Address:  address,
+		Key:      key,
+		Message:  msg,
+		Line:     node.Line,
+		Column:   node.Column,
+	})
+}
+
+func (v *usageValidator) validateResourceTypeUsages(raw *yamlv3.Node) {
+	for i := 0; i+1 < len(raw.Content); i += 2 {
+		keyNode, valNode := raw.Content[i], raw.Content[i+1]
+		resourceType := keyNode.Value
+
+		var match *schema.Resource
+		for _, r := range v.resources {
+			if r.ResourceType == resourceType {
+				match = r
+				break
+			}
+		}
+
+		if match == nil {
+			v.add(SeverityWarning, IssueUnknownResourceType, keyNode, resourceType, "",
+				fmt.Sprintf("No resources of type %s were found in the project", resourceType))
+			continue
+		}
+
+		items, exactTypes := v.usageSchema(match)
+		if items == nil {
+			if ref := v.refFile.FindMatchingResourceTypeUsage(resourceType); ref != nil {
+				items = ref.Items
+			}
+		}
+
+		v.validateItems(resourceType, "", valNode, items, exactTypes)
+	}
+}
+
+func (v *usageValidator) validateResourceUsages(raw *yamlv3.Node) {
+	for i := 0; i+1 < len(raw.Content); i += 2 {
+		keyNode, valNode := raw.Content[i], raw.Content[i+1]
+		address := keyNode.Value
+
+		match := v.matchResource(keyNode, address)
+		if match == nil {
+			continue
+		}
+
+		items, exactTypes := v.usageSchema(match)
+		if items == nil {
+			if ref := v.refFile.FindMatchingResourceUsage(address); ref != nil {
+				items = ref.Items
+			}
+		}
+
+		v.validateItems(address, "", valNode, items, exactTypes)
+	}
+}
+
+// usageSchema returns the usage schema of the resource. The returned bool is
+// true if the schema comes from the resource itself, so the int and float
+// value types are exact. Otherwise the caller falls back to the reference
+// usage file, where the value types are inferred from the example values.
+func (v *usageValidator) usageSchema(r *schema.Resource) ([]*schema.UsageItem, bool) {
+	if len(r.UsageSchema) > 0 {
+		return r.UsageSchema, true
+	}
+
+	return nil, false
+}
+
+// matchResource returns a resource that matches the given usage file address,
+// adding an issue if none do.
+func (v *usageValidator) matchResource(keyNode *yamlv3.Node, address string) *schema.Resource {
+	if strings.HasSuffix(address, "[*]") {
+		prefix := strings.TrimSuffix(address, "*]")
+		for _, r := range v.resources {
+			if strings.HasPrefix(r.Name, prefix) {
+				return r
+			}
+		}
+
+		v.add(SeverityWarning, IssueUnmatchedWildcard, keyNode, address, "",
+			fmt.Sprintf("Wildcard %s does not match any resources in the project", address))
+		return nil
+	}
+
+	for _, r := range v.resources {
+		if r.Name == address {
+			return r
+		}
+	}
+
+	// Work out if the address is for a resource with count or for_each that has
+	// been scaled down or had its keys changed, so we can give a helpful message.
+	prefix := address + "["
+	if strings.HasSuffix(address, "]") {
+		prefix = address[:strings.LastIndex(address, "[")+1]
+	}
+
+	var indexed []string
+	for _, r := range v.resources {
+		if strings.HasPrefix(r.Name, prefix) {
+			indexed = append(indexed, r.Name)
+		}
+	}
+
+	if len(indexed) == 0 {
+		v.add(SeverityWarning, IssueUnknownAddress, keyNode, address, "",
+			fmt.Sprintf("No resource with address %s was found in the project", address))
+		return nil
+	}
+
+	sort.Strings(indexed)
+	available := indexed
+	if len(available) > 5 {
+		available = append(available[:5:5], "...")
+	}
+
+	if strings.HasSuffix(address, "]") {
+		v.add(SeverityWarning, IssueStaleIndex, keyNode, address, "",
+			fmt.Sprintf("No resource with address %s was found in the project, the count or for_each keys have changed. Found: %s", address, strings.Join(available, ", ")))
+	} else {
+		v.add(SeverityWarning, IssueUnknownAddress, keyNode, address, "",
+			fmt.Sprintf("Resource %s uses count or for_each, use %s*] or a specific key instead. Found: %s", address, prefix, strings.Join(available, ", ")))
+	}
+
+	return nil
+}
+
+func (v *usageValidator) validateItems(address, path string, node *yamlv3.Node, items []*schema.UsageItem, exactTypes bool) {
+	if node.ShortTag() == "!!null" {
+		return
+	}
+
+	if node.Kind != yamlv3.MappingNode {
+		v.add(SeverityError, IssueInvalidType, node, address, path, "Expected a map of usage keys to values")
+		return
+	}
+
+	itemMap := make(map[string]*schema.UsageItem, len(items))
+	for _, item := range items {
+		itemMap[item.Key] = item
+	}
+
+	siblings := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i < len(node.Content); i += 2 {
+		siblings[node.Content[i].Value] = true
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		if path != "" {
+			key = path + "." + keyNode.Value
+		}
+
+		item, ok := itemMap[keyNode.Value]
+		if !ok && items != nil && !strings.Contains(keyNode.Value, "[") {
+			v.add(SeverityWarning, IssueUnknownKey, keyNode, address, key,
+				fmt.Sprintf("Usage key %s is not used by %s and will be ignored", key, address))
+			continue
+		}
+
+		if (item == nil || item.ValueType != schema.SubResourceUsage) && isTimeSeriesNode(valNode) {
+			v.validateTimeSeries(address, key, valNode, item)
+			continue
+		}
+
+		if item != nil && item.ValueType == schema.SubResourceUsage {
+			var subItems []*schema.UsageItem
+			if ru, ok := item.DefaultValue.(*ResourceUsage); ok {
+				subItems = ru.Items
+			}
+			v.validateItems(address, key, valNode, subItems, exactTypes)
+			continue
+		}
+
+		if valNode.Kind == yamlv3.MappingNode && item == nil {
+			v.validateItems(address, key, valNode, nil, exactTypes)
+			continue
+		}
+
+		v.validateValue(address, key, valNode, item, siblings, exactTypes)
+	}
+}
+
+func (v *usageValidator) validateValue(address, key string, node *yamlv3.Node, item *schema.UsageItem, siblings map[string]bool, exactTypes bool) {
+	tag := node.ShortTag()
+
+	if tag == "!!null" {
+		return
+	}
+
+	if tag == "!!str" {
+		if _, ok := parseUsageExpression(key, node.Value, siblings); ok {
+			// Expressions are checked when the usage file is loaded.
+			return
+		}
+	}
+
+	if item != nil {
+		if msg := checkValueType(item.ValueType, node, exactTypes); msg != "" {
+			v.add(SeverityError, IssueInvalidType, node, address, key, fmt.Sprintf("Usage key %s %s", key, msg))
+			return
+		}
+	}
+
+	if tag != "!!int" && tag != "!!float" {
+		return
+	}
+
+	var f float64
+	if err := node.Decode(&f); err != nil {
+		return
+	}
+
+	if f < 0 {
+		v.add(SeverityError, IssueNegativeValue, node, address, key,
+			fmt.Sprintf("Usage key %s has a negative value %s", key, node.Value))
+		return
+	}
+
+	if msg := implausibleValue(key, f); msg != "" {
+		v.add(SeverityWarning, IssueImplausibleValue, node, address, key, msg)
+	}
+}
+
+// validateTimeSeries checks the start and series values of a time series
+// usage value. The rest of the time series is checked when the usage file is
+// loaded.
+func (v *usageValidator) validateTimeSeries(address, key string, node *yamlv3.Node, item *schema.UsageItem) {
+	if item != nil && item.ValueType != schema.Int64 && item.ValueType != schema.Float64 {
+		v.add(SeverityError, IssueInvalidType, node, address, key,
+			fmt.Sprintf("Usage key %s %s", key, checkValueType(item.ValueType, node, false)))
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case timeSeriesStart:
+			v.validateValue(address, key, node.Content[i+1], item, nil, false)
+		case timeSeriesSeries:
+			for _, valNode := range node.Content[i+1].Content {
+				v.validateValue(address, key, valNode, item, nil, false)
+			}
+		}
+	}
+}
+
+// checkValueType returns a message describing why the value does not match
+// the value type, or an empty string if it matches.
+func checkValueType(valueType schema.UsageVariableType, node *yamlv3.Node, exactTypes bool) string {
+	tag := node.ShortTag()
+
+	switch valueType {
+	case schema.Int64:
+		if tag == "!!int" {
+			return ""
+		}
+		if tag == "!!float" {
+			if !exactTypes {
+				return ""
+			}
+			var f float64
+			if err := node.Decode(&f); err == nil && f == math.Trunc(f) {
+				return ""
+			}
+			return fmt.Sprintf("must be a whole number, got %s", node.Value)
+		}
+		return fmt.Sprintf("must be a number, got %s", describeNode(node))
+	case schema.Float64:
+		if tag == "!!int" || tag == "!!float" {
+			return ""
+		}
+		return fmt.Sprintf("must be a number, got %s", describeNode(node))
+	case schema.String:
+		if node.Kind == yamlv3.ScalarNode {
+			return ""
+		}
+		return fmt.Sprintf("must be a string, got %s", describeNode(node))
+	case schema.StringArray:
+		if node.Kind == yamlv3.SequenceNode {
+			return ""
+		}
+		return fmt.Sprintf("must be a list of strings, got %s", describeNode(node))
+	case schema.SubResourceUsage:
+		if node.Kind == yamlv3.MappingNode {
+			return ""
+		}
+		return fmt.Sprintf("must be a map, got %s", describeNode(node))
+	}
+
+	return ""
+}
+
+func describeNode(node *yamlv3.Node) string {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		return "a map"
+	case yamlv3.SequenceNode:
+		return "a list"
+	}
+
+	return fmt.Sprintf("%q", node.Value)
+}
+
+// implausibleValue returns a message if the value is outside the range that
+// makes sense for the key, going by the key naming conventions used in the
+// usage schemas.
+func implausibleValue(key string, f float64) string {
+	name := key[strings.LastIndex(key, ".")+1:]
+
+	switch {
+	case strings.HasPrefix(name, "monthly_") && strings.HasSuffix(name, "_hours") && f > maxMonthlyHours:
+		return fmt.Sprintf("Usage key %s is %g which is more than the %d hours in a month", key, f, maxMonthlyHours)
+	case (strings.Contains(name, "percent") || strings.Contains(name, "utilization")) && f > 100:
+		return fmt.Sprintf("Usage key %s is %g which is more than 100%%", key, f)
+	}
+
+	return ""
+}
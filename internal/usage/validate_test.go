@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestValidate(t *testing.T) {
+	usageFile, err := LoadUsageFileFromString(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: 1000
+  aws_sqs_queue:
+    monthly_requests: 1000
+resource_usage:
+  aws_instance.web[0]:
+    operating_system: linux
+    monthly_cpu_credit_hrs: -10
+  aws_instance.web[3]:
+    operating_system: linux
+  aws_instance.web:
+    operating_system: linux
+  aws_nat_gateway.missing:
+    monthly_data_processed_gb: 10
+  aws_ebs_volume.disks[*]:
+    monthly_standard_io_requests: 10
+  aws_lambda_function.api:
+    monthly_requests: lots
+    request_duration_ms: 1.5
+    monthly_instance_hours: 800
+    unknown_key: 1
+    nested:
+      value: 1
+`)
+	require.NoError(t, err)
+
+	lambdaSchema := []*schema.UsageItem{
+		{Key: "monthly_requests", ValueType: schema.Int64},
+		{Key: "request_duration_ms", ValueType: schema.Int64},
+		{Key: "monthly_instance_hours", ValueType: schema.Float64},
+	}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web[0]", ResourceType: "aws_instance", UsageSchema: []*schema.UsageItem{
+			{Key: "operating_system", ValueType: schema.String},
+			{Key: "monthly_cpu_credit_hrs", ValueType: schema.Int64},
+		}},
+		{Name: "aws_instance.web[1]", ResourceType: "aws_instance"},
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function", UsageSchema: lambdaSchema},
+	}
+
+	result, err := usageFile.Validate("infracost-usage.yml", resources)
+	require.NoError(t, err)
+
+	type issue struct {
+		code string
+		line int
+		key  string
+	}
+
+	var actual []issue
+	for _, i := range result.Issues {
+		actual = append(actual, issue{i.Code, i.Line, i.Key})
+	}
+
+	assert.Equal(t, []issue{
+		{IssueUnknownResourceType, 5, ""},
+		{IssueNegativeValue, 10, "monthly_cpu_credit_hrs"},
+		{IssueStaleIndex, 11, ""},
+		{IssueUnknownAddress, 13, ""},
+		{IssueUnknownAddress, 15, ""},
+		{IssueUnmatchedWildcard, 17, ""},
+		{IssueInvalidType, 20, "monthly_requests"},
+		{IssueInvalidType, 21, "request_duration_ms"},
+		{IssueImplausibleValue, 22, "monthly_instance_hours"},
+		{IssueUnknownKey, 23, "unknown_key"},
+		{IssueUnknownKey, 24, "nested"},
+	}, actual)
+
+	assert.True(t, result.HasErrors(false))
+	assert.Contains(t, result.Issues[2].Message, "Found: aws_instance.web[0], aws_instance.web[1]")
+	assert.Contains(t, result.Issues[3].Message, "use aws_instance.web[*]")
+}
@@ -49,7 +49,7 @@ var (
 	versionRegxp     = regexp.MustCompile(`^v\d/`)
 
 	allowedProviders = map[string]struct{}{
-		"github": {}, "gitlab": {}, "azure_repos": {}, "bitbucket": {},
+		"github": {}, "gitlab": {}, "azure_repos": {}, "bitbucket": {}, "gitea": {},
 	}
 )
 
@@ -299,7 +299,18 @@ func (f *metadataFetcher) Get(path string, gitDiffTarget *string) (m Metadata, e
 		return StubMetadata, nil
 	}
 
-	v, ok := lookupEnv("GITHUB_ACTIONS")
+	// Gitea and Forgejo Actions also set GITHUB_ACTIONS so they need to be
+	// detected first.
+	v, ok := lookupEnv("GITEA_ACTIONS")
+	if !ok || v == "" {
+		v, ok = lookupEnv("FORGEJO_ACTIONS")
+	}
+	if ok && v != "" {
+		logging.Logger.Debug("fetching Gitea Actions VCS metadata")
+		return f.getGiteaActionsMetadata(path, gitDiffTarget)
+	}
+
+	v, ok = lookupEnv("GITHUB_ACTIONS")
 	if ok && v != "" {
 		logging.Logger.Debug("fetching GitHub action VCS metadata")
 		return f.getGithubMetadata(path, gitDiffTarget)
@@ -334,6 +345,12 @@ func (f *metadataFetcher) Get(path string, gitDiffTarget *string) (m Metadata, e
 		return f.getCircleCIMetadata(path, gitDiffTarget)
 	}
 
+	v, ok = lookupEnv("CI")
+	if ok && v == "woodpecker" {
+		logging.Logger.Debug("fetching Woodpecker CI VCS metadata")
+		return f.getWoodpeckerMetadata(path, gitDiffTarget)
+	}
+
 	ok = lookupEnvPrefix("ATLANTIS_")
 	if ok {
 		logging.Logger.Debug("fetching Atlantis VCS metadata")
@@ -497,6 +514,51 @@ func (f *metadataFetcher) getGithubMetadata(path string, gitDiffTarget *string)
 	return m, nil
 }
 
+// getGiteaActionsMetadata fetches the metadata of Gitea and Forgejo Actions,
+// which provide the same environment variables and event file as GitHub
+// Actions.
+func (f *metadataFetcher) getGiteaActionsMetadata(path string, gitDiffTarget *string) (Metadata, error) {
+	event, err := os.ReadFile(getEnv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("could not read the Gitea event file %w", err)
+	}
+
+	m, err := f.getLocalGitMetadata(path, gitDiffTarget)
+	if err != nil {
+		return m, fmt.Errorf("Gitea metadata error, could not fetch initial metadata from local git %w", err)
+	}
+
+	if m.Branch.Name == "HEAD" {
+		m.Branch.Name = getEnv("GITHUB_HEAD_REF")
+	}
+
+	m.Remote = urlStringToRemote(gjson.GetBytes(event, "repository.html_url").String())
+	m.Pipeline = &Pipeline{ID: getEnv("GITHUB_RUN_ID")}
+
+	if !gjson.GetBytes(event, "pull_request").Exists() {
+		return m, nil
+	}
+
+	l := gjson.GetBytes(event, "pull_request.labels.#.name").Array()
+	labels := make([]string, 0, len(l))
+	for _, v := range l {
+		labels = append(labels, v.String())
+	}
+
+	m.PullRequest = &PullRequest{
+		VCSProvider:  "gitea",
+		ID:           gjson.GetBytes(event, "pull_request.number").String(),
+		Title:        gjson.GetBytes(event, "pull_request.title").String(),
+		Author:       gjson.GetBytes(event, "pull_request.user.login").String(),
+		Labels:       labels,
+		SourceBranch: gjson.GetBytes(event, "pull_request.head.ref").String(),
+		BaseBranch:   gjson.GetBytes(event, "pull_request.base.ref").String(),
+		URL:          gjson.GetBytes(event, "pull_request.html_url").String(),
+	}
+
+	return m, nil
+}
+
 func (f *metadataFetcher) getLocalGitMetadata(path string, gitDiffTarget *string) (Metadata, error) {
 	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
 	if err != nil {
@@ -868,6 +930,47 @@ func (f *metadataFetcher) getCircleCIMetadata(path string, gitDiffTarget *string
 	return m, nil
 }
 
+func (f *metadataFetcher) getWoodpeckerMetadata(path string, gitDiffTarget *string) (Metadata, error) {
+	m, err := f.getLocalGitMetadata(path, gitDiffTarget)
+	if err != nil {
+		return m, fmt.Errorf("woodpecker CI metadata error, could not fetch initial metadata from local git %w", err)
+	}
+
+	if m.Branch.Name == "HEAD" {
+		m.Branch.Name = getEnv("CI_COMMIT_SOURCE_BRANCH")
+	}
+
+	m.Remote = urlStringToRemote(getEnv("CI_REPO_URL"))
+	m.Pipeline = &Pipeline{ID: getEnv("CI_PIPELINE_NUMBER")}
+
+	if getEnv("CI_COMMIT_PULL_REQUEST") == "" {
+		return m, nil
+	}
+
+	// Woodpecker supports several forges, Forgejo is a fork of Gitea so they're
+	// reported as the same provider.
+	provider := getEnv("CI_FORGE_TYPE")
+	if provider == "forgejo" {
+		provider = "gitea"
+	}
+
+	m.PullRequest = &PullRequest{
+		VCSProvider:  provider,
+		ID:           getEnv("CI_COMMIT_PULL_REQUEST"),
+		Author:       getEnv("CI_COMMIT_AUTHOR"),
+		Labels:       getEnvList("CI_COMMIT_PULL_REQUEST_LABELS"),
+		SourceBranch: getEnv("CI_COMMIT_SOURCE_BRANCH"),
+		BaseBranch:   getEnv("CI_COMMIT_TARGET_BRANCH"),
+		URL:          getEnv("CI_PIPELINE_FORGE_URL"),
+
+		// Woodpecker doesn't provide the title of the pull request without calling
+		// the API of the forge.
+		Title: "",
+	}
+
+	return m, nil
+}
+
 func (f *metadataFetcher) getAtlantisMetadata(path string, gitDiffTarget *string) (Metadata, error) {
 	m, err := f.getLocalGitMetadata(path, gitDiffTarget)
 	if err != nil {
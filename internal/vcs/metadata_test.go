@@ -221,6 +221,95 @@ func Test_metadataFetcher_GetLocalMetadataMergesWithEnv(t *testing.T) {
 	}, actual)
 }
 
+func Test_metadataFetcher_GetGiteaActionsMetadata(t *testing.T) {
+	tmp := t.TempDir()
+	createLocalRepoWithCommits(t, tmp)
+
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	err := os.WriteFile(eventPath, []byte(`{
+		"repository": {"html_url": "https://gitea.example.com/my-org/my-repo"},
+		"pull_request": {
+			"number": 3,
+			"title": "Add a database",
+			"html_url": "https://gitea.example.com/my-org/my-repo/pulls/3",
+			"user": {"login": "alice"},
+			"labels": [{"name": "infra"}, {"name": "cost"}],
+			"head": {"ref": "add-db"},
+			"base": {"ref": "main"}
+		}
+	}`), 0600)
+	require.NoError(t, err)
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITEA_ACTIONS", "true")
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+	t.Setenv("GITHUB_RUN_ID", "42")
+
+	test := false
+	m := metadataFetcher{
+		mu:     &sync.KeyMutex{},
+		client: &http.Client{Timeout: time.Second * 5},
+		test:   &test,
+	}
+
+	actual, err := m.Get(tmp, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "gitea.example.com", actual.Remote.Host)
+	assert.Equal(t, "my-org/my-repo", actual.Remote.Name)
+	assert.Equal(t, &Pipeline{ID: "42"}, actual.Pipeline)
+	assert.Equal(t, &PullRequest{
+		VCSProvider:  "gitea",
+		ID:           "3",
+		Title:        "Add a database",
+		Author:       "alice",
+		Labels:       []string{"infra", "cost"},
+		SourceBranch: "add-db",
+		BaseBranch:   "main",
+		URL:          "https://gitea.example.com/my-org/my-repo/pulls/3",
+	}, actual.PullRequest)
+}
+
+func Test_metadataFetcher_GetWoodpeckerMetadata(t *testing.T) {
+	tmp := t.TempDir()
+	createLocalRepoWithCommits(t, tmp)
+
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("CI", "woodpecker")
+	t.Setenv("CI_FORGE_TYPE", "forgejo")
+	t.Setenv("CI_REPO_URL", "https://forgejo.example.com/my-org/my-repo")
+	t.Setenv("CI_PIPELINE_NUMBER", "7")
+	t.Setenv("CI_PIPELINE_FORGE_URL", "https://forgejo.example.com/my-org/my-repo/pulls/3")
+	t.Setenv("CI_COMMIT_PULL_REQUEST", "3")
+	t.Setenv("CI_COMMIT_PULL_REQUEST_LABELS", "infra,cost")
+	t.Setenv("CI_COMMIT_AUTHOR", "alice")
+	t.Setenv("CI_COMMIT_SOURCE_BRANCH", "add-db")
+	t.Setenv("CI_COMMIT_TARGET_BRANCH", "main")
+
+	test := false
+	m := metadataFetcher{
+		mu:     &sync.KeyMutex{},
+		client: &http.Client{Timeout: time.Second * 5},
+		test:   &test,
+	}
+
+	actual, err := m.Get(tmp, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "forgejo.example.com", actual.Remote.Host)
+	assert.Equal(t, "my-org/my-repo", actual.Remote.Name)
+	assert.Equal(t, &Pipeline{ID: "7"}, actual.Pipeline)
+	assert.Equal(t, &PullRequest{
+		VCSProvider:  "gitea",
+		ID:           "3",
+		Author:       "alice",
+		Labels:       []string{"infra", "cost"},
+		SourceBranch: "add-db",
+		BaseBranch:   "main",
+		URL:          "https://forgejo.example.com/my-org/my-repo/pulls/3",
+	}, actual.PullRequest)
+}
+
 func createLocalRepoWithCommits(t *testing.T, tmp string) (*git.Repository, *object.Commit) {
 	t.Helper()
 	r, err := git.PlainInit(tmp, false)